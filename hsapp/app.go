@@ -17,14 +17,18 @@ import (
 	"github.com/OpenDiablo2/HellSpawner/abysswrapper"
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
 	"github.com/OpenDiablo2/HellSpawner/hsconfig"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hsaboutdialog"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hsfontimportdialog"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hspreferencesdialog"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hsprojectpropertiesdialog"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor/hsdt1editor"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hsconsole"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hsmpqexplorer"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hsprojectexplorer"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hstilesetbrowser"
 )
 
 const (
@@ -38,6 +42,8 @@ const (
 	mpqExplorerDefaultY      = 30
 	consoleDefaultX          = 10
 	consoleDefaultY          = 500
+	tilesetBrowserDefaultX   = 60
+	tilesetBrowserDefaultY   = 60
 
 	samplesPerSecond = 22050
 	sampleDuration   = time.Second / 10
@@ -78,13 +84,17 @@ type App struct {
 	aboutDialog             *hsaboutdialog.AboutDialog
 	preferencesDialog       *hspreferencesdialog.PreferencesDialog
 	projectPropertiesDialog *hsprojectpropertiesdialog.ProjectPropertiesDialog
+	fontImportDialog        *hsfontimportdialog.FontImportDialog
 
 	projectExplorer *hsprojectexplorer.ProjectExplorer
 	mpqExplorer     *hsmpqexplorer.MPQExplorer
 	console         *hsconsole.Console
+	tilesetBrowser  *hstilesetbrowser.TilesetBrowser
 
 	editors            []hscommon.EditorWindow
 	editorConstructors map[hsfiletypes.FileType]editorConstructor
+	// map regions copied in DS1 editors
+	ds1Clipboard *hsds1.Clipboard
 
 	editorManagerMutex sync.RWMutex
 	focusedEditor      hscommon.EditorWindow
@@ -105,6 +115,7 @@ func Create() (*App, error) {
 		Flags:              &Flags{},
 		editors:            make([]hscommon.EditorWindow, 0),
 		editorConstructors: make(map[hsfiletypes.FileType]editorConstructor),
+		ds1Clipboard:       &hsds1.Clipboard{},
 		TextureLoader:      hscommon.NewTextureLoader(),
 		abyssWrapper:       abysswrapper.Create(),
 	}
@@ -113,6 +124,10 @@ func Create() (*App, error) {
 		return nil, nil
 	}
 
+	if *result.Flags.convertPath != "" {
+		return nil, convert(*result.Flags.convertPath, *result.Flags.outputPath, *result.Flags.palettePath)
+	}
+
 	result.config = hsconfig.Load(*result.Flags.optionalConfigPath)
 
 	return result, nil
@@ -243,6 +258,42 @@ func (a *App) openEditor(path *hscommon.PathEntry) {
 	a.editorManagerMutex.Unlock()
 }
 
+// isFileOpen returns true if project's file (absolute path) is open in an editor
+func (a *App) isFileOpen(path string) bool {
+	entry := &hscommon.PathEntry{FullPath: filepath.Clean(path), Source: hscommon.PathEntrySourceProject}
+	uniqueID := entry.GetUniqueID()
+
+	a.editorManagerMutex.RLock()
+	defer a.editorManagerMutex.RUnlock()
+
+	for _, editor := range a.editors {
+		if editor.GetID() == uniqueID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// openTile opens DT1 editor at tile group given
+func (a *App) openTile(path *hscommon.PathEntry, tileGroup int32) {
+	a.openEditor(path)
+
+	a.editorManagerMutex.RLock()
+	defer a.editorManagerMutex.RUnlock()
+
+	uniqueID := path.GetUniqueID()
+	for idx := range a.editors {
+		if a.editors[idx].GetID() != uniqueID {
+			continue
+		}
+
+		if editor, ok := a.editors[idx].(*hsdt1editor.DT1Editor); ok {
+			editor.SetTileGroup(tileGroup)
+		}
+	}
+}
+
 func (a *App) loadProjectFromFile(file string) error {
 	project, err := hsproject.LoadFromFile(file)
 	if err != nil {
@@ -254,6 +305,8 @@ func (a *App) loadProjectFromFile(file string) error {
 		return fmt.Errorf("could not validate aux mpq's, %w", err)
 	}
 
+	project.SetFileOpenChecker(a.isFileOpen)
+
 	a.project = project
 	a.config.AddToRecentProjects(file)
 	a.updateWindowTitle()
@@ -265,6 +318,7 @@ func (a *App) loadProjectFromFile(file string) error {
 
 	a.projectExplorer.SetProject(a.project)
 	a.mpqExplorer.SetProject(a.project)
+	a.tilesetBrowser.SetProject(a.project)
 
 	a.CloseAllOpenWindows()
 
@@ -291,6 +345,10 @@ func (a *App) toggleMPQExplorer() {
 	a.mpqExplorer.ToggleVisibility()
 }
 
+func (a *App) toggleTilesetBrowser() {
+	a.tilesetBrowser.ToggleVisibility()
+}
+
 func (a *App) onProjectPropertiesChanged(project *hsproject.Project) {
 	a.project = project
 	if err := a.project.Save(); err != nil {
@@ -298,6 +356,7 @@ func (a *App) onProjectPropertiesChanged(project *hsproject.Project) {
 	}
 
 	a.mpqExplorer.SetProject(a.project)
+	a.tilesetBrowser.SetProject(a.project)
 	a.updateWindowTitle()
 
 	if err := a.reloadAuxiliaryMPQs(); err != nil {
@@ -326,6 +385,7 @@ func (a *App) reloadAuxiliaryMPQs() error {
 	}
 
 	a.mpqExplorer.Reset()
+	a.tilesetBrowser.Reset()
 
 	return nil
 }
@@ -348,6 +408,7 @@ func (a *App) closePopups() {
 	a.projectPropertiesDialog.Cleanup()
 	a.aboutDialog.Cleanup()
 	a.preferencesDialog.Cleanup()
+	a.fontImportDialog.Cleanup()
 }
 
 func (a *App) toggleConsole() {
@@ -359,6 +420,7 @@ func (a *App) CloseAllOpenWindows() {
 	a.closePopups()
 	a.projectExplorer.Cleanup()
 	a.mpqExplorer.Cleanup()
+	a.tilesetBrowser.Cleanup()
 	a.focusedEditor = nil
 
 	for _, editor := range a.editors {
@@ -25,6 +25,7 @@ func (a *App) State() hsstate.AppState {
 		a.mpqExplorer.State(),
 		a.projectExplorer.State(),
 		a.console.State(),
+		a.tilesetBrowser.State(),
 	)
 
 	return appState
@@ -42,6 +43,8 @@ func (a *App) RestoreAppState(state hsstate.AppState) {
 			tool = a.mpqExplorer
 		case hsstate.ToolWindowTypeProjectExplorer:
 			tool = a.projectExplorer
+		case hsstate.ToolWindowTypeTilesetBrowser:
+			tool = a.tilesetBrowser
 		default:
 			continue
 		}
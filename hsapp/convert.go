@@ -0,0 +1,107 @@
+package hsapp
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dcc"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdcc"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+)
+
+const (
+	convertedFilePerms = 0o644
+	extDC6             = ".dc6"
+	extDCC             = ".dcc"
+)
+
+// convert converts DC6 sprite into DCC animation (or DCC into DC6, depending on input's extension);
+// anything DCC can't represent is logged
+func convert(inputPath, outputPath, palettePath string) error {
+	data, err := ioutil.ReadFile(filepath.Clean(inputPath))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", inputPath, err)
+	}
+
+	var (
+		result    []byte
+		targetExt string
+	)
+
+	switch strings.ToLower(filepath.Ext(inputPath)) {
+	case extDC6:
+		result, err = convertToDCC(data, palettePath)
+		targetExt = extDCC
+	case extDCC:
+		result, err = convertToDC6(data)
+		targetExt = extDC6
+	default:
+		return fmt.Errorf("can't convert %s: only DC6 and DCC files are supported", inputPath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(inputPath, filepath.Ext(inputPath)) + targetExt
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(outputPath), result, os.FileMode(convertedFilePerms)); err != nil {
+		return fmt.Errorf("error writing %s: %w", outputPath, err)
+	}
+
+	log.Printf("%s converted to %s", inputPath, outputPath)
+
+	return nil
+}
+
+func convertToDCC(data []byte, palettePath string) ([]byte, error) {
+	sprite, err := d2dc6.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("error loading DC6: %w", err)
+	}
+
+	var palette *[256]color.RGBA
+
+	if palettePath != "" {
+		colors, _, err := hspalette.LoadFile(palettePath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading palette: %w", err)
+		}
+
+		palette = &colors
+	}
+
+	result, warnings, err := hsdcc.FromDC6(sprite, palette)
+	if err != nil {
+		return nil, fmt.Errorf("error converting DC6 to DCC: %w", err)
+	}
+
+	for _, warning := range warnings {
+		log.Printf("warning: %s", warning)
+	}
+
+	return result, nil
+}
+
+func convertToDC6(data []byte) ([]byte, error) {
+	dcc, err := d2dcc.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("error loading DCC: %w", err)
+	}
+
+	sprite, err := hsdcc.ToDC6(dcc)
+	if err != nil {
+		return nil, fmt.Errorf("error converting DCC to DC6: %w", err)
+	}
+
+	return sprite.Marshal(), nil
+}
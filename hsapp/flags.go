@@ -14,6 +14,9 @@ type Flags struct {
 	optionalConfigPath *string
 	bgColor            *string
 	logFile            *string
+	convertPath        *string
+	outputPath         *string
+	palettePath        *string
 }
 
 // parse all of the command line args
@@ -21,6 +24,7 @@ func (a *App) parseArgs() (shouldTerminate bool) {
 	a.parseConfigArgs()
 	a.parseLogFileArgs()
 	a.parseBackgroundColorArgs()
+	a.parseConvertArgs()
 
 	// help args need to be parsed last, so that all other args
 	// can be parsed before a possible os.Exit() invoked by `-h` or `--help`
@@ -83,3 +87,18 @@ func (a *App) parseLogFileArgs() {
 
 	a.Flags.logFile = flag.String(name, "", desc)
 }
+
+func (a *App) parseConvertArgs() {
+	const (
+		convertName = "convert"
+		convertDesc = "convert DC6 sprite to DCC animation (or vice versa) and exit."
+		outputName  = "output"
+		outputDesc  = "path to the converted file.\nDefault is the input path with the other extension"
+		paletteName = "palette"
+		paletteDesc = "palette (DAT or PL2) used to reduce colors when converting to DCC."
+	)
+
+	a.Flags.convertPath = flag.String(convertName, "", convertDesc)
+	a.Flags.outputPath = flag.String(outputName, "", outputDesc)
+	a.Flags.palettePath = flag.String(paletteName, "", paletteDesc)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/browser"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsfont"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
 )
 
@@ -122,6 +123,11 @@ func (a *App) viewMenu() *g.MenuWidget {
 			Enabled(hasProject).
 			OnClick(a.toggleMPQExplorer),
 
+		g.MenuItem("Tileset Browser").
+			Selected(a.tilesetBrowser.Visible && hasProject).
+			Enabled(hasProject).
+			OnClick(a.toggleTilesetBrowser),
+
 		g.MenuItem("Console\t\t\t\t\tCtrl+Shift+C").
 			Selected(a.console.Visible).
 			OnClick(a.toggleConsole),
@@ -171,11 +177,21 @@ func (a *App) projectMenu() *g.MenuWidget {
 		Enabled(projectOpened).
 		OnClick(a.onProjectExportMPQClicked)
 
+	projectMenuCompileExcel := menuItem("MainMenuProject", "Compile Excel Tables", "").
+		Enabled(projectOpened).
+		OnClick(a.onProjectCompileExcelTablesClicked)
+
+	projectMenuImportFont := menuItem("MainMenuProject", "Import Font...", "").
+		Enabled(projectOpened).
+		OnClick(a.onProjectImportFontClicked)
+
 	return projectMenu.Layout(
 		projectMenuRun,
 		g.Separator(),
 		projectMenuProperties,
 		g.Separator(),
+		projectMenuImportFont,
+		projectMenuCompileExcel,
 		projectMenuExportMPQ,
 	)
 }
@@ -270,6 +286,7 @@ func (a *App) onCloseProjectClicked() {
 
 	a.projectExplorer.SetProject(nil)
 	a.mpqExplorer.SetProject(nil)
+	a.tilesetBrowser.SetProject(nil)
 	a.CloseAllOpenWindows()
 	a.updateWindowTitle()
 }
@@ -297,6 +314,32 @@ func (a *App) onProjectRunClicked() {
 func (a *App) onProjectExportMPQClicked() {
 }
 
+func (a *App) onProjectCompileExcelTablesClicked() {
+	compiled, err := a.project.CompileExcelTables()
+	for _, path := range compiled {
+		log.Printf("compiled %s", path)
+	}
+
+	if err != nil {
+		logErr("could not compile excel tables, %s", err)
+		return
+	}
+
+	log.Printf("%d excel table(s) compiled", len(compiled))
+}
+
+func (a *App) onProjectImportFontClicked() {
+	a.fontImportDialog.Show(a.project.GetProjectFileContentPath())
+}
+
+func (a *App) onFontImported(font *hsfont.Font) {
+	log.Printf("font imported: %s, %s", font.SpriteFile, font.TableFile)
+
+	if a.project != nil {
+		a.project.InvalidateFileStructure()
+	}
+}
+
 // NOTE: some characters in URLs cannot be dirrectly written, because they have
 // another meaning (e.g. #). Instead we need to use ASCII code (for # %23).
 // for ascii codes see https://www.w3schools.com/tags/ref_urlencode.ASP
@@ -390,10 +433,12 @@ func (a *App) renderWindows() {
 	windows := []hscommon.Renderable{
 		a.projectExplorer,
 		a.mpqExplorer,
+		a.tilesetBrowser,
 		a.console,
 		a.preferencesDialog,
 		a.aboutDialog,
 		a.projectPropertiesDialog,
+		a.fontImportDialog,
 	}
 
 	for _, tw := range windows {
@@ -26,9 +26,11 @@ import (
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hsaboutdialog"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hsfontimportdialog"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hspreferencesdialog"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog/hsprojectpropertiesdialog"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor/hsanimdataeditor"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor/hsbineditor"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor/hscofeditor"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor/hsdc6editor"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor/hsdcceditor"
@@ -39,6 +41,7 @@ import (
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hsconsole"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hsmpqexplorer"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hsprojectexplorer"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow/hstilesetbrowser"
 )
 
 func (a *App) setup() (err error) {
@@ -65,6 +68,8 @@ func (a *App) setup() (err error) {
 		return err
 	}
 
+	a.setupTilesetBrowser()
+
 	err = a.setupDialogs()
 	if err != nil {
 		return err
@@ -136,7 +141,8 @@ func (a *App) registerEditors() {
 	a.editorConstructors[hsfiletypes.FileTypePL2] = hspalettemapeditor.Create
 	a.editorConstructors[hsfiletypes.FileTypeTBLStringTable] = hsstringtableeditor.Create
 	a.editorConstructors[hsfiletypes.FileTypeTBLFontTable] = hsfonttableeditor.Create
-	a.editorConstructors[hsfiletypes.FileTypeDS1] = hsds1editor.Create
+	a.editorConstructors[hsfiletypes.FileTypeDS1] = hsds1editor.Creator(a.ds1Clipboard)
+	a.editorConstructors[hsfiletypes.FileTypeBIN] = hsbineditor.Create
 }
 
 func (a *App) setupMainMpqExplorer() error {
@@ -164,6 +170,12 @@ func (a *App) setupProjectExplorer() error {
 	return nil
 }
 
+func (a *App) setupTilesetBrowser() {
+	x, y := float32(tilesetBrowserDefaultX), float32(tilesetBrowserDefaultY)
+
+	a.tilesetBrowser = hstilesetbrowser.Create(a.TextureLoader, a.openTile, a.config, x, y)
+}
+
 func (a *App) setupAudio() error {
 	sampleRate := beep.SampleRate(samplesPerSecond)
 	bufferSize := sampleRate.N(sampleDuration)
@@ -198,6 +210,7 @@ func (a *App) setupDialogs() error {
 	a.aboutDialog = about
 	a.projectPropertiesDialog = hsprojectpropertiesdialog.Create(a.TextureLoader, a.onProjectPropertiesChanged)
 	a.preferencesDialog = hspreferencesdialog.Create(a.onPreferencesChanged, a.masterWindow.SetBgColor)
+	a.fontImportDialog = hsfontimportdialog.Create(a.onFontImported)
 
 	return nil
 }
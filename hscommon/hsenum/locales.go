@@ -19,3 +19,19 @@ const (
 	LocaleItalien                          // Italien
 	LocalePolish                           // Polish
 )
+
+// Code returns a name of locale's directory used by the game (data\local\lng\<code>)
+func (i Locale) Code() string {
+	table := map[Locale]string{
+		LocaleEnglish:            "eng",
+		LocaleGerman:             "deu",
+		LocaleFrench:             "fra",
+		LocaleKorean:             "kor",
+		LocaleChineseTraditional: "chi",
+		LocaleSpanish:            "esp",
+		LocaleItalien:            "ita",
+		LocalePolish:             "pol",
+	}
+
+	return table[i]
+}
@@ -27,6 +27,7 @@ const (
 	FileTypeTBLFontTable
 	FileTypeDS1
 	FileTypeAnimationData
+	FileTypeBIN
 	numFileTypes
 )
 
@@ -61,6 +62,7 @@ func (f FileType) String() string {
 		FileTypeText:           "text file",
 		FileTypeDS1:            "DS1 Map Stamp",
 		FileTypeAnimationData:  "Animation Dataset",
+		FileTypeBIN:            "Compiled Excel Table",
 	}
 
 	val, found := table[f]
@@ -87,6 +89,7 @@ func (f FileType) FileExtension() string {
 		FileTypeText:           ".txt",
 		FileTypeDS1:            ".ds1",
 		FileTypeAnimationData:  ".d2",
+		FileTypeBIN:            ".bin",
 	}
 
 	return table[f]
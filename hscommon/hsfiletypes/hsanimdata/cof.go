@@ -0,0 +1,143 @@
+package hsanimdata
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2animdata"
+)
+
+// COFInfo describes a COF file, which should have an animation data record
+type COFInfo struct {
+	// Name is a name of record (file name of COF without extension, e.g. AMA1HTH)
+	Name string
+	// Source is a path of COF (in project or MPQ)
+	Source             string
+	FramesPerDirection int
+	Speed              int
+	// Error describes why COF couldn't be loaded; such COFs are reported as unreadable
+	Error string
+}
+
+// Mismatch is a record, which frames per direction differ from its COF
+type Mismatch struct {
+	Name string
+	// Record is a frames per direction value of record
+	Record int
+	COF    COFInfo
+}
+
+// COFReport is a result of validating animation data against COF files
+type COFReport struct {
+	// Missing are COFs without animation data record
+	Missing []COFInfo
+	// Mismatched are records, which frame count doesn't match their COF
+	Mismatched []Mismatch
+	// Orphaned are names of records, which don't match any COF
+	Orphaned []string
+	// Unreadable are COFs, which couldn't be loaded; their records aren't checked
+	Unreadable []COFInfo
+}
+
+// Empty returns true if animation data matches COFs
+func (r *COFReport) Empty() bool {
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0 && len(r.Orphaned) == 0 && len(r.Unreadable) == 0
+}
+
+// ValidateCOFs compares records with COFs given
+func ValidateCOFs(records []Record, cofs []COFInfo) *COFReport {
+	result := &COFReport{
+		Missing:    make([]COFInfo, 0),
+		Mismatched: make([]Mismatch, 0),
+		Orphaned:   make([]string, 0),
+		Unreadable: make([]COFInfo, 0),
+	}
+
+	byName := groupByName(records)
+	cofsByName := make(map[string]COFInfo, len(cofs))
+
+	for _, cof := range cofs {
+		cofsByName[cof.Name] = cof
+
+		if cof.Error != "" {
+			result.Unreadable = append(result.Unreadable, cof)
+
+			continue
+		}
+
+		entry, found := byName[cof.Name]
+		if !found {
+			result.Missing = append(result.Missing, cof)
+
+			continue
+		}
+
+		for idx := range entry {
+			if int(entry[idx].FramesPerDirection) != cof.FramesPerDirection {
+				result.Mismatched = append(result.Mismatched, Mismatch{
+					Name:   cof.Name,
+					Record: int(entry[idx].FramesPerDirection),
+					COF:    cof,
+				})
+			}
+		}
+	}
+
+	for name := range byName {
+		if _, found := cofsByName[name]; !found {
+			result.Orphaned = append(result.Orphaned, name)
+		}
+	}
+
+	sort.Slice(result.Missing, func(i, j int) bool { return result.Missing[i].Name < result.Missing[j].Name })
+	sort.Slice(result.Mismatched, func(i, j int) bool { return result.Mismatched[i].Name < result.Mismatched[j].Name })
+	sort.Strings(result.Orphaned)
+	sort.Slice(result.Unreadable, func(i, j int) bool { return result.Unreadable[i].Source < result.Unreadable[j].Source })
+
+	return result
+}
+
+// Fix creates records for missing COFs and corrects frame counts of mismatched records;
+// records without COF are removed if removeOrphaned is true
+func (r *COFReport) Fix(records []Record, removeOrphaned bool) ([]Record, error) {
+	orphaned := make(map[string]bool, len(r.Orphaned))
+	for _, name := range r.Orphaned {
+		orphaned[name] = true
+	}
+
+	fixes := make(map[string]int)
+	for _, m := range r.Mismatched {
+		fixes[m.Name] = m.COF.FramesPerDirection
+	}
+
+	result := make([]Record, 0, len(records)+len(r.Missing))
+
+	for _, record := range records {
+		if removeOrphaned && orphaned[record.Name] {
+			continue
+		}
+
+		if fpd, found := fixes[record.Name]; found {
+			record.FramesPerDirection = uint32(fpd)
+		}
+
+		result = append(result, record)
+	}
+
+	for _, cof := range r.Missing {
+		if cof.FramesPerDirection < 0 || cof.Speed < 0 || cof.Speed > 0xffff {
+			return nil, fmt.Errorf("%s: invalid COF values", cof.Source)
+		}
+
+		result = append(result, Record{
+			Name:               cof.Name,
+			FramesPerDirection: uint32(cof.FramesPerDirection),
+			Speed:              uint16(cof.Speed),
+			Events:             make(map[int]d2animdata.AnimationEvent),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
@@ -0,0 +1,7 @@
+// Package hsanimdata contains helpers for animation data (data\global\AnimData.d2):
+// a plain representation of records, which can be exported to and imported from
+// CSV/JSON, merged and encoded back into d2animdata.AnimationData.
+//
+// Records are encoded into hash blocks the game uses to look them up
+// (the block of record is the sum of its upper-case name's bytes modulo 256).
+package hsanimdata
@@ -0,0 +1,324 @@
+package hsanimdata
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2animdata"
+)
+
+const (
+	csvNameColumn   = "name"
+	csvFramesColumn = "frames per direction"
+	csvSpeedColumn  = "speed"
+	csvEventsColumn = "events"
+
+	// eventSeparator separates events in csv cell, e.g. "5:attack;9:sound"
+	eventSeparator      = ";"
+	eventFrameSeparator = ":"
+)
+
+// Format represents a format of exported animation data
+type Format int
+
+// formats
+const (
+	FormatCSV Format = iota
+	FormatJSON
+)
+
+// FormatFromExtension returns format of file with extension given (e.g. ".csv")
+func FormatFromExtension(ext string) (Format, error) {
+	for _, f := range []Format{FormatCSV, FormatJSON} {
+		if strings.EqualFold(f.Extension(), ext) {
+			return f, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unsupported file extension %q (supported are .csv and .json)", ext)
+}
+
+// Extension returns format's file extension
+func (f Format) Extension() string {
+	table := map[Format]string{
+		FormatCSV:  ".csv",
+		FormatJSON: ".json",
+	}
+
+	return table[f]
+}
+
+// jsonRecord is a record as it is stored in json
+type jsonRecord struct {
+	Name               string         `json:"name"`
+	FramesPerDirection uint32         `json:"framesPerDirection"`
+	Speed              uint16         `json:"speed"`
+	Events             map[int]string `json:"events,omitempty"`
+}
+
+// Export encodes records into format given
+func Export(records []Record, f Format) ([]byte, error) {
+	switch f {
+	case FormatCSV:
+		return exportCSV(records)
+	case FormatJSON:
+		return exportJSON(records)
+	}
+
+	return nil, fmt.Errorf("unknown format %d", f)
+}
+
+// Import decodes records from format given
+func Import(data []byte, f Format) ([]Record, error) {
+	// some editors (e.g. spreadsheets) put UTF-8 byte order mark at the beginning of file
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	var (
+		records []Record
+		err     error
+	)
+
+	switch f {
+	case FormatCSV:
+		records, err = importCSV(data)
+	case FormatJSON:
+		records, err = importJSON(data)
+	default:
+		return nil, fmt.Errorf("unknown format %d", f)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range records {
+		if err := records[idx].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+func sortedFrames(events map[int]d2animdata.AnimationEvent) []int {
+	frames := make([]int, 0, len(events))
+
+	for frame, event := range events {
+		if event != d2animdata.AnimationEventNone {
+			frames = append(frames, frame)
+		}
+	}
+
+	sort.Ints(frames)
+
+	return frames
+}
+
+// formatEvents formats events as "frame:event" pairs
+func formatEvents(events map[int]d2animdata.AnimationEvent) string {
+	frames := sortedFrames(events)
+	parts := make([]string, len(frames))
+
+	for idx, frame := range frames {
+		parts[idx] = strconv.Itoa(frame) + eventFrameSeparator + EventName(events[frame])
+	}
+
+	return strings.Join(parts, eventSeparator)
+}
+
+// parseEvents parses events formatted by formatEvents
+func parseEvents(s string) (map[int]d2animdata.AnimationEvent, error) {
+	result := make(map[int]d2animdata.AnimationEvent)
+
+	for _, part := range strings.Split(s, eventSeparator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pair := strings.SplitN(part, eventFrameSeparator, 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid event %q (expected frame%sevent)", part, eventFrameSeparator)
+		}
+
+		frame, err := strconv.Atoi(strings.TrimSpace(pair[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid event frame %q: %w", pair[0], err)
+		}
+
+		event, err := EventFromName(strings.TrimSpace(pair[1]))
+		if err != nil {
+			return nil, err
+		}
+
+		result[frame] = event
+	}
+
+	return result, nil
+}
+
+func exportCSV(records []Record) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+
+	if err := w.Write([]string{csvNameColumn, csvFramesColumn, csvSpeedColumn, csvEventsColumn}); err != nil {
+		return nil, fmt.Errorf("error writing csv header: %w", err)
+	}
+
+	for idx := range records {
+		r := &records[idx]
+
+		line := []string{
+			r.Name,
+			strconv.Itoa(int(r.FramesPerDirection)),
+			strconv.Itoa(int(r.Speed)),
+			formatEvents(r.Events),
+		}
+
+		if err := w.Write(line); err != nil {
+			return nil, fmt.Errorf("error writing csv record: %w", err)
+		}
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("error writing csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func importCSV(data []byte) ([]Record, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	columns := map[string]int{csvNameColumn: -1, csvFramesColumn: -1, csvSpeedColumn: -1, csvEventsColumn: -1}
+
+	for idx := range header {
+		name := strings.ToLower(strings.TrimSpace(header[idx]))
+		if _, known := columns[name]; known {
+			columns[name] = idx
+		}
+	}
+
+	for name, idx := range columns {
+		// events column is optional
+		if idx < 0 && name != csvEventsColumn {
+			return nil, fmt.Errorf("csv file should have %q column", name)
+		}
+	}
+
+	result := make([]Record, 0)
+
+	for line := 2; ; line++ {
+		cells, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading csv record: %w", err)
+		}
+
+		cell := func(column string) string {
+			if idx := columns[column]; idx >= 0 && idx < len(cells) {
+				return strings.TrimSpace(cells[idx])
+			}
+
+			return ""
+		}
+
+		frames, err := strconv.ParseUint(cell(csvFramesColumn), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: invalid frames per direction: %w", line, err)
+		}
+
+		speed, err := strconv.ParseUint(cell(csvSpeedColumn), 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: invalid speed: %w", line, err)
+		}
+
+		events, err := parseEvents(cell(csvEventsColumn))
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+
+		result = append(result, Record{
+			Name:               strings.ToUpper(cell(csvNameColumn)),
+			FramesPerDirection: uint32(frames),
+			Speed:              uint16(speed),
+			Events:             events,
+		})
+	}
+
+	return result, nil
+}
+
+func exportJSON(records []Record) ([]byte, error) {
+	encoded := make([]jsonRecord, len(records))
+
+	for idx := range records {
+		r := &records[idx]
+
+		encoded[idx] = jsonRecord{
+			Name:               r.Name,
+			FramesPerDirection: r.FramesPerDirection,
+			Speed:              r.Speed,
+			Events:             make(map[int]string),
+		}
+
+		for _, frame := range sortedFrames(r.Events) {
+			encoded[idx].Events[frame] = EventName(r.Events[frame])
+		}
+	}
+
+	data, err := json.MarshalIndent(encoded, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding json: %w", err)
+	}
+
+	return data, nil
+}
+
+func importJSON(data []byte) ([]Record, error) {
+	var decoded []jsonRecord
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("error decoding json: %w", err)
+	}
+
+	result := make([]Record, len(decoded))
+
+	for idx := range decoded {
+		r := &decoded[idx]
+
+		result[idx] = Record{
+			Name:               strings.ToUpper(r.Name),
+			FramesPerDirection: r.FramesPerDirection,
+			Speed:              r.Speed,
+			Events:             make(map[int]d2animdata.AnimationEvent),
+		}
+
+		for frame, name := range r.Events {
+			event, err := EventFromName(name)
+			if err != nil {
+				return nil, fmt.Errorf("record %s: %w", r.Name, err)
+			}
+
+			result[idx].Events[frame] = event
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package hsanimdata
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2animdata"
+)
+
+func testRecords() []Record {
+	return []Record{
+		{Name: "AAA1HS", FramesPerDirection: 8, Speed: 256, Events: map[int]d2animdata.AnimationEvent{
+			3: d2animdata.AnimationEventAttack,
+		}},
+		{Name: "BABLHTH", FramesPerDirection: 12, Speed: 128, Events: map[int]d2animdata.AnimationEvent{}},
+		{Name: "BABLHTH", FramesPerDirection: 13, Speed: 128, Events: map[int]d2animdata.AnimationEvent{
+			0:  d2animdata.AnimationEventSound,
+			12: d2animdata.AnimationEventSkill,
+		}},
+	}
+}
+
+func equal(t *testing.T, expected, got []Record) {
+	t.Helper()
+
+	if len(expected) != len(got) {
+		t.Fatalf("expected %d records, got %d", len(expected), len(got))
+	}
+
+	for idx := range expected {
+		if !expected[idx].Equal(&got[idx]) {
+			t.Fatalf("record %d: expected %+v, got %+v", idx, expected[idx], got[idx])
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	records := testRecords()
+
+	ad, err := Build(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, records, Records(ad))
+}
+
+func TestExportImport(t *testing.T) {
+	records := testRecords()
+
+	for _, f := range []Format{FormatCSV, FormatJSON} {
+		data, err := Export(records, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		imported, err := Import(data, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		equal(t, records, imported)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	current := testRecords()
+	imported := []Record{
+		{Name: "AAA1HS", FramesPerDirection: 8, Speed: 200},
+		{Name: "NEW", FramesPerDirection: 1, Speed: 256},
+	}
+
+	m := NewMerge(current, imported)
+
+	if len(m.Added) != 1 || m.Added[0] != "NEW" {
+		t.Fatalf("unexpected added entries %v", m.Added)
+	}
+
+	if len(m.Conflicts) != 1 || m.Conflicts[0] != "AAA1HS" {
+		t.Fatalf("unexpected conflicts %v", m.Conflicts)
+	}
+
+	if kept := m.Records(false); len(kept) != 4 || kept[0].Speed != 256 {
+		t.Fatalf("unexpected merge result %+v", kept)
+	}
+
+	if overwritten := m.Records(true); len(overwritten) != 4 || overwritten[0].Speed != 200 {
+		t.Fatalf("unexpected merge result %+v", overwritten)
+	}
+}
+
+func TestValidateCOFs(t *testing.T) {
+	cofs := []COFInfo{
+		{Name: "AAA1HS", FramesPerDirection: 8, Speed: 256},
+		{Name: "BABLHTH", FramesPerDirection: 13, Speed: 128},
+		{Name: "CCNUHTH", FramesPerDirection: 6, Speed: 256},
+	}
+
+	report := ValidateCOFs(testRecords(), cofs)
+	if len(report.Missing) != 1 || len(report.Mismatched) != 1 || len(report.Orphaned) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	// records of COFs, which can't be loaded, aren't orphaned
+	unreadable := append([]COFInfo{{Name: "AAA1HS", Error: "invalid COF"}}, cofs[1:]...)
+
+	report = ValidateCOFs(testRecords(), unreadable)
+	if len(report.Unreadable) != 1 || len(report.Orphaned) != 0 || len(report.Missing) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	fixed, err := report.Fix(testRecords(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report = ValidateCOFs(fixed, cofs); !report.Empty() {
+		t.Fatalf("fixed records don't match COFs: %+v", report)
+	}
+
+	if _, err := Build(fixed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetEvent(t *testing.T) {
+	ad, err := Build(testRecords())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// records added by PushRecord have no events map
+	ad.PushRecord("AAA1HS")
+
+	if err := SetEvent(ad, "AAA1HS", 1, 4, d2animdata.AnimationEventSound); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetEvent(ad, "AAA1HS", 0, 3, d2animdata.AnimationEventNone); err != nil {
+		t.Fatal(err)
+	}
+
+	records := ad.GetRecords("AAA1HS")
+	if len(records) != 2 || len(records[0].Events()) != 0 || records[1].Event(4) != d2animdata.AnimationEventSound {
+		t.Fatalf("unexpected events: %v, %v", records[0].Events(), records[1].Events())
+	}
+}
+
+func TestCanAddRecord(t *testing.T) {
+	records := make([]Record, maxRecordsPerBlock)
+	for idx := range records {
+		records[idx] = Record{Name: "AAA1HS"}
+	}
+
+	ad, err := Build(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"", "AAA1HTH1", "AAA1HS"} {
+		if err := CanAddRecord(ad, name); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+
+	if err := CanAddRecord(ad, "BABLHTH"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,113 @@
+package hsanimdata
+
+import (
+	"sort"
+)
+
+// Merge represents a result of merging imported records into the current ones
+type Merge struct {
+	// Added are names of entries present only in imported records
+	Added []string
+	// Conflicts are names of entries, which records differ
+	Conflicts []string
+	// Unchanged is a number of entries, which are the same in both sets of records
+	Unchanged int
+
+	current, imported map[string][]Record
+}
+
+func groupByName(records []Record) map[string][]Record {
+	result := make(map[string][]Record)
+
+	for _, r := range records {
+		result[r.Name] = append(result[r.Name], r)
+	}
+
+	return result
+}
+
+func equalRecords(a, b []Record) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if !a[idx].Equal(&b[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewMerge compares current records with the imported ones
+func NewMerge(current, imported []Record) *Merge {
+	result := &Merge{
+		Added:     make([]string, 0),
+		Conflicts: make([]string, 0),
+		current:   groupByName(current),
+		imported:  groupByName(imported),
+	}
+
+	for name, records := range result.imported {
+		currentRecords, found := result.current[name]
+
+		switch {
+		case !found:
+			result.Added = append(result.Added, name)
+		case equalRecords(currentRecords, records):
+			result.Unchanged++
+		default:
+			result.Conflicts = append(result.Conflicts, name)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Conflicts)
+
+	return result
+}
+
+// Current returns current records of entry given
+func (m *Merge) Current(name string) []Record {
+	return m.current[name]
+}
+
+// Imported returns imported records of entry given
+func (m *Merge) Imported(name string) []Record {
+	return m.imported[name]
+}
+
+// Records returns merged records; added entries are always taken, conflicting
+// ones are taken from the imported records if overwrite is true
+func (m *Merge) Records(overwrite bool) []Record {
+	merged := make(map[string][]Record, len(m.current)+len(m.Added))
+
+	for name, records := range m.current {
+		merged[name] = records
+	}
+
+	for _, name := range m.Added {
+		merged[name] = m.imported[name]
+	}
+
+	if overwrite {
+		for _, name := range m.Conflicts {
+			merged[name] = m.imported[name]
+		}
+	}
+
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	result := make([]Record, 0)
+	for _, name := range names {
+		result = append(result, merged[name]...)
+	}
+
+	return result
+}
@@ -0,0 +1,290 @@
+package hsanimdata
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2animdata"
+)
+
+const (
+	numBlocks          = 256
+	maxRecordsPerBlock = 67
+	byteCountName      = 8
+	speedPaddingBytes  = 2
+	// NumEvents is a number of frames, which could have an event
+	NumEvents = 144
+)
+
+// Record is a single animation data record
+type Record struct {
+	Name               string
+	FramesPerDirection uint32
+	Speed              uint16
+	// Events maps frame index to event
+	Events map[int]d2animdata.AnimationEvent
+}
+
+// Equal returns true if records are the same
+func (r *Record) Equal(other *Record) bool {
+	if r.Name != other.Name || r.FramesPerDirection != other.FramesPerDirection || r.Speed != other.Speed {
+		return false
+	}
+
+	for frame := 0; frame < NumEvents; frame++ {
+		if r.Events[frame] != other.Events[frame] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EventName returns name of animation event
+func EventName(event d2animdata.AnimationEvent) string {
+	table := map[d2animdata.AnimationEvent]string{
+		d2animdata.AnimationEventNone:    "none",
+		d2animdata.AnimationEventAttack:  "attack",
+		d2animdata.AnimationEventMissile: "missile",
+		d2animdata.AnimationEventSound:   "sound",
+		d2animdata.AnimationEventSkill:   "skill",
+	}
+
+	if name, found := table[event]; found {
+		return name
+	}
+
+	return strconv.Itoa(int(event))
+}
+
+// EventFromName returns animation event of name given (numbers are accepted too)
+func EventFromName(name string) (d2animdata.AnimationEvent, error) {
+	for event := d2animdata.AnimationEventNone; event <= d2animdata.AnimationEventSkill; event++ {
+		if strings.EqualFold(EventName(event), name) {
+			return event, nil
+		}
+	}
+
+	n, err := strconv.ParseUint(name, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown animation event %q", name)
+	}
+
+	return d2animdata.AnimationEvent(n), nil
+}
+
+// Records returns records of animation data sorted by name (records of the same name keep their order)
+func Records(ad *d2animdata.AnimationData) []Record {
+	names := ad.GetRecordNames()
+	sort.Strings(names)
+
+	result := make([]Record, 0, len(names))
+
+	for _, name := range names {
+		for _, record := range ad.GetRecords(name) {
+			events := make(map[int]d2animdata.AnimationEvent)
+
+			for frame, event := range record.Events() {
+				if event != d2animdata.AnimationEventNone {
+					events[frame] = event
+				}
+			}
+
+			result = append(result, Record{
+				Name:               name,
+				FramesPerDirection: uint32(record.FramesPerDirection()),
+				Speed:              uint16(record.Speed()),
+				Events:             events,
+			})
+		}
+	}
+
+	return result
+}
+
+// validate checks if record can be encoded
+func (r *Record) validate() error {
+	if r.Name == "" || len(r.Name) >= byteCountName {
+		return fmt.Errorf("invalid record name %q (should have 1 to %d characters)", r.Name, byteCountName-1)
+	}
+
+	for frame := range r.Events {
+		if frame < 0 || frame >= NumEvents {
+			return fmt.Errorf("record %s: event frame %d out of range (0-%d)", r.Name, frame, NumEvents-1)
+		}
+	}
+
+	return nil
+}
+
+// blockIndex returns index of hash block, which record belongs to
+func blockIndex(name string) int {
+	hash := 0
+
+	for _, b := range []byte(strings.ToUpper(name)) {
+		hash += int(b)
+	}
+
+	return hash % numBlocks
+}
+
+// CanAddRecord returns an error if a record named name can't be added to animation data,
+// because the name is invalid or its hash block is full
+func CanAddRecord(ad *d2animdata.AnimationData, name string) error {
+	record := Record{Name: name}
+	if err := record.validate(); err != nil {
+		return err
+	}
+
+	block := blockIndex(name)
+	count := 0
+
+	for _, n := range ad.GetRecordNames() {
+		if blockIndex(n) == block {
+			count += len(ad.GetRecords(n))
+		}
+	}
+
+	if count >= maxRecordsPerBlock {
+		return fmt.Errorf("record %s: hash block %d is full (max %d records)", name, block, maxRecordsPerBlock)
+	}
+
+	return nil
+}
+
+// Marshal encodes records into AnimData.d2
+func Marshal(records []Record) ([]byte, error) {
+	var blocks [numBlocks][]*Record
+
+	for idx := range records {
+		record := &records[idx]
+
+		if err := record.validate(); err != nil {
+			return nil, err
+		}
+
+		block := blockIndex(record.Name)
+		if len(blocks[block]) == maxRecordsPerBlock {
+			return nil, fmt.Errorf("record %s: too many records in hash block %d (max %d)", record.Name, block, maxRecordsPerBlock)
+		}
+
+		blocks[block] = append(blocks[block], record)
+	}
+
+	sw := d2datautils.CreateStreamWriter()
+
+	for _, block := range blocks {
+		sw.PushUint32(uint32(len(block)))
+
+		for _, record := range block {
+			name := make([]byte, byteCountName)
+			copy(name, record.Name)
+			sw.PushBytes(name...)
+
+			sw.PushUint32(record.FramesPerDirection)
+			sw.PushUint16(record.Speed)
+			sw.PushBytes(make([]byte, speedPaddingBytes)...)
+
+			for frame := 0; frame < NumEvents; frame++ {
+				sw.PushBytes(byte(record.Events[frame]))
+			}
+		}
+	}
+
+	return sw.GetBytes(), nil
+}
+
+// Build creates animation data of records given
+func Build(records []Record) (*d2animdata.AnimationData, error) {
+	data, err := Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d2animdata.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("error loading animation data: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetSpeed sets speed of all records, which names match pattern (path.Match syntax, case insensitive).
+// Returns number of records changed
+func SetSpeed(ad *d2animdata.AnimationData, pattern string, speed uint16) (int, error) {
+	if pattern == "" {
+		return 0, errors.New("empty pattern")
+	}
+
+	count := 0
+
+	for _, name := range ad.GetRecordNames() {
+		match, err := path.Match(strings.ToUpper(pattern), strings.ToUpper(name))
+		if err != nil {
+			return 0, fmt.Errorf("invalid pattern: %w", err)
+		}
+
+		if !match {
+			continue
+		}
+
+		for _, record := range ad.GetRecords(name) {
+			record.SetSpeed(speed)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// SetEvent sets event of record (recordIdx of records named name) on frame given;
+// AnimationEventNone removes event from frame
+func SetEvent(ad *d2animdata.AnimationData, name string, recordIdx, frame int, event d2animdata.AnimationEvent) error {
+	records := ad.GetRecords(name)
+	if recordIdx < 0 || recordIdx >= len(records) {
+		return fmt.Errorf("%s: record %d not found", name, recordIdx)
+	}
+
+	if frame < 0 || frame >= NumEvents {
+		return fmt.Errorf("%s: frame %d out of range (0-%d)", name, frame, NumEvents-1)
+	}
+
+	if events := records[recordIdx].Events(); events != nil {
+		if event == d2animdata.AnimationEventNone {
+			delete(events, frame)
+		} else {
+			events[frame] = event
+		}
+
+		return nil
+	}
+
+	// records created by PushRecord have no events map, so it's rebuilt
+	all := Records(ad)
+
+	for idx, n := 0, 0; idx < len(all); idx++ {
+		if all[idx].Name != name {
+			continue
+		}
+
+		if n == recordIdx && event != d2animdata.AnimationEventNone {
+			all[idx].Events[frame] = event
+		}
+
+		n++
+	}
+
+	built, err := Build(all)
+	if err != nil {
+		return err
+	}
+
+	*ad = *built
+
+	return nil
+}
@@ -0,0 +1,5 @@
+// Package hsbin contains data for compiled excel tables (data\global\excel\*.bin).
+// Diablo II compiles its tab-separated *.txt tables into fixed-size binary
+// records; this package knows the record layouts of some of these tables
+// and can convert them from and to the txt representation.
+package hsbin
@@ -0,0 +1,286 @@
+package hsbin
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+)
+
+// FieldType represents a type of compiled table field
+type FieldType int
+
+// field types
+const (
+	// FieldCode is a 4-character code (e.g. item or body location code)
+	FieldCode FieldType = iota
+	FieldUint8
+	FieldUint16
+	FieldUint32
+	FieldInt32
+)
+
+const (
+	codeSize   = 4
+	uint8Size  = 1
+	uint16Size = 2
+	uint32Size = 4
+
+	recordCountSize = 4
+)
+
+// Field represents a single field of compiled table record
+type Field struct {
+	// Column is a name of txt column the field is compiled from
+	Column string
+	Type   FieldType
+}
+
+// size returns a size of the field in bytes
+func (f *Field) size() int {
+	switch f.Type {
+	case FieldCode:
+		return codeSize
+	case FieldUint8:
+		return uint8Size
+	case FieldUint16:
+		return uint16Size
+	case FieldUint32, FieldInt32:
+		return uint32Size
+	}
+
+	return 0
+}
+
+// Layout describes a record of compiled table
+type Layout struct {
+	Name   string
+	Fields []Field
+}
+
+// LayoutFor returns a record layout of compiled table given by file name
+// (e.g. "data/global/excel/BodyLocs.bin" or "bodylocs.txt")
+func LayoutFor(fileName string) (*Layout, bool) {
+	name := strings.ToLower(filepath.Base(fileName))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	layout, found := knownLayouts()[name]
+	if !found {
+		return nil, false
+	}
+
+	return layout, true
+}
+
+// RecordSize returns a size of single record in bytes
+func (l *Layout) RecordSize() int {
+	result := 0
+
+	for idx := range l.Fields {
+		result += l.Fields[idx].size()
+	}
+
+	return result
+}
+
+// Columns returns names of txt columns stored in compiled table
+func (l *Layout) Columns() []string {
+	result := make([]string, len(l.Fields))
+
+	for idx := range l.Fields {
+		result[idx] = l.Fields[idx].Column
+	}
+
+	return result
+}
+
+// Decode decodes compiled table
+func (l *Layout) Decode(data []byte) (*Table, error) {
+	if len(data) < recordCountSize {
+		return nil, errors.New("compiled table is too short")
+	}
+
+	sr := d2datautils.CreateStreamReader(data)
+
+	numRecords, err := sr.ReadUInt32()
+	if err != nil {
+		return nil, fmt.Errorf("error reading number of records: %w", err)
+	}
+
+	if expected := recordCountSize + int(numRecords)*l.RecordSize(); expected != len(data) {
+		return nil, fmt.Errorf("unexpected size of %s table: %d bytes (expected %d bytes for %d records)",
+			l.Name, len(data), expected, numRecords)
+	}
+
+	result := &Table{
+		Columns: l.Columns(),
+		Rows:    make([][]string, numRecords),
+	}
+
+	for recordIdx := range result.Rows {
+		row := make([]string, len(l.Fields))
+
+		for fieldIdx := range l.Fields {
+			if row[fieldIdx], err = l.Fields[fieldIdx].decode(sr); err != nil {
+				return nil, fmt.Errorf("error reading record %d, field %s: %w", recordIdx, l.Fields[fieldIdx].Column, err)
+			}
+		}
+
+		result.Rows[recordIdx] = row
+	}
+
+	return result, nil
+}
+
+// Encode compiles table given
+func (l *Layout) Encode(table *Table) ([]byte, error) {
+	columnIndices := make([]int, len(l.Fields))
+
+	for idx := range l.Fields {
+		columnIndices[idx] = table.ColumnIndex(l.Fields[idx].Column)
+		if columnIndices[idx] < 0 {
+			return nil, fmt.Errorf("column %s not found", l.Fields[idx].Column)
+		}
+	}
+
+	sw := d2datautils.CreateStreamWriter()
+
+	rows := make([][]string, 0, len(table.Rows))
+
+	for _, row := range table.Rows {
+		if isExpansionRow(row) {
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	sw.PushUint32(uint32(len(rows)))
+
+	for rowIdx, row := range rows {
+		for fieldIdx := range l.Fields {
+			value := ""
+			if columnIndices[fieldIdx] < len(row) {
+				value = row[columnIndices[fieldIdx]]
+			}
+
+			if err := l.Fields[fieldIdx].encode(sw, value); err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %w", rowIdx+1, l.Fields[fieldIdx].Column, err)
+			}
+		}
+	}
+
+	return sw.GetBytes(), nil
+}
+
+func (f *Field) decode(sr *d2datautils.StreamReader) (string, error) {
+	switch f.Type {
+	case FieldCode:
+		data, err := sr.ReadBytes(f.size())
+		if err != nil {
+			return "", fmt.Errorf("error reading string: %w", err)
+		}
+
+		if idx := strings.IndexByte(string(data), 0); idx >= 0 {
+			data = data[:idx]
+		}
+
+		return strings.TrimRight(string(data), " "), nil
+	case FieldUint8:
+		value, err := sr.ReadByte()
+		if err != nil {
+			return "", fmt.Errorf("error reading byte: %w", err)
+		}
+
+		return strconv.Itoa(int(value)), nil
+	case FieldUint16:
+		value, err := sr.ReadUInt16()
+		if err != nil {
+			return "", fmt.Errorf("error reading word: %w", err)
+		}
+
+		return strconv.Itoa(int(value)), nil
+	case FieldUint32:
+		value, err := sr.ReadUInt32()
+		if err != nil {
+			return "", fmt.Errorf("error reading dword: %w", err)
+		}
+
+		return strconv.FormatUint(uint64(value), 10), nil
+	case FieldInt32:
+		value, err := sr.ReadInt32()
+		if err != nil {
+			return "", fmt.Errorf("error reading dword: %w", err)
+		}
+
+		return strconv.Itoa(int(value)), nil
+	}
+
+	return "", fmt.Errorf("unknown field type %d", f.Type)
+}
+
+func (f *Field) encode(sw *d2datautils.StreamWriter, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch f.Type {
+	case FieldCode:
+		if len(value) > f.size() {
+			return fmt.Errorf("%q is longer than %d characters", value, f.size())
+		}
+
+		padding := byte(0)
+		if value != "" {
+			padding = ' '
+		}
+
+		data := []byte(value)
+		for len(data) < f.size() {
+			data = append(data, padding)
+		}
+
+		sw.PushBytes(data...)
+
+		return nil
+	}
+
+	if value == "" {
+		value = "0"
+	}
+
+	bitSize := map[FieldType]int{
+		FieldUint8:  8,
+		FieldUint16: 16,
+		FieldUint32: 32,
+		FieldInt32:  32,
+	}[f.Type]
+
+	if f.Type == FieldInt32 {
+		number, err := strconv.ParseInt(value, 10, bitSize)
+		if err != nil {
+			return fmt.Errorf("invalid number %q: %w", value, err)
+		}
+
+		sw.PushInt32(int32(number))
+
+		return nil
+	}
+
+	number, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil {
+		return fmt.Errorf("invalid number %q: %w", value, err)
+	}
+
+	switch f.Type {
+	case FieldUint8:
+		sw.PushBytes(byte(number))
+	case FieldUint16:
+		sw.PushUint16(uint16(number))
+	default:
+		sw.PushUint32(uint32(number))
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package hsbin
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLayout_EncodeDecode(t *testing.T) {
+	txt := "Body Location\tCode\r\nNone\t\r\nHead\thead\r\nExpansion\r\nRight Arm\trarm\r\n"
+
+	table, err := LoadTXT([]byte(txt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layout, found := LayoutFor("data/global/excel/BodyLocs.txt")
+	if !found {
+		t.Fatal("bodylocs layout not found")
+	}
+
+	data, err := layout.Encode(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{3, 0, 0, 0, 0, 0, 0, 0, 'h', 'e', 'a', 'd', 'r', 'a', 'r', 'm'}
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("unexpected compiled data %v", data)
+	}
+
+	decoded, err := layout.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := []string{"", "head", "rarm"}
+	for idx, code := range codes {
+		if decoded.Rows[idx][0] != code {
+			t.Fatalf("record %d: unexpected code %q (expected %q)", idx, decoded.Rows[idx][0], code)
+		}
+	}
+}
+
+func TestLayout_EncodeInvalidNumber(t *testing.T) {
+	table := &Table{
+		Columns: []string{"Level", "Amazon", "Sorceress", "Necromancer", "Paladin", "Barbarian", "Druid", "Assassin", "ExpRatio"},
+		Rows:    [][]string{{"1", "abc", "0", "0", "0", "0", "0", "0", "1024"}},
+	}
+
+	layout, found := LayoutFor("experience.bin")
+	if !found {
+		t.Fatal("experience layout not found")
+	}
+
+	if _, err := layout.Encode(table); err == nil {
+		t.Fatal("expected error for invalid number")
+	}
+}
@@ -0,0 +1,40 @@
+package hsbin
+
+// codeTable returns a layout of tables, which store only a 4-character code per record
+func codeTable(name string) *Layout {
+	return &Layout{
+		Name: name,
+		Fields: []Field{
+			{Column: "Code", Type: FieldCode},
+		},
+	}
+}
+
+// knownLayouts returns layouts of compiled tables supported, keyed by lowercase table name
+func knownLayouts() map[string]*Layout {
+	return map[string]*Layout{
+		"bodylocs":    codeTable("BodyLocs"),
+		"colors":      codeTable("Colors"),
+		"elemtypes":   codeTable("ElemTypes"),
+		"hitclass":    codeTable("HitClass"),
+		"playerclass": codeTable("PlayerClass"),
+		"storepage":   codeTable("StorePage"),
+		"plrmode":     codeTable("PlrMode"),
+		"monmode":     codeTable("MonMode"),
+		"objmode":     codeTable("ObjMode"),
+		"composit":    codeTable("Composit"),
+		"experience": {
+			Name: "Experience",
+			Fields: []Field{
+				{Column: "Amazon", Type: FieldUint32},
+				{Column: "Sorceress", Type: FieldUint32},
+				{Column: "Necromancer", Type: FieldUint32},
+				{Column: "Paladin", Type: FieldUint32},
+				{Column: "Barbarian", Type: FieldUint32},
+				{Column: "Druid", Type: FieldUint32},
+				{Column: "Assassin", Type: FieldUint32},
+				{Column: "ExpRatio", Type: FieldUint32},
+			},
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package hsbin
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	txtColumnSeparator = "\t"
+	txtLineSeparator   = "\r\n"
+	txtExpansionRow    = "Expansion"
+)
+
+// Table represents a tab-separated excel table
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// LoadTXT loads a table from tab-separated txt data
+func LoadTXT(data []byte) (*Table, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	// trailing empty lines are not a part of the table
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("table has no header")
+	}
+
+	result := &Table{
+		Columns: strings.Split(lines[0], txtColumnSeparator),
+		Rows:    make([][]string, 0, len(lines)-1),
+	}
+
+	for _, line := range lines[1:] {
+		result.Rows = append(result.Rows, strings.Split(line, txtColumnSeparator))
+	}
+
+	return result, nil
+}
+
+// MarshalTXT encodes table into tab-separated txt data
+func (t *Table) MarshalTXT() []byte {
+	lines := make([]string, 0, len(t.Rows)+1)
+
+	lines = append(lines, strings.Join(t.Columns, txtColumnSeparator))
+
+	for _, row := range t.Rows {
+		lines = append(lines, strings.Join(row, txtColumnSeparator))
+	}
+
+	return []byte(strings.Join(lines, txtLineSeparator) + txtLineSeparator)
+}
+
+// ColumnIndex returns index of the column given or -1 if the table doesn't have it
+func (t *Table) ColumnIndex(column string) int {
+	for idx := range t.Columns {
+		if strings.EqualFold(t.Columns[idx], column) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// isExpansionRow returns true if the row is a marker, which is skipped by the game, when compiling tables
+func isExpansionRow(row []string) bool {
+	return len(row) > 0 && row[0] == txtExpansionRow
+}
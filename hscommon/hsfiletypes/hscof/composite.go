@@ -0,0 +1,330 @@
+package hscof
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2cof"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dcc"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	tokenLen         = 2
+	modeLen          = 2
+	maxAlpha         = 0xff
+	transparentAlpha = 0x80
+)
+
+// FileReader reads a file of path given (MPQ notation, e.g. data\global\chars\AM\COF\AMA1HTH.cof)
+type FileReader func(path string) ([]byte, error)
+
+// basePaths are directories containing animated units
+func basePaths() []string {
+	return []string{`data\global\chars`, `data\global\monsters`}
+}
+
+// layerStyles are styles tried (in order) when looking for layer's graphics: armor styles and,
+// for weapon layers, the animation's weapon class (e.g. HTH) first
+func layerStyles(layerType d2enum.CompositeType, weaponClass string) []string {
+	armorStyles := []string{"LIT", "MED", "HVY"}
+
+	switch layerType {
+	case d2enum.CompositeTypeRightHand, d2enum.CompositeTypeLeftHand, d2enum.CompositeTypeShield:
+		return append([]string{weaponClass}, armorStyles...)
+	}
+
+	return armorStyles
+}
+
+// ParseName splits animation name (e.g. AMA1HTH) into token, mode and weapon class
+func ParseName(name string) (token, mode, weaponClass string, err error) {
+	name = strings.ToUpper(name)
+
+	if len(name) <= tokenLen+modeLen {
+		return "", "", "", fmt.Errorf("invalid animation name %q", name)
+	}
+
+	return name[:tokenLen], name[tokenLen : tokenLen+modeLen], name[tokenLen+modeLen:], nil
+}
+
+// FindCOF looks for COF of animation name given in characters and monsters directories.
+// Returns COF and the directory of its token (e.g. data\global\chars\AM)
+func FindCOF(name string, read FileReader) (cof *d2cof.COF, tokenPath string, err error) {
+	token, _, _, err := ParseName(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, base := range basePaths() {
+		tokenPath = base + `\` + token
+
+		data, readErr := read(tokenPath + `\COF\` + strings.ToUpper(name) + ".cof")
+		if readErr != nil {
+			continue
+		}
+
+		if cof, err = d2cof.Unmarshal(data); err != nil {
+			return nil, "", fmt.Errorf("cannot load COF of %s: %w", name, err)
+		}
+
+		return cof, tokenPath, nil
+	}
+
+	return nil, "", fmt.Errorf("COF of %s not found", name)
+}
+
+// layerFrame is a palettized frame of layer placed relatively to the animation's origin
+type layerFrame struct {
+	indices []byte
+	rect    image.Rectangle
+}
+
+// layer is a direction of layer's graphics
+type layer struct {
+	cofLayer *d2cof.CofLayer
+	frames   []layerFrame
+}
+
+func loadDCC(data []byte, direction int) ([]layerFrame, error) {
+	dcc, err := d2dcc.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load DCC: %w", err)
+	}
+
+	if len(dcc.Directions) == 0 {
+		return nil, errors.New("DCC has no directions")
+	}
+
+	dir := dcc.Directions[direction%len(dcc.Directions)]
+	box := dir.Box
+	rect := image.Rect(box.Left, box.Top, box.Left+box.Width, box.Top+box.Height)
+	result := make([]layerFrame, len(dir.Frames))
+
+	for idx, frame := range dir.Frames {
+		result[idx] = layerFrame{indices: frame.PixelData, rect: rect}
+	}
+
+	return result, nil
+}
+
+func loadDC6(data []byte, direction int) ([]layerFrame, error) {
+	dc6, err := d2dc6.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load DC6: %w", err)
+	}
+
+	if dc6.Directions == 0 {
+		return nil, errors.New("DC6 has no directions")
+	}
+
+	fpd := int(dc6.FramesPerDirection)
+	first := (direction % int(dc6.Directions)) * fpd
+
+	if first+fpd > len(dc6.Frames) {
+		return nil, fmt.Errorf("DC6 has %d frames, %d directions of %d frames expected",
+			len(dc6.Frames), dc6.Directions, fpd)
+	}
+
+	result := make([]layerFrame, fpd)
+
+	for idx := range result {
+		frame := dc6.Frames[first+idx]
+		if frame.Width == 0 || frame.Height == 0 {
+			continue
+		}
+
+		left, bottom := int(frame.OffsetX), int(frame.OffsetY)
+
+		result[idx] = layerFrame{
+			indices: dc6.DecodeFrame(first + idx),
+			rect:    image.Rect(left, bottom-int(frame.Height), left+int(frame.Width), bottom),
+		}
+	}
+
+	return result, nil
+}
+
+// Composite is a direction of animation composed of all its layers
+type Composite struct {
+	Name      string
+	COF       *d2cof.COF
+	Direction int
+	// Layers are paths of layers' graphics, which were loaded
+	Layers []string
+	// Missing are names of layers, which graphics weren't found
+	Missing []string
+	// Frames are composed frames of direction; all frames have the same size
+	Frames []*image.RGBA
+}
+
+// Load composes direction of animation name given. Layers are drawn with the lightest armor
+// style found (weapon layers with the animation's weapon class, if there is such a file); palette may be nil (grayscale is used then)
+func Load(name string, read FileReader, palette *[256]d2interface.Color, direction int) (*Composite, error) {
+	_, mode, animationClass, err := ParseName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cof, tokenPath, err := FindCOF(name, read)
+	if err != nil {
+		return nil, err
+	}
+
+	if cof.NumberOfDirections == 0 || cof.FramesPerDirection == 0 {
+		return nil, fmt.Errorf("COF of %s has no frames", name)
+	}
+
+	direction %= cof.NumberOfDirections
+	token := tokenPath[strings.LastIndex(tokenPath, `\`)+1:]
+
+	result := &Composite{
+		Name:      strings.ToUpper(name),
+		COF:       cof,
+		Direction: direction,
+		Layers:    make([]string, 0),
+		Missing:   make([]string, 0),
+	}
+
+	layers := make(map[d2enum.CompositeType]*layer)
+
+	for idx := range cof.CofLayers {
+		cofLayer := &cof.CofLayers[idx]
+		key := cofLayer.Type.String()
+		weaponClass := strings.ToUpper(cofLayer.WeaponClass.String())
+
+		styles := layerStyles(cofLayer.Type, animationClass)
+
+		l, path := loadLayer(read, tokenPath, token+key, mode+weaponClass, key, styles, direction)
+		if l == nil {
+			result.Missing = append(result.Missing, key)
+
+			continue
+		}
+
+		l.cofLayer = cofLayer
+		layers[cofLayer.Type] = l
+		result.Layers = append(result.Layers, path)
+	}
+
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("graphics of %s not found", name)
+	}
+
+	result.compose(layers, palette)
+
+	return result, nil
+}
+
+// loadLayer tries styles given and both DCC and DC6 files of layer
+func loadLayer(read FileReader, tokenPath, prefix, suffix, key string, styles []string,
+	direction int) (l *layer, path string) {
+	loaders := map[string]func([]byte, int) ([]layerFrame, error){
+		".dcc": loadDCC,
+		".dc6": loadDC6,
+	}
+
+	for _, style := range styles {
+		for _, ext := range []string{".dcc", ".dc6"} {
+			path = tokenPath + `\` + key + `\` + prefix + style + suffix + ext
+
+			data, err := read(path)
+			if err != nil {
+				continue
+			}
+
+			frames, err := loaders[ext](data, direction)
+			if err != nil {
+				continue
+			}
+
+			return &layer{frames: frames}, path
+		}
+	}
+
+	return nil, ""
+}
+
+func (c *Composite) compose(layers map[d2enum.CompositeType]*layer, palette *[256]d2interface.Color) {
+	bounds := image.Rectangle{}
+
+	for _, l := range layers {
+		for _, f := range l.frames {
+			bounds = bounds.Union(f.rect)
+		}
+	}
+
+	fpd := c.COF.FramesPerDirection
+	c.Frames = make([]*image.RGBA, fpd)
+
+	for frameIdx := 0; frameIdx < fpd; frameIdx++ {
+		img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+		for _, layerType := range c.drawOrder(frameIdx) {
+			l, found := layers[layerType]
+			if !found || frameIdx >= len(l.frames) {
+				continue
+			}
+
+			drawFrame(img, &l.frames[frameIdx], bounds.Min, palette, l.cofLayer.Transparent)
+		}
+
+		c.Frames[frameIdx] = img
+	}
+}
+
+// drawOrder returns composite types of layers in order they should be drawn in frame given
+func (c *Composite) drawOrder(frameIdx int) []d2enum.CompositeType {
+	if c.Direction < len(c.COF.Priority) && frameIdx < len(c.COF.Priority[c.Direction]) {
+		return c.COF.Priority[c.Direction][frameIdx]
+	}
+
+	result := make([]d2enum.CompositeType, len(c.COF.CofLayers))
+	for idx := range c.COF.CofLayers {
+		result[idx] = c.COF.CofLayers[idx].Type
+	}
+
+	return result
+}
+
+func drawFrame(img *image.RGBA, f *layerFrame, origin image.Point, palette *[256]d2interface.Color, transparent bool) {
+	w := f.rect.Dx()
+
+	for idx, val := range f.indices {
+		if val == 0 || w == 0 {
+			continue
+		}
+
+		x, y := f.rect.Min.X+idx%w-origin.X, f.rect.Min.Y+idx/w-origin.Y
+
+		c := color.RGBA{R: val, G: val, B: val, A: maxAlpha}
+		if palette != nil {
+			c = color.RGBA{R: palette[val].R(), G: palette[val].G(), B: palette[val].B(), A: maxAlpha}
+		}
+
+		if transparent {
+			c = blend(img.RGBAAt(x, y), c)
+		}
+
+		img.SetRGBA(x, y, c)
+	}
+}
+
+// blend draws color c at half opacity over dst
+func blend(dst, c color.RGBA) color.RGBA {
+	if dst.A == 0 {
+		return color.RGBA{R: c.R / 2, G: c.G / 2, B: c.B / 2, A: transparentAlpha}
+	}
+
+	return color.RGBA{
+		R: uint8((uint16(dst.R) + uint16(c.R)) / 2),
+		G: uint8((uint16(dst.G) + uint16(c.G)) / 2),
+		B: uint8((uint16(dst.B) + uint16(c.B)) / 2),
+		A: dst.A,
+	}
+}
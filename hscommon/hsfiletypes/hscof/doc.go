@@ -0,0 +1,5 @@
+// Package hscof contains helpers for COF (component object files): it locates a COF
+// and the DCC/DC6 graphics of its layers by animation name (e.g. AMA1HTH, which is
+// token AM, mode A1 and weapon class HTH) and composes layers into frames in the order
+// given by COF's priorities.
+package hscof
@@ -0,0 +1,4 @@
+// Package hsdc6 contains helpers for creating DC6 sprites: it encodes indexed
+// (palettized) frames into DC6's run-length scanlines and computes frame pointers,
+// so that a sprite can be built from images and saved with d2dc6.DC6.Marshal.
+package hsdc6
@@ -0,0 +1,292 @@
+package hsdc6
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+)
+
+// directions splits frames of sprite into directions
+func directions(sprite *d2dc6.DC6) [][]*d2dc6.DC6Frame {
+	fpd := int(sprite.FramesPerDirection)
+	result := make([][]*d2dc6.DC6Frame, sprite.Directions)
+
+	for dir := range result {
+		result[dir] = append([]*d2dc6.DC6Frame{}, sprite.Frames[dir*fpd:(dir+1)*fpd]...)
+	}
+
+	return result
+}
+
+// setDirections replaces frames of sprite (all directions should have the same number of frames)
+func setDirections(sprite *d2dc6.DC6, dirs [][]*d2dc6.DC6Frame) {
+	sprite.Directions = uint32(len(dirs))
+	sprite.FramesPerDirection = 0
+	sprite.Frames = make([]*d2dc6.DC6Frame, 0)
+
+	if len(dirs) > 0 {
+		sprite.FramesPerDirection = uint32(len(dirs[0]))
+	}
+
+	for _, dir := range dirs {
+		sprite.Frames = append(sprite.Frames, dir...)
+	}
+
+	UpdatePointers(sprite)
+}
+
+func newFrame(frame *Frame) *d2dc6.DC6Frame {
+	data := EncodeFrame(frame.Indices, frame.Width, frame.Height)
+
+	return &d2dc6.DC6Frame{
+		Width:      uint32(frame.Width),
+		Height:     uint32(frame.Height),
+		OffsetX:    int32(frame.OffsetX),
+		OffsetY:    int32(frame.OffsetY),
+		Length:     uint32(len(data)),
+		FrameData:  data,
+		Terminator: []byte{terminator, terminator, terminator},
+	}
+}
+
+// blankFrame returns a transparent frame; frames can't be empty, because the game
+// (and d2dc6.DecodeFrame) expects at least one scanline
+func blankFrame() *d2dc6.DC6Frame {
+	return newFrame(&Frame{Width: 1, Height: 1, Indices: []byte{0}})
+}
+
+func cloneFrame(frame *d2dc6.DC6Frame) *d2dc6.DC6Frame {
+	clone := *frame
+	clone.FrameData = append([]byte{}, frame.FrameData...)
+	clone.Terminator = append([]byte{}, frame.Terminator...)
+
+	return &clone
+}
+
+func insert(frames []*d2dc6.DC6Frame, index int, frame *d2dc6.DC6Frame) []*d2dc6.DC6Frame {
+	frames = append(frames, nil)
+	copy(frames[index+1:], frames[index:])
+	frames[index] = frame
+
+	return frames
+}
+
+func checkIndex(what string, index, count int) error {
+	if index < 0 || index >= count {
+		return fmt.Errorf("%s %d out of range (0-%d)", what, index, count-1)
+	}
+
+	return nil
+}
+
+// InsertFrame inserts a blank frame at index (0 - frames per direction) of every direction
+func InsertFrame(sprite *d2dc6.DC6, index int) error {
+	if err := checkIndex("frame", index, int(sprite.FramesPerDirection)+1); err != nil {
+		return err
+	}
+
+	dirs := directions(sprite)
+	for idx := range dirs {
+		dirs[idx] = insert(dirs[idx], index, blankFrame())
+	}
+
+	setDirections(sprite, dirs)
+
+	return nil
+}
+
+// DuplicateFrame inserts a copy of frame index after it in every direction
+func DuplicateFrame(sprite *d2dc6.DC6, index int) error {
+	if err := checkIndex("frame", index, int(sprite.FramesPerDirection)); err != nil {
+		return err
+	}
+
+	dirs := directions(sprite)
+	for idx := range dirs {
+		dirs[idx] = insert(dirs[idx], index+1, cloneFrame(dirs[idx][index]))
+	}
+
+	setDirections(sprite, dirs)
+
+	return nil
+}
+
+// DeleteFrame removes frame index from every direction
+func DeleteFrame(sprite *d2dc6.DC6, index int) error {
+	if err := checkIndex("frame", index, int(sprite.FramesPerDirection)); err != nil {
+		return err
+	}
+
+	if sprite.FramesPerDirection == 1 {
+		return errors.New("cannot delete the only frame")
+	}
+
+	dirs := directions(sprite)
+	for idx := range dirs {
+		dirs[idx] = append(dirs[idx][:index], dirs[idx][index+1:]...)
+	}
+
+	setDirections(sprite, dirs)
+
+	return nil
+}
+
+// MoveFrame moves frame from index to index given in every direction
+func MoveFrame(sprite *d2dc6.DC6, from, to int) error {
+	fpd := int(sprite.FramesPerDirection)
+
+	if err := checkIndex("frame", from, fpd); err != nil {
+		return err
+	}
+
+	if err := checkIndex("frame", to, fpd); err != nil {
+		return err
+	}
+
+	dirs := directions(sprite)
+	for idx := range dirs {
+		frame := dirs[idx][from]
+		dirs[idx] = insert(append(dirs[idx][:from], dirs[idx][from+1:]...), to, frame)
+	}
+
+	setDirections(sprite, dirs)
+
+	return nil
+}
+
+// InsertDirection inserts a direction of blank frames at index (0 - number of directions)
+func InsertDirection(sprite *d2dc6.DC6, index int) error {
+	dirs := directions(sprite)
+
+	if err := checkIndex("direction", index, len(dirs)+1); err != nil {
+		return err
+	}
+
+	dir := make([]*d2dc6.DC6Frame, sprite.FramesPerDirection)
+	for idx := range dir {
+		dir[idx] = blankFrame()
+	}
+
+	dirs = append(dirs, nil)
+	copy(dirs[index+1:], dirs[index:])
+	dirs[index] = dir
+
+	setDirections(sprite, dirs)
+
+	return nil
+}
+
+// DuplicateDirection inserts a copy of direction index after it
+func DuplicateDirection(sprite *d2dc6.DC6, index int) error {
+	dirs := directions(sprite)
+
+	if err := checkIndex("direction", index, len(dirs)); err != nil {
+		return err
+	}
+
+	dir := make([]*d2dc6.DC6Frame, len(dirs[index]))
+	for idx := range dir {
+		dir[idx] = cloneFrame(dirs[index][idx])
+	}
+
+	dirs = append(dirs, nil)
+	copy(dirs[index+2:], dirs[index+1:])
+	dirs[index+1] = dir
+
+	setDirections(sprite, dirs)
+
+	return nil
+}
+
+// DeleteDirection removes direction index
+func DeleteDirection(sprite *d2dc6.DC6, index int) error {
+	dirs := directions(sprite)
+
+	if err := checkIndex("direction", index, len(dirs)); err != nil {
+		return err
+	}
+
+	if len(dirs) == 1 {
+		return errors.New("cannot delete the only direction")
+	}
+
+	setDirections(sprite, append(dirs[:index], dirs[index+1:]...))
+
+	return nil
+}
+
+// MoveDirection moves direction from index to index given
+func MoveDirection(sprite *d2dc6.DC6, from, to int) error {
+	dirs := directions(sprite)
+
+	if err := checkIndex("direction", from, len(dirs)); err != nil {
+		return err
+	}
+
+	if err := checkIndex("direction", to, len(dirs)); err != nil {
+		return err
+	}
+
+	dir := dirs[from]
+	dirs = append(dirs[:from], dirs[from+1:]...)
+	dirs = append(dirs, nil)
+	copy(dirs[to+1:], dirs[to:])
+	dirs[to] = dir
+
+	setDirections(sprite, dirs)
+
+	return nil
+}
+
+// ReplaceFrame replaces frame index of direction given; the frame keeps its offset
+func ReplaceFrame(sprite *d2dc6.DC6, direction, index int, frame Frame) error {
+	if err := checkIndex("direction", direction, int(sprite.Directions)); err != nil {
+		return err
+	}
+
+	if err := checkIndex("frame", index, int(sprite.FramesPerDirection)); err != nil {
+		return err
+	}
+
+	if frame.Width <= 0 || frame.Height <= 0 || len(frame.Indices) != frame.Width*frame.Height {
+		return fmt.Errorf("invalid frame %dx%d", frame.Width, frame.Height)
+	}
+
+	idx := direction*int(sprite.FramesPerDirection) + index
+	old := sprite.Frames[idx]
+	frame.OffsetX, frame.OffsetY = int(old.OffsetX), int(old.OffsetY)
+
+	sprite.Frames[idx] = newFrame(&frame)
+	sprite.Frames[idx].Flipped = old.Flipped
+
+	UpdatePointers(sprite)
+
+	return nil
+}
+
+// Remap replaces palette indices of all frames with the ones of table given (see hspalette.RemapTable)
+func Remap(sprite *d2dc6.DC6, table *[256]byte) {
+	for idx, old := range sprite.Frames {
+		if old.Width == 0 || old.Height == 0 {
+			continue
+		}
+
+		frame := Frame{
+			Width:   int(old.Width),
+			Height:  int(old.Height),
+			OffsetX: int(old.OffsetX),
+			OffsetY: int(old.OffsetY),
+			Indices: sprite.DecodeFrame(idx),
+		}
+
+		hspalette.Remap(frame.Indices, table)
+
+		sprite.Frames[idx] = newFrame(&frame)
+		sprite.Frames[idx].Flipped = old.Flipped
+	}
+
+	UpdatePointers(sprite)
+}
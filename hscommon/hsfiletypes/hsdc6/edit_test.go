@@ -0,0 +1,75 @@
+package hsdc6
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+)
+
+// testSprite creates a sprite, which frame's only pixel is 10*direction + frame + 1
+func testSprite(t *testing.T, dirs, fpd int) *d2dc6.DC6 {
+	t.Helper()
+
+	frames := make([]Frame, 0, dirs*fpd)
+
+	for dir := 0; dir < dirs; dir++ {
+		for frame := 0; frame < fpd; frame++ {
+			frames = append(frames, Frame{Width: 1, Height: 1, Indices: []byte{byte(10*dir + frame + 1)}})
+		}
+	}
+
+	sprite, err := New(dirs, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sprite
+}
+
+func pixels(t *testing.T, sprite *d2dc6.DC6) []byte {
+	t.Helper()
+
+	loaded, err := d2dc6.Load(sprite.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := make([]byte, len(loaded.Frames))
+	for idx := range loaded.Frames {
+		result[idx] = loaded.DecodeFrame(idx)[0]
+	}
+
+	return result
+}
+
+func TestEditFrames(t *testing.T) {
+	sprite := testSprite(t, 2, 2)
+
+	steps := []struct {
+		edit     func() error
+		expected string
+	}{
+		{func() error { return DuplicateFrame(sprite, 0) }, "\x01\x01\x02\x0b\x0b\x0c"},
+		{func() error { return MoveFrame(sprite, 2, 0) }, "\x02\x01\x01\x0c\x0b\x0b"},
+		{func() error { return DeleteFrame(sprite, 1) }, "\x02\x01\x0c\x0b"},
+		{func() error { return InsertFrame(sprite, 2) }, "\x02\x01\x00\x0c\x0b\x00"},
+		{func() error { return DuplicateDirection(sprite, 1) }, "\x02\x01\x00\x0c\x0b\x00\x0c\x0b\x00"},
+		{func() error { return MoveDirection(sprite, 0, 2) }, "\x0c\x0b\x00\x0c\x0b\x00\x02\x01\x00"},
+		{func() error { return DeleteDirection(sprite, 0) }, "\x0c\x0b\x00\x02\x01\x00"},
+		{func() error { return InsertDirection(sprite, 0) }, "\x00\x00\x00\x0c\x0b\x00\x02\x01\x00"},
+	}
+
+	for idx, step := range steps {
+		if err := step.edit(); err != nil {
+			t.Fatalf("step %d: %v", idx, err)
+		}
+
+		if got := string(pixels(t, sprite)); got != step.expected {
+			t.Fatalf("step %d: expected %q, got %q", idx, step.expected, got)
+		}
+	}
+
+	if err := DeleteFrame(testSprite(t, 1, 1), 0); err == nil {
+		t.Fatal("the only frame was deleted")
+	}
+}
@@ -0,0 +1,120 @@
+package hsdc6
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+)
+
+const (
+	version      = 6
+	flags        = 1
+	terminator   = 0xee
+	endOfLine    = 0x80
+	maxRunLength = 0x7f
+
+	terminationSize  = 4
+	terminatorSize   = 3
+	headerSize       = 24
+	framePointerSize = 4
+	frameHeaderSize  = 32
+)
+
+// Frame is a single indexed frame; Indices[x+y*Width] is a palette index of pixel (0 is transparent)
+type Frame struct {
+	Width, Height    int
+	OffsetX, OffsetY int
+	Indices          []byte
+}
+
+// EncodeFrame encodes indexed pixels into DC6 frame data.
+// DC6 stores scanlines from the bottom to the top; trailing transparent pixels are omitted.
+func EncodeFrame(indices []byte, width, height int) []byte {
+	result := make([]byte, 0, len(indices))
+
+	for y := height - 1; y >= 0; y-- {
+		line := indices[y*width : (y+1)*width]
+
+		// don't encode trailing transparent pixels
+		end := len(line)
+		for end > 0 && line[end-1] == 0 {
+			end--
+		}
+
+		for x := 0; x < end; {
+			run := 0
+
+			if line[x] == 0 {
+				for x+run < end && line[x+run] == 0 && run < maxRunLength {
+					run++
+				}
+
+				result = append(result, byte(endOfLine|run))
+				x += run
+
+				continue
+			}
+
+			for x+run < end && line[x+run] != 0 && run < maxRunLength {
+				run++
+			}
+
+			result = append(result, byte(run))
+			result = append(result, line[x:x+run]...)
+			x += run
+		}
+
+		result = append(result, endOfLine)
+	}
+
+	return result
+}
+
+// New creates a DC6 sprite of frames given (directions * framesPerDirection frames, grouped by direction)
+func New(directions int, frames []Frame) (*d2dc6.DC6, error) {
+	if directions <= 0 || len(frames)%directions != 0 {
+		return nil, fmt.Errorf("%d frames can't be split into %d directions", len(frames), directions)
+	}
+
+	if len(frames) == 0 {
+		return nil, errors.New("sprite has no frames")
+	}
+
+	result := d2dc6.New()
+	result.Version = version
+	result.Flags = flags
+	result.Termination = []byte{terminator, terminator, terminator, terminator}
+	result.Directions = uint32(directions)
+	result.FramesPerDirection = uint32(len(frames) / directions)
+
+	for idx, frame := range frames {
+		if frame.Width <= 0 || frame.Height <= 0 {
+			return nil, fmt.Errorf("frame %d: invalid size %dx%d", idx, frame.Width, frame.Height)
+		}
+
+		if len(frame.Indices) != frame.Width*frame.Height {
+			return nil, fmt.Errorf("frame %d: expected %d pixels, got %d", idx, frame.Width*frame.Height, len(frame.Indices))
+		}
+
+		result.Frames = append(result.Frames, newFrame(&frames[idx]))
+	}
+
+	UpdatePointers(result)
+
+	return result, nil
+}
+
+// UpdatePointers recalculates frame pointers (and NextBlock fields) of the sprite;
+// it should be called after frame data were changed
+func UpdatePointers(sprite *d2dc6.DC6) {
+	sprite.FramePointers = make([]uint32, len(sprite.Frames))
+	offset := uint32(headerSize + framePointerSize*len(sprite.Frames))
+
+	for idx, frame := range sprite.Frames {
+		sprite.FramePointers[idx] = offset
+		frame.Length = uint32(len(frame.FrameData))
+		offset += uint32(frameHeaderSize) + frame.Length + uint32(terminatorSize)
+		frame.NextBlock = offset
+	}
+}
@@ -0,0 +1,53 @@
+package hsdc6
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+)
+
+func TestNew_MarshalLoad(t *testing.T) {
+	frames := []Frame{
+		{Width: 3, Height: 2, Indices: []byte{0, 1, 0, 2, 2, 0}},
+		{Width: 2, Height: 2, Indices: []byte{0, 0, 0, 0}},
+	}
+
+	sprite, err := New(1, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := d2dc6.Load(sprite.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.Frames) != len(frames) {
+		t.Fatalf("expected %d frames, got %d", len(frames), len(loaded.Frames))
+	}
+
+	for idx, frame := range frames {
+		if decoded := loaded.DecodeFrame(idx); !bytes.Equal(decoded, frame.Indices) {
+			t.Fatalf("frame %d: expected %v, got %v", idx, frame.Indices, decoded)
+		}
+	}
+}
+
+func TestEncodeFrame_LongRuns(t *testing.T) {
+	const width = 300
+
+	indices := make([]byte, width)
+	for idx := 150; idx < width; idx++ {
+		indices[idx] = 5
+	}
+
+	sprite, err := New(1, []Frame{{Width: width, Height: 1, Indices: indices}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded := sprite.DecodeFrame(0); !bytes.Equal(decoded, indices) {
+		t.Fatal("long runs weren't encoded correctly")
+	}
+}
@@ -0,0 +1,75 @@
+package hsdc6
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+const (
+	// alphaThreshold is a minimal opacity of pixel, which isn't converted to transparent one
+	alphaThreshold = 0x40
+	maxAlpha       = 0xff
+)
+
+// Quantizer maps colors to the nearest color of palette (excluding transparent index 0)
+type Quantizer struct {
+	palette color.Palette
+	cache   map[color.RGBA]byte
+}
+
+// NewQuantizer creates a new quantizer of palette given
+func NewQuantizer(colors *[256]color.RGBA) *Quantizer {
+	result := &Quantizer{
+		palette: make(color.Palette, len(colors)-1),
+		cache:   make(map[color.RGBA]byte),
+	}
+
+	for idx := 1; idx < len(colors); idx++ {
+		result.palette[idx-1] = colors[idx]
+	}
+
+	return result
+}
+
+// Index returns palette index of color; (almost) transparent colors are mapped to 0
+func (q *Quantizer) Index(c color.RGBA) byte {
+	if c.A < alphaThreshold {
+		return 0
+	}
+
+	// image.RGBA stores alpha-premultiplied colors, so semi-transparent edges
+	// become darker, like antialiased text printed on a dark background
+	c.A = maxAlpha
+
+	if idx, found := q.cache[c]; found {
+		return idx
+	}
+
+	idx := byte(q.palette.Index(c) + 1)
+	q.cache[c] = idx
+
+	return idx
+}
+
+// FrameFromImage converts image into an indexed frame
+func FrameFromImage(img image.Image, q *Quantizer) Frame {
+	bounds := img.Bounds()
+
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(bounds)
+		draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
+	}
+
+	w, h := bounds.Dx(), bounds.Dy()
+	indices := make([]byte, w*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			indices[x+y*w] = q.Index(rgba.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return Frame{Width: w, Height: h, Indices: indices}
+}
@@ -0,0 +1,160 @@
+package hsdcc
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dcc"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdc6"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+)
+
+// isUsualDirectionCount returns true if the game looks up directions of animations
+// with n directions (see d2dcc.Dir64ToDcc)
+func isUsualDirectionCount(n int) bool {
+	switch n {
+	case 1, 4, 8, 16, 32:
+		return true
+	}
+
+	return false
+}
+
+// FromDC6 converts DC6 sprite into DCC animation; directions, frames per direction and frame
+// offsets are kept. Warnings describe what DCC can't represent exactly (see Encode for palette)
+func FromDC6(sprite *d2dc6.DC6, palette *[256]color.RGBA) (data []byte, warnings []string, err error) {
+	numDirections, framesPerDirection := int(sprite.Directions), int(sprite.FramesPerDirection)
+
+	if len(sprite.Frames) != numDirections*framesPerDirection {
+		return nil, nil, fmt.Errorf("sprite has %d frames, expected %d", len(sprite.Frames), numDirections*framesPerDirection)
+	}
+
+	if !isUsualDirectionCount(numDirections) {
+		warnings = append(warnings, fmt.Sprintf("the game expects 1, 4, 8, 16 or 32 directions, sprite has %d", numDirections))
+	}
+
+	directions := make([][]Frame, numDirections)
+	empty := 0
+
+	for dir := range directions {
+		directions[dir] = make([]Frame, framesPerDirection)
+
+		for idx := range directions[dir] {
+			frameIdx := dir*framesPerDirection + idx
+			frame := sprite.Frames[frameIdx]
+			width, height := int(frame.Width), int(frame.Height)
+
+			// DC6 draws frames above OffsetY, DCC frames end at their y offset
+			if width == 0 || height == 0 {
+				empty++
+
+				directions[dir][idx] = Frame{Left: int(frame.OffsetX), Top: int(frame.OffsetY) - 1, Width: 1, Height: 1, Indices: []byte{0}}
+
+				continue
+			}
+
+			directions[dir][idx] = Frame{
+				Left:    int(frame.OffsetX),
+				Top:     int(frame.OffsetY) - height,
+				Width:   width,
+				Height:  height,
+				Indices: sprite.DecodeFrame(frameIdx),
+			}
+		}
+	}
+
+	if empty > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d empty frame(s) were stored as 1x1 transparent frames", empty))
+	}
+
+	data, reduced, err := Encode(directions, palette)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding DCC: %w", err)
+	}
+
+	if reduced > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d cell(s) of 4x4 pixels had more than 4 colors and were reduced "+
+			"to the nearest ones (DCC can't store more)", reduced))
+	}
+
+	return data, warnings, nil
+}
+
+// DirectionFrames returns frames of decoded DCC direction cropped to their boxes
+func DirectionFrames(dir *d2dcc.DCCDirection) []Frame {
+	result := make([]Frame, len(dir.Frames))
+
+	for idx, frame := range dir.Frames {
+		box := frame.Box
+		indices := make([]byte, box.Width*box.Height)
+
+		// frame's pixel data covers the whole direction's box
+		for y := 0; y < box.Height; y++ {
+			start := box.Left - dir.Box.Left + (box.Top-dir.Box.Top+y)*dir.Box.Width
+			copy(indices[y*box.Width:(y+1)*box.Width], frame.PixelData[start:start+box.Width])
+		}
+
+		result[idx] = Frame{Left: box.Left, Top: box.Top, Width: box.Width, Height: box.Height, Indices: indices}
+	}
+
+	return result
+}
+
+// ToDC6 converts DCC animation into DC6 sprite; directions, frames per direction and frame offsets are kept.
+// DC6 can represent any DCC frame, so there is nothing to warn about
+func ToDC6(dcc *d2dcc.DCC) (*d2dc6.DC6, error) {
+	frames := make([]hsdc6.Frame, 0, dcc.NumberOfDirections*dcc.FramesPerDirection)
+
+	for _, dir := range dcc.Directions {
+		for _, frame := range DirectionFrames(dir) {
+			frames = append(frames, hsdc6.Frame{
+				Width:   frame.Width,
+				Height:  frame.Height,
+				OffsetX: frame.Left,
+				OffsetY: frame.Top + frame.Height,
+				Indices: frame.Indices,
+			})
+		}
+	}
+
+	sprite, err := hsdc6.New(len(dcc.Directions), frames)
+	if err != nil {
+		return nil, fmt.Errorf("error creating DC6: %w", err)
+	}
+
+	return sprite, nil
+}
+
+// Marshal encodes decoded DCC animation again (e.g. after its pixels were changed); cells of
+// decoded animation never have more than 4 colors, so nothing is lost
+func Marshal(dcc *d2dcc.DCC) ([]byte, error) {
+	directions := make([][]Frame, len(dcc.Directions))
+
+	for idx, dir := range dcc.Directions {
+		directions[idx] = DirectionFrames(dir)
+	}
+
+	data, _, err := Encode(directions, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Remap replaces palette indices of all frames with the ones of table given (see hspalette.RemapTable);
+// pixels outside of frame boxes are kept transparent. Use Marshal to encode the remapped animation
+func Remap(dcc *d2dcc.DCC, table *[256]byte) {
+	for _, dir := range dcc.Directions {
+		for _, frame := range dir.Frames {
+			box := frame.Box
+
+			for y := 0; y < box.Height; y++ {
+				start := box.Left - dir.Box.Left + (box.Top-dir.Box.Top+y)*dir.Box.Width
+				hspalette.Remap(frame.PixelData[start:start+box.Width], table)
+			}
+		}
+	}
+}
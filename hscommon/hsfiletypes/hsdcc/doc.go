@@ -0,0 +1,4 @@
+// Package hsdcc contains helpers for creating DCC animations: it encodes indexed
+// (palettized) frames into DCC's cell based bitstreams (which d2dcc can only decode)
+// and converts animations between DC6 and DCC formats.
+package hsdcc
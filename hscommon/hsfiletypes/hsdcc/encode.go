@@ -0,0 +1,481 @@
+package hsdcc
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+)
+
+const (
+	signature     = 0x74
+	version       = 6
+	maxDirections = 0xff
+
+	fileHeaderSize      = 15
+	directionOffsetSize = 4
+
+	bitsPerByte          = 8
+	outSizeBits          = 32
+	compressionFlagsBits = 2
+	bitWidthCodeBits     = 4
+	streamSizeBits       = 20
+	pixelMaskBits        = 4
+	displacementBits     = 4
+
+	// cellSize is a width and height of DCC cell; a cell can't have more than cellColors colors
+	cellSize         = 4
+	cellColors       = 4
+	fullPixelMask    = 0x0f
+	maxDisplacement  = 15
+	paletteSize      = 256
+	signedMinBits    = 2
+	maxFieldBitWidth = 32
+)
+
+// Frame is a single indexed frame of DCC direction; Indices[x+y*Width] is a palette index of pixel
+// (0 is transparent). Left and Top is a position of frame's top left pixel relative to the anchor
+type Frame struct {
+	Left, Top     int
+	Width, Height int
+	Indices       []byte
+}
+
+// bitWriter collects bits (the least significant bit first) the way d2datautils.BitMuncher reads them
+type bitWriter struct {
+	data []byte
+	size int
+}
+
+func (w *bitWriter) push(value uint32, bits int) {
+	for i := 0; i < bits; i++ {
+		if w.size%bitsPerByte == 0 {
+			w.data = append(w.data, 0)
+		}
+
+		if value&(1<<uint(i)) != 0 {
+			w.data[w.size/bitsPerByte] |= 1 << uint(w.size%bitsPerByte)
+		}
+
+		w.size++
+	}
+}
+
+// pushWriter appends all bits of other writer; DCC bitstreams follow each other without byte alignment
+func (w *bitWriter) pushWriter(other *bitWriter) {
+	for i := 0; i < other.size; i++ {
+		w.push(uint32(other.data[i/bitsPerByte]>>uint(i%bitsPerByte)), 1)
+	}
+}
+
+// pushDisplacement writes pixel stack displacement as 4-bit chunks; 15 means "add the next chunk too"
+func (w *bitWriter) pushDisplacement(displacement int) {
+	for ; displacement >= maxDisplacement; displacement -= maxDisplacement {
+		w.push(maxDisplacement, displacementBits)
+	}
+
+	w.push(uint32(displacement), displacementBits)
+}
+
+// fieldWidth returns the code (an index in DCC's bit width table) and the number of bits
+// of the narrowest field, which can store all values given
+func fieldWidth(signed bool, values []int) (code uint32, bits int) {
+	widths := []int{0, 1, 2, 4, 6, 8, 10, 12, 14, 16, 20, 24, 26, 28, 30, 32}
+
+	fits := func(v, bits int) bool {
+		switch {
+		case bits == maxFieldBitWidth:
+			return true
+		case !signed:
+			return v >= 0 && v < 1<<uint(bits)
+		case bits < signedMinBits:
+			// a single bit stores 0 or -1
+			return v == 0 || (bits == 1 && v == -1)
+		default:
+			return v >= -(1<<uint(bits-1)) && v < 1<<uint(bits-1)
+		}
+	}
+
+	for idx, width := range widths {
+		ok := true
+
+		for _, v := range values {
+			if !fits(v, width) {
+				ok = false
+
+				break
+			}
+		}
+
+		if ok {
+			return uint32(idx), width
+		}
+	}
+
+	return uint32(len(widths) - 1), maxFieldBitWidth
+}
+
+// cellSizes splits frame's width (or height) into cells the way d2dcc does it; cells are aligned
+// to direction's box, so the first cell could be narrower and the last one could be 5 pixels wide
+func cellSizes(size, offset int) []int {
+	first := cellSize - offset%cellSize
+
+	if size-first <= 1 {
+		return []int{size}
+	}
+
+	tmp := size - first - 1
+	count := 2 + tmp/cellSize
+
+	if tmp%cellSize == 0 {
+		count--
+	}
+
+	result := make([]int, count)
+	result[0] = first
+
+	for idx := 1; idx < count-1; idx++ {
+		result[idx] = cellSize
+	}
+
+	result[count-1] = size - first - cellSize*(count-2)
+
+	return result
+}
+
+// nearestColor returns the color of colors, which is the most similar to c
+func nearestColor(c byte, colors []byte, palette *[256]color.RGBA) byte {
+	distance := func(a, b byte) int {
+		if palette == nil {
+			d := int(a) - int(b)
+
+			return d * d
+		}
+
+		dr := int(palette[a].R) - int(palette[b].R)
+		dg := int(palette[a].G) - int(palette[b].G)
+		db := int(palette[a].B) - int(palette[b].B)
+
+		return dr*dr + dg*dg + db*db
+	}
+
+	result, best := colors[0], -1
+
+	for _, candidate := range colors {
+		if candidate == 0 {
+			continue
+		}
+
+		if d := distance(c, candidate); best < 0 || d < best {
+			result, best = candidate, d
+		}
+	}
+
+	return result
+}
+
+// cellColorMap maps colors of cell to at most 4 colors: transparent pixels and the most frequent
+// colors are kept, other colors are replaced by the nearest kept ones
+func cellColorMap(counts map[byte]int, palette *[256]color.RGBA) (mapping map[byte]byte, reduced bool) {
+	colors := make([]byte, 0, len(counts))
+	for c := range counts {
+		colors = append(colors, c)
+	}
+
+	sort.Slice(colors, func(i, j int) bool {
+		if (colors[i] == 0) != (colors[j] == 0) {
+			return colors[i] == 0
+		}
+
+		if counts[colors[i]] != counts[colors[j]] {
+			return counts[colors[i]] > counts[colors[j]]
+		}
+
+		return colors[i] < colors[j]
+	})
+
+	mapping = make(map[byte]byte, len(colors))
+	reduced = len(colors) > cellColors
+
+	if reduced {
+		for _, c := range colors[cellColors:] {
+			mapping[c] = nearestColor(c, colors[:cellColors], palette)
+		}
+
+		colors = colors[:cellColors]
+	}
+
+	for _, c := range colors {
+		mapping[c] = c
+	}
+
+	return mapping, reduced
+}
+
+// encodeCell writes cell's pixel stack (its colors as displacements of palette entry codes) and
+// pixels (indices in the stack); it returns true if cell's colors had to be reduced.
+//
+// The decoder fills cell's 4 values with the stack in the reverse order and the rest with code 0
+// (transparent); then it reads no bits per pixel if the first two values are equal, 1 bit if
+// the second and the third are equal and 2 bits otherwise
+func encodeCell(frame *Frame, cell image.Rectangle, codes *[paletteSize]byte, palette *[256]color.RGBA,
+	displacements, pixels *bitWriter) bool {
+	counts := make(map[byte]int)
+
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		for x := cell.Min.X; x < cell.Max.X; x++ {
+			counts[frame.Indices[x+y*frame.Width]]++
+		}
+	}
+
+	mapping, reduced := cellColorMap(counts, palette)
+
+	stack := make([]int, 0, cellColors)
+
+	for c := range counts {
+		if mapping[c] == c && c != 0 {
+			stack = append(stack, int(codes[c]))
+		}
+	}
+
+	sort.Ints(stack)
+
+	last := 0
+
+	for _, code := range stack {
+		displacements.pushDisplacement(code - last)
+		last = code
+	}
+
+	if len(stack) < cellColors {
+		// repeated value terminates the stack
+		displacements.pushDisplacement(0)
+	}
+
+	if len(stack) == 0 {
+		return reduced
+	}
+
+	slots := make(map[int]uint32, cellColors)
+	slots[0] = uint32(len(stack))
+
+	for idx, code := range stack {
+		slots[code] = uint32(len(stack) - 1 - idx)
+	}
+
+	bits := 2
+	if len(stack) == 1 {
+		bits = 1
+	}
+
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		for x := cell.Min.X; x < cell.Max.X; x++ {
+			c := mapping[frame.Indices[x+y*frame.Width]]
+			pixels.push(slots[int(codes[c])], bits)
+		}
+	}
+
+	return reduced
+}
+
+// validateFrames checks frames and returns their bounding box
+func validateFrames(frames []Frame) (image.Rectangle, error) {
+	var box image.Rectangle
+
+	for idx := range frames {
+		frame := &frames[idx]
+
+		if frame.Width <= 0 || frame.Height <= 0 {
+			return box, fmt.Errorf("frame %d: invalid size %dx%d", idx, frame.Width, frame.Height)
+		}
+
+		if len(frame.Indices) != frame.Width*frame.Height {
+			return box, fmt.Errorf("frame %d: expected %d pixels, got %d", idx, frame.Width*frame.Height, len(frame.Indices))
+		}
+
+		rect := image.Rect(frame.Left, frame.Top, frame.Left+frame.Width, frame.Top+frame.Height)
+
+		if idx == 0 {
+			box = rect
+		} else {
+			box = box.Union(rect)
+		}
+	}
+
+	return box, nil
+}
+
+// encodeDirection encodes frames of a single direction; all cells get a full pixel mask and
+// no optional streams (equal cells, encoding type and raw pixel codes) are written
+func encodeDirection(frames []Frame, palette *[256]color.RGBA) (data []byte, reducedCells int, err error) {
+	box, err := validateFrames(frames)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var used [paletteSize]bool
+
+	used[0] = true
+
+	for idx := range frames {
+		for _, c := range frames[idx].Indices {
+			used[c] = true
+		}
+	}
+
+	// pixels are coded with indices in the list of palette entries used by direction
+	var codes [paletteSize]byte
+
+	count := 0
+
+	for idx := range used {
+		if used[idx] {
+			codes[idx] = byte(count)
+			count++
+		}
+	}
+
+	cellsX := 1 + (box.Dx()-1)/cellSize
+	cellUsed := make([]bool, cellsX*(1+(box.Dy()-1)/cellSize))
+
+	var pixelMasks, displacements, pixels bitWriter
+
+	for idx := range frames {
+		frame := &frames[idx]
+		originX, originY := frame.Left-box.Min.X, frame.Top-box.Min.Y
+
+		y := 0
+
+		for cellY, h := range cellSizes(frame.Height, originY) {
+			x := 0
+
+			for cellX, w := range cellSizes(frame.Width, originX) {
+				current := originX/cellSize + cellX + (originY/cellSize+cellY)*cellsX
+
+				// the first cell at this position always gets a full mask, others read it
+				if cellUsed[current] {
+					pixelMasks.push(fullPixelMask, pixelMaskBits)
+				}
+
+				cellUsed[current] = true
+
+				if encodeCell(frame, image.Rect(x, y, x+w, y+h), &codes, palette, &displacements, &pixels) {
+					reducedCells++
+				}
+
+				x += w
+			}
+
+			y += h
+		}
+	}
+
+	if pixelMasks.size >= 1<<streamSizeBits {
+		return nil, 0, errors.New("direction is too large")
+	}
+
+	widths, heights := make([]int, len(frames)), make([]int, len(frames))
+	lefts, bottoms := make([]int, len(frames)), make([]int, len(frames))
+	outSize := 0
+
+	for idx := range frames {
+		frame := &frames[idx]
+		widths[idx], heights[idx] = frame.Width, frame.Height
+		lefts[idx], bottoms[idx] = frame.Left, frame.Top+frame.Height-1
+		outSize += frame.Width * frame.Height
+	}
+
+	widthCode, widthBits := fieldWidth(false, widths)
+	heightCode, heightBits := fieldWidth(false, heights)
+	xCode, xBits := fieldWidth(true, lefts)
+	yCode, yBits := fieldWidth(true, bottoms)
+
+	var result bitWriter
+
+	result.push(uint32(outSize), outSizeBits)
+	result.push(0, compressionFlagsBits)
+
+	for _, code := range []uint32{0, widthCode, heightCode, xCode, yCode, 0, 0} {
+		result.push(code, bitWidthCodeBits)
+	}
+
+	for idx := range frames {
+		result.push(uint32(widths[idx]), widthBits)
+		result.push(uint32(heights[idx]), heightBits)
+		result.push(uint32(lefts[idx]), xBits)
+		result.push(uint32(bottoms[idx]), yBits)
+		result.push(0, 1) // frame isn't bottom-up
+	}
+
+	result.push(uint32(pixelMasks.size), streamSizeBits)
+
+	for _, isUsed := range used {
+		if isUsed {
+			result.push(1, 1)
+		} else {
+			result.push(0, 1)
+		}
+	}
+
+	result.pushWriter(&pixelMasks)
+	result.pushWriter(&displacements)
+	result.pushWriter(&pixels)
+
+	return result.data, reducedCells, nil
+}
+
+// Encode creates a DCC animation of frames given (directions[d][f] is frame f of direction d).
+// A DCC cell (4x4 pixels) can't have more than 4 colors, so colors of such cells are reduced to
+// the nearest colors of palette (or the nearest indices, if palette is nil); the number of
+// reduced cells is returned
+func Encode(directions [][]Frame, palette *[256]color.RGBA) (data []byte, reducedCells int, err error) {
+	if len(directions) == 0 || len(directions[0]) == 0 {
+		return nil, 0, errors.New("animation has no frames")
+	}
+
+	if len(directions) > maxDirections {
+		return nil, 0, fmt.Errorf("DCC can't have more than %d directions", maxDirections)
+	}
+
+	framesPerDirection := len(directions[0])
+	encoded := make([][]byte, len(directions))
+	size := fileHeaderSize + directionOffsetSize*len(directions)
+
+	for idx, frames := range directions {
+		if len(frames) != framesPerDirection {
+			return nil, 0, fmt.Errorf("direction %d: expected %d frames, got %d", idx, framesPerDirection, len(frames))
+		}
+
+		dirData, reduced, err := encodeDirection(frames, palette)
+		if err != nil {
+			return nil, 0, fmt.Errorf("direction %d: %w", idx, err)
+		}
+
+		encoded[idx] = dirData
+		reducedCells += reduced
+		size += len(dirData)
+	}
+
+	sw := d2datautils.CreateStreamWriter()
+
+	sw.PushBytes(signature, version, byte(len(directions)))
+	sw.PushUint32(uint32(framesPerDirection))
+	sw.PushUint32(1)
+	sw.PushUint32(uint32(size))
+
+	offset := fileHeaderSize + directionOffsetSize*len(directions)
+
+	for _, dirData := range encoded {
+		sw.PushUint32(uint32(offset))
+		offset += len(dirData)
+	}
+
+	for _, dirData := range encoded {
+		sw.PushBytes(dirData...)
+	}
+
+	return sw.GetBytes(), reducedCells, nil
+}
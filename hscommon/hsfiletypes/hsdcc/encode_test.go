@@ -0,0 +1,201 @@
+package hsdcc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dcc"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdc6"
+)
+
+// testFrame creates a frame of 3 colors (including transparent one)
+func testFrame(left, top, width, height int, seed byte) Frame {
+	indices := make([]byte, width*height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (x+y)%3 != 0 {
+				indices[x+y*width] = seed + byte(x%2)*37
+			}
+		}
+	}
+
+	return Frame{Left: left, Top: top, Width: width, Height: height, Indices: indices}
+}
+
+func checkFrame(t *testing.T, dir *d2dcc.DCCDirection, idx int, expected *Frame) {
+	t.Helper()
+
+	frame := dir.Frames[idx]
+	if frame.Box.Left != expected.Left || frame.Box.Top != expected.Top ||
+		frame.Box.Width != expected.Width || frame.Box.Height != expected.Height {
+		t.Fatalf("frame %d: expected box %v, got %v", idx, expected, frame.Box)
+	}
+
+	for y := 0; y < expected.Height; y++ {
+		start := expected.Left - dir.Box.Left + (expected.Top-dir.Box.Top+y)*dir.Box.Width
+		got := frame.PixelData[start : start+expected.Width]
+		want := expected.Indices[y*expected.Width : (y+1)*expected.Width]
+
+		if !bytes.Equal(got, want) {
+			t.Fatalf("frame %d, line %d: expected %v, got %v", idx, y, want, got)
+		}
+	}
+}
+
+// stripesFrame creates a frame of many colors, each cell has a single one
+func stripesFrame() Frame {
+	const width, height = 4, 4 * 60
+
+	indices := make([]byte, width*height)
+	for idx := range indices {
+		indices[idx] = byte(1 + idx/(width*cellSize)*4)
+	}
+
+	return Frame{Top: -height, Width: width, Height: height, Indices: indices}
+}
+
+func TestEncode_Load(t *testing.T) {
+	tests := [][][]Frame{
+		{
+			{testFrame(-10, -30, 13, 21, 10), testFrame(-7, -33, 9, 26, 100), testFrame(-12, -20, 1, 1, 5)},
+			{testFrame(0, 0, 4, 4, 200), testFrame(3, 2, 6, 5, 20), testFrame(-2, -1, 17, 9, 3)},
+		},
+		{{stripesFrame()}},
+	}
+
+	for _, directions := range tests {
+		data, reduced, err := Encode(directions, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if reduced != 0 {
+			t.Fatalf("expected no reduced cells, got %d", reduced)
+		}
+
+		dcc, err := d2dcc.Load(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dcc.NumberOfDirections != len(directions) || dcc.FramesPerDirection != len(directions[0]) {
+			t.Fatalf("expected %dx%d frames, got %dx%d", len(directions), len(directions[0]),
+				dcc.NumberOfDirections, dcc.FramesPerDirection)
+		}
+
+		for dirIdx, frames := range directions {
+			for idx := range frames {
+				checkFrame(t, dcc.Directions[dirIdx], idx, &frames[idx])
+			}
+		}
+	}
+}
+
+func TestEncode_ReducesColors(t *testing.T) {
+	frame := Frame{Width: 4, Height: 2, Indices: []byte{0, 1, 2, 3, 40, 40, 40, 41}}
+
+	data, reduced, err := Encode([][]Frame{{frame}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reduced != 1 {
+		t.Fatalf("expected 1 reduced cell, got %d", reduced)
+	}
+
+	dcc, err := d2dcc.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// transparent pixels and the most frequent colors are kept, others are replaced by the nearest indices
+	expected := Frame{Width: 4, Height: 2, Indices: []byte{0, 1, 2, 2, 40, 40, 40, 40}}
+
+	checkFrame(t, dcc.Directions[0], 0, &expected)
+}
+
+func TestConvert_RoundTrip(t *testing.T) {
+	frames := []hsdc6.Frame{
+		{Width: 3, Height: 2, OffsetX: -1, OffsetY: 5, Indices: []byte{0, 1, 0, 2, 2, 0}},
+		{Width: 2, Height: 3, OffsetX: 4, OffsetY: -2, Indices: []byte{7, 7, 0, 0, 9, 9}},
+	}
+
+	sprite, err := hsdc6.New(2, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, warnings, err := FromDC6(sprite, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("expected a warning about direction count, got %v", warnings)
+	}
+
+	dcc, err := d2dcc.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converted, err := ToDC6(dcc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if converted.Directions != 2 || converted.FramesPerDirection != 1 {
+		t.Fatalf("expected 2 directions of 1 frame, got %d of %d", converted.Directions, converted.FramesPerDirection)
+	}
+
+	for idx, frame := range frames {
+		got := converted.Frames[idx]
+		if int(got.OffsetX) != frame.OffsetX || int(got.OffsetY) != frame.OffsetY {
+			t.Fatalf("frame %d: expected offset %d,%d, got %d,%d", idx, frame.OffsetX, frame.OffsetY, got.OffsetX, got.OffsetY)
+		}
+
+		if decoded := converted.DecodeFrame(idx); !bytes.Equal(decoded, frame.Indices) {
+			t.Fatalf("frame %d: expected %v, got %v", idx, frame.Indices, decoded)
+		}
+	}
+}
+
+func TestRemap_Marshal(t *testing.T) {
+	frame := testFrame(-3, -7, 9, 6, 10)
+
+	data, _, err := Encode([][]Frame{{frame}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dcc, err := d2dcc.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var table [256]byte
+	for idx := range table {
+		table[idx] = byte(idx)
+	}
+
+	table[10], table[47] = 200, 201
+
+	Remap(dcc, &table)
+
+	remapped, err := Marshal(dcc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dcc, err = d2dcc.Load(remapped); err != nil {
+		t.Fatal(err)
+	}
+
+	for idx, c := range frame.Indices {
+		frame.Indices[idx] = table[c]
+	}
+
+	checkFrame(t, dcc.Directions[0], 0, &frame)
+}
@@ -0,0 +1,7 @@
+// Package hsds1 contains helpers for DS1 maps: it resolves presets of map's objects
+// (monsters and objects, which are referenced by act, type and ID) to their names
+// using obj.txt, objects.txt and monpreset.txt tables, resizes maps, adds or
+// deletes their layers within the limits of DS1's version, converts maps
+// between versions, encodes them the way they are loaded, resolves their
+// tile records to tiles of DT1 files and updates lists of the DT1 files.
+package hsds1
@@ -0,0 +1,79 @@
+package hsds1
+
+import (
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+)
+
+const ds1FilePrefix = `\d2\`
+
+// DS1File converts path of a DT1 file (e.g. data\global\tiles\act1\town\floor.dt1)
+// to the form, in which DS1 lists it (\d2\data\global\tiles\act1\town\floor.tg1)
+func DS1File(path string) string {
+	path = DT1Path(path)
+
+	return ds1FilePrefix + strings.TrimSuffix(path, ".dt1") + ".tg1"
+}
+
+// listsFile returns true if files (as listed in DS1) contain DT1 file of path given
+func listsFile(files []string, path string) bool {
+	path = DT1Path(path)
+
+	for _, file := range files {
+		if DT1Path(file) == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReplaceFiles updates DT1 files listed in DS1 after tiles were moved between the files:
+// the first listed file of oldPaths is replaced with newPaths and other listed files
+// of oldPaths are removed; files listed already aren't listed twice.
+// Returns false (and leaves DS1 unchanged) if DS1 lists none of oldPaths
+func ReplaceFiles(ds1 *d2ds1.DS1, oldPaths, newPaths []string) bool {
+	kept := make([]string, 0, len(ds1.Files))
+
+	for _, file := range ds1.Files {
+		if !listsFile(oldPaths, file) {
+			kept = append(kept, file)
+		}
+	}
+
+	if len(kept) == len(ds1.Files) {
+		return false
+	}
+
+	files := make([]string, 0, len(ds1.Files)+len(newPaths))
+	replaced := false
+
+	for _, file := range ds1.Files {
+		if !listsFile(oldPaths, file) {
+			files = append(files, file)
+
+			continue
+		}
+
+		if replaced {
+			continue
+		}
+
+		replaced = true
+
+		for _, path := range newPaths {
+			switch {
+			case DT1Path(path) == DT1Path(file):
+				// keep the way DS1 lists the file
+				files = append(files, file)
+			case !listsFile(kept, path) && !listsFile(files, path):
+				files = append(files, DS1File(path))
+			}
+		}
+	}
+
+	ds1.Files = files
+
+	return true
+}
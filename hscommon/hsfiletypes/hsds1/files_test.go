@@ -0,0 +1,40 @@
+package hsds1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+)
+
+func TestReplaceFiles(t *testing.T) {
+	ds1 := &d2ds1.DS1{Files: []string{
+		`/d2/data/global/tiles/ACT1/Town/Floor.tg1`,
+		`\d2\data\global\tiles\act1\town\fence.tg1`,
+		`\d2\data\global\tiles\act1\town\trees.tg1`,
+	}}
+
+	// split: extracted tiles are listed next to the source file
+	if !ReplaceFiles(ds1, []string{`data\global\tiles\act1\town\floor.dt1`},
+		[]string{`data\global\tiles\act1\town\floor.dt1`, `data/global/tiles/act1/town/path.dt1`}) {
+		t.Fatal("expected DS1 to be updated")
+	}
+
+	// merge: trees and fence are merged into fence
+	ReplaceFiles(ds1, []string{`data\global\tiles\act1\town\trees.dt1`, `data\global\tiles\act1\town\fence.dt1`},
+		[]string{`data\global\tiles\act1\town\fence.dt1`})
+
+	expected := []string{
+		`/d2/data/global/tiles/ACT1/Town/Floor.tg1`,
+		`\d2\data\global\tiles\act1\town\path.tg1`,
+		`\d2\data\global\tiles\act1\town\fence.tg1`,
+	}
+
+	if !reflect.DeepEqual(ds1.Files, expected) {
+		t.Fatalf("unexpected files %v", ds1.Files)
+	}
+
+	if ReplaceFiles(ds1, []string{`data\global\tiles\act2\floor.dt1`}, nil) {
+		t.Fatal("DS1 doesn't list the file")
+	}
+}
@@ -0,0 +1,108 @@
+package hsds1
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+)
+
+// LayerLimits are minimum and maximum numbers of layers of a group, which can be stored in DS1
+type LayerLimits struct {
+	Min, Max int
+}
+
+// Limits returns how many layers of type given can be stored in DS1 of its version
+func Limits(ds1 *d2ds1.DS1, t d2ds1.LayerGroupType) LayerLimits {
+	version := ds1.Version()
+
+	if version < versionWithLayerCounts {
+		return LayerLimits{Min: 1, Max: 1}
+	}
+
+	switch t {
+	case d2ds1.WallLayerGroup:
+		return LayerLimits{Min: 1, Max: d2ds1.GetMaxGroupLen(t)}
+	case d2ds1.FloorLayerGroup:
+		if version < versionWithFloorCount {
+			return LayerLimits{Min: 1, Max: 1}
+		}
+
+		return LayerLimits{Min: 1, Max: d2ds1.GetMaxGroupLen(t)}
+	case d2ds1.ShadowLayerGroup:
+		return LayerLimits{Min: 1, Max: 1}
+	case d2ds1.SubstitutionLayerGroup:
+		if version < versionWithSubstitutionType {
+			return LayerLimits{Min: 0, Max: 0}
+		}
+
+		return LayerLimits{Min: 0, Max: d2ds1.GetMaxGroupLen(t)}
+	}
+
+	return LayerLimits{}
+}
+
+// CanAddLayer returns an error describing why a layer of type given can't be added
+func CanAddLayer(ds1 *d2ds1.DS1, t d2ds1.LayerGroupType) error {
+	limits := Limits(ds1, t)
+	if len(*ds1.GetLayersGroup(t)) >= limits.Max {
+		return fmt.Errorf("DS1 version %d can store at most %d %s layer(s)", ds1.Version(), limits.Max, t)
+	}
+
+	return nil
+}
+
+// CanDeleteLayer returns an error describing why a layer of type given can't be deleted
+func CanDeleteLayer(ds1 *d2ds1.DS1, t d2ds1.LayerGroupType) error {
+	limits := Limits(ds1, t)
+	if len(*ds1.GetLayersGroup(t)) <= limits.Min {
+		return fmt.Errorf("DS1 version %d should have at least %d %s layer(s)", ds1.Version(), limits.Min, t)
+	}
+
+	return nil
+}
+
+// AddLayer inserts an empty layer of type given at index idx (or appends it if idx is out of range).
+// Adding a substitution layer sets DS1's substitution type, so that the layer is saved
+func AddLayer(ds1 *d2ds1.DS1, t d2ds1.LayerGroupType, idx int) error {
+	if err := CanAddLayer(ds1, t); err != nil {
+		return err
+	}
+
+	group := ds1.GetLayersGroup(t)
+	layer := (&d2ds1.Layer{}).SetSize(ds1.Width(), ds1.Height())
+
+	if idx < 0 || idx > len(*group) {
+		idx = len(*group)
+	}
+
+	*group = append((*group)[:idx], append([]*d2ds1.Layer{layer}, (*group)[idx:]...)...)
+
+	if t == d2ds1.SubstitutionLayerGroup &&
+		(ds1.SubstitutionType <= noSubstitutionType || ds1.SubstitutionType > maxSubstitutionType) {
+		ds1.SubstitutionType = defaultSubstitutionType
+	}
+
+	return nil
+}
+
+// DeleteLayer deletes layer of type given at index idx.
+// Deleting the substitution layer clears DS1's substitution type and substitution groups
+func DeleteLayer(ds1 *d2ds1.DS1, t d2ds1.LayerGroupType, idx int) error {
+	if err := CanDeleteLayer(ds1, t); err != nil {
+		return err
+	}
+
+	group := ds1.GetLayersGroup(t)
+	if idx < 0 || idx >= len(*group) {
+		return fmt.Errorf("there is no %s layer %d", t, idx)
+	}
+
+	*group = append((*group)[:idx], (*group)[idx+1:]...)
+
+	if t == d2ds1.SubstitutionLayerGroup && len(*group) == 0 && ds1.Version() >= versionWithSubstitutionType {
+		ds1.SubstitutionType = noSubstitutionType
+		ds1.SubstitutionGroups = make([]d2ds1.SubstitutionGroup, 0)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package hsds1
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+)
+
+const (
+	dwordSize             = 4
+	substitutionGroupSize = 5 * dwordSize
+	npcHeaderSize         = 3 * dwordSize
+	pathPointSize         = 2 * dwordSize
+	pathPointWithAction   = 3 * dwordSize
+	// d2ds1 writes path actions since version 15
+	versionWithEncodedActions = 15
+)
+
+func hasSubstitutionGroups(ds1 *d2ds1.DS1) bool {
+	return ds1.Version() >= versionWithSubstitutionGroups &&
+		ds1.SubstitutionType > noSubstitutionType && ds1.SubstitutionType <= maxSubstitutionType
+}
+
+// Marshal encodes DS1 so that it can be loaded for its version. d2ds1's Marshal is used for
+// everything but the end of the file, which it doesn't encode the way it is read:
+// it writes substitutions' unknown dword for versions older than 18, NPC paths for versions
+// older than 15 and actions of paths for version 15 and it takes paths of wrong objects
+func Marshal(ds1 *d2ds1.DS1) []byte {
+	data := ds1.Marshal()
+
+	// length of NPCs section as written by d2ds1
+	npcLength := dwordSize
+	pathLength := pathPointSize
+
+	if ds1.Version() >= versionWithEncodedActions {
+		pathLength = pathPointWithAction
+	}
+
+	numNPCs := 0
+
+	for _, obj := range ds1.Objects {
+		if len(obj.Paths) > 0 {
+			numNPCs++
+		}
+	}
+
+	for idx := 0; idx < numNPCs; idx++ {
+		npcLength += npcHeaderSize + len(ds1.Objects[idx].Paths)*pathLength
+	}
+
+	substitutionsLength := 0
+	if hasSubstitutionGroups(ds1) {
+		substitutionsLength = 2*dwordSize + len(ds1.SubstitutionGroups)*substitutionGroupSize
+	}
+
+	bodyLength := len(data) - npcLength - substitutionsLength
+
+	sw := d2datautils.CreateStreamWriter()
+	sw.PushBytes(data[:bodyLength]...)
+
+	if substitutionsLength > 0 {
+		substitutions := data[bodyLength : bodyLength+substitutionsLength]
+
+		if ds1.Version() < versionWithUnknown2 {
+			substitutions = substitutions[dwordSize:]
+		}
+
+		sw.PushBytes(substitutions...)
+	}
+
+	if ds1.Version() >= versionWithNPCs {
+		encodeNPCs(ds1, sw)
+	}
+
+	return sw.GetBytes()
+}
+
+// encodeNPCs encodes paths of objects, the way d2ds1 reads them
+func encodeNPCs(ds1 *d2ds1.DS1, sw *d2datautils.StreamWriter) {
+	npcs := make([]*d2ds1.Object, 0)
+
+	for idx := range ds1.Objects {
+		if len(ds1.Objects[idx].Paths) > 0 {
+			npcs = append(npcs, &ds1.Objects[idx])
+		}
+	}
+
+	sw.PushInt32(int32(len(npcs)))
+
+	for _, obj := range npcs {
+		sw.PushInt32(int32(len(obj.Paths)))
+		sw.PushInt32(int32(obj.X))
+		sw.PushInt32(int32(obj.Y))
+
+		for _, path := range obj.Paths {
+			sw.PushInt32(int32(path.Position.X()))
+			sw.PushInt32(int32(path.Position.Y()))
+
+			if ds1.Version() >= versionWithNPCActions {
+				sw.PushInt32(int32(path.Action))
+			}
+		}
+	}
+}
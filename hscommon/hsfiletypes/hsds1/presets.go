@@ -0,0 +1,270 @@
+package hsds1
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsbin"
+)
+
+const (
+	objPath       = `data\global\obj.txt`
+	objectsPath   = `data\global\excel\objects.txt`
+	monPresetPath = `data\global\excel\monpreset.txt`
+)
+
+// FileReader reads a file of path given (MPQ notation, e.g. data\global\obj.txt)
+type FileReader func(path string) ([]byte, error)
+
+// Preset is a monster or an object, which can be placed on a map
+type Preset struct {
+	Type d2enum.ObjectType
+	ID   int
+	Name string
+}
+
+// TypeName returns a human-readable name of preset's type
+func (p *Preset) TypeName() string {
+	return TypeName(p.Type)
+}
+
+// TypeName returns a human-readable name of DS1 object's type
+func TypeName(t d2enum.ObjectType) string {
+	switch t {
+	case d2enum.ObjectTypeCharacter:
+		return "Monster"
+	case d2enum.ObjectTypeItem:
+		return "Object"
+	}
+
+	return fmt.Sprintf("Type %d", t)
+}
+
+type presetKey struct {
+	objType d2enum.ObjectType
+	id      int
+}
+
+// Presets are monster and object presets of an act
+type Presets struct {
+	Act     int
+	presets []Preset
+	lookup  map[presetKey]int
+}
+
+// LoadPresets loads presets of act given: monsters are taken from monpreset.txt (and obj.txt
+// if monpreset.txt doesn't list them), names of objects are looked up in objects.txt
+// by obj.txt's objectsTxtId. Missing tables are skipped; an error is returned only if
+// neither obj.txt nor monpreset.txt could be read
+func LoadPresets(read FileReader, act int) (*Presets, error) {
+	result := &Presets{
+		Act:    act,
+		lookup: make(map[presetKey]int),
+	}
+
+	monPresetErr := result.loadMonPresets(read)
+
+	objectNames, err := loadObjectNames(read)
+	if err != nil {
+		objectNames = make(map[int]string)
+	}
+
+	objErr := result.loadObj(read, objectNames)
+
+	if monPresetErr != nil && objErr != nil {
+		return nil, fmt.Errorf("error loading presets: %w", objErr)
+	}
+
+	sort.Slice(result.presets, func(i, j int) bool {
+		a, b := result.presets[i], result.presets[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+
+		return a.ID < b.ID
+	})
+
+	for idx := range result.presets {
+		p := &result.presets[idx]
+		result.lookup[presetKey{p.Type, p.ID}] = idx
+	}
+
+	return result, nil
+}
+
+func loadTable(read FileReader, path string) (*hsbin.Table, error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	table, err := hsbin.LoadTXT(data)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", path, err)
+	}
+
+	return table, nil
+}
+
+// cell returns trimmed value of row's column or empty string if row is too short
+func cell(row []string, column int) string {
+	if column < 0 || column >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[column])
+}
+
+// number returns value of row's column as a number; ok is false if the cell is not a number
+func number(row []string, column int) (n int, ok bool) {
+	n, err := strconv.Atoi(cell(row, column))
+
+	return n, err == nil
+}
+
+// loadMonPresets adds monsters of act; their IDs are indices of act's rows in monpreset.txt
+func (p *Presets) loadMonPresets(read FileReader) error {
+	table, err := loadTable(read, monPresetPath)
+	if err != nil {
+		return err
+	}
+
+	actColumn, placeColumn := table.ColumnIndex("Act"), table.ColumnIndex("Place")
+	if actColumn < 0 || placeColumn < 0 {
+		return fmt.Errorf("%s has no Act or Place column", monPresetPath)
+	}
+
+	id := 0
+
+	for _, row := range table.Rows {
+		if act, ok := number(row, actColumn); !ok || act != p.Act {
+			continue
+		}
+
+		p.add(Preset{Type: d2enum.ObjectTypeCharacter, ID: id, Name: cell(row, placeColumn)})
+		id++
+	}
+
+	return nil
+}
+
+// loadObjectNames returns names of objects.txt records by their IDs
+func loadObjectNames(read FileReader) (map[int]string, error) {
+	table, err := loadTable(read, objectsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	idColumn, nameColumn := table.ColumnIndex("Id"), table.ColumnIndex("Name")
+	if idColumn < 0 || nameColumn < 0 {
+		return nil, fmt.Errorf("%s has no Id or Name column", objectsPath)
+	}
+
+	result := make(map[int]string)
+
+	for _, row := range table.Rows {
+		if id, ok := number(row, idColumn); ok {
+			result[id] = cell(row, nameColumn)
+		}
+	}
+
+	return result, nil
+}
+
+// loadObj adds presets of act listed in obj.txt, which weren't added from monpreset.txt
+func (p *Presets) loadObj(read FileReader, objectNames map[int]string) error {
+	table, err := loadTable(read, objPath)
+	if err != nil {
+		return err
+	}
+
+	actColumn, typeColumn, idColumn := table.ColumnIndex("Act"), table.ColumnIndex("Type"), table.ColumnIndex("Id")
+	descriptionColumn, objectsIDColumn := table.ColumnIndex("Description"), table.ColumnIndex("objectsTxtId")
+
+	if actColumn < 0 || typeColumn < 0 || idColumn < 0 {
+		return fmt.Errorf("%s has no Act, Type or Id column", objPath)
+	}
+
+	// descriptions end with act's table name, e.g. "gheed-ACT 1 TABLE"
+	tableSuffix := fmt.Sprintf("-ACT %d TABLE", p.Act)
+
+	for _, row := range table.Rows {
+		act, actOk := number(row, actColumn)
+		objType, typeOk := number(row, typeColumn)
+		id, idOk := number(row, idColumn)
+
+		if !actOk || !typeOk || !idOk || act != p.Act {
+			continue
+		}
+
+		if _, found := p.find(d2enum.ObjectType(objType), id); found {
+			continue
+		}
+
+		name := strings.TrimSuffix(cell(row, descriptionColumn), tableSuffix)
+
+		if objectsID, ok := number(row, objectsIDColumn); ok {
+			if objectName, found := objectNames[objectsID]; found && objectName != "" {
+				name = objectName
+			}
+		}
+
+		p.add(Preset{Type: d2enum.ObjectType(objType), ID: id, Name: name})
+	}
+
+	return nil
+}
+
+func (p *Presets) add(preset Preset) {
+	p.lookup[presetKey{preset.Type, preset.ID}] = len(p.presets)
+	p.presets = append(p.presets, preset)
+}
+
+func (p *Presets) find(objType d2enum.ObjectType, id int) (*Preset, bool) {
+	idx, found := p.lookup[presetKey{objType, id}]
+	if !found {
+		return nil, false
+	}
+
+	return &p.presets[idx], true
+}
+
+// Name returns name of preset of type and ID given; found is false if act has no such preset
+func (p *Presets) Name(objType, id int) (name string, found bool) {
+	if p == nil {
+		return "", false
+	}
+
+	preset, found := p.find(d2enum.ObjectType(objType), id)
+	if !found {
+		return "", false
+	}
+
+	return preset.Name, true
+}
+
+// Search returns presets, which names contain query (case-insensitive) or which ID is query;
+// empty query matches all presets
+func (p *Presets) Search(query string) []Preset {
+	if p == nil {
+		return nil
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	id, err := strconv.Atoi(query)
+	isID := err == nil
+
+	result := make([]Preset, 0)
+
+	for _, preset := range p.presets {
+		if query == "" || strings.Contains(strings.ToLower(preset.Name), query) || (isID && preset.ID == id) {
+			result = append(result, preset)
+		}
+	}
+
+	return result
+}
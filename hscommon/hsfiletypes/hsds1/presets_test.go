@@ -0,0 +1,71 @@
+package hsds1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func testReader(files map[string]string) FileReader {
+	return func(path string) ([]byte, error) {
+		data, found := files[path]
+		if !found {
+			return nil, errors.New("file not found")
+		}
+
+		return []byte(data), nil
+	}
+}
+
+func TestLoadPresets(t *testing.T) {
+	read := testReader(map[string]string{
+		monPresetPath: "Act\tPlace\r\n1\tgheed\r\n2\twarriv2\r\n1\tcain1\r\n",
+		objectsPath:   "Name\tId\r\nChest\t5\r\nStash\t267\r\n",
+		objPath: "Act\tType\tId\tDescription\tobjectsTxtId\r\n" +
+			"1\t1\t0\tgheed-ACT 1 TABLE\t-1\r\n" +
+			"1\t1\t40\trogue2-ACT 1 TABLE\t-1\r\n" +
+			"1\t2\t5\tchest-ACT 1 TABLE\t5\r\n" +
+			"1\t2\t7\ttorch-ACT 1 TABLE\t-1\r\n" +
+			"2\t2\t5\tchest-ACT 2 TABLE\t5\r\n",
+	})
+
+	presets, err := LoadPresets(read, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		objType d2enum.ObjectType
+		id      int
+		name    string
+	}{
+		{d2enum.ObjectTypeCharacter, 0, "gheed"},
+		{d2enum.ObjectTypeCharacter, 1, "cain1"},
+		{d2enum.ObjectTypeCharacter, 40, "rogue2"},
+		{d2enum.ObjectTypeItem, 5, "Chest"},
+		{d2enum.ObjectTypeItem, 7, "torch"},
+	}
+
+	for _, e := range expected {
+		if name, found := presets.Name(int(e.objType), e.id); !found || name != e.name {
+			t.Fatalf("type %d, id %d: expected %q, got %q (found: %v)", e.objType, e.id, e.name, name, found)
+		}
+	}
+
+	if _, found := presets.Name(int(d2enum.ObjectTypeCharacter), 2); found {
+		t.Fatal("monster of another act should not be found")
+	}
+
+	if result := presets.Search("CHE"); len(result) != 1 || result[0].Name != "Chest" {
+		t.Fatalf("unexpected search result %v", result)
+	}
+
+	if result := presets.Search("7"); len(result) != 1 || result[0].Name != "torch" {
+		t.Fatalf("unexpected search result %v", result)
+	}
+
+	if _, err := LoadPresets(testReader(nil), 1); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,227 @@
+package hsds1
+
+import (
+	"image"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2path"
+)
+
+// Region is a rectangular part of a map; it contains tiles of chosen layer groups,
+// objects (with paths) placed in the region relatively to its top left corner and
+// DT1 files (as listed in the map it was copied from), which the copied tiles resolve to
+type Region struct {
+	Width, Height int
+	// Layers contains tiles (row by row) of copied layers of each group
+	Layers  map[d2ds1.LayerGroupType][][]d2ds1.Tile
+	Objects []d2ds1.Object
+	Files   []string
+}
+
+// PasteResult describes how the target map was changed by pasting a region
+type PasteResult struct {
+	AddedFiles []string
+	// layers of the region, which couldn't be pasted, because DS1's version can't store so many layers
+	SkippedLayers int
+	// objects of the region, which would be outside of the target map
+	SkippedObjects int
+}
+
+// Clipboard holds a region copied from a map, so that it can be pasted into another one
+type Clipboard struct {
+	region *Region
+}
+
+// Set puts region into clipboard
+func (c *Clipboard) Set(region *Region) {
+	c.region = region
+}
+
+// Region returns region of the clipboard or nil if it is empty
+func (c *Clipboard) Region() *Region {
+	if c == nil {
+		return nil
+	}
+
+	return c.region
+}
+
+// Copy copies region of the map given by rect (in tiles) with layers of groups given;
+// objects are copied if withObjects is set. DT1 files of the map are read to find files,
+// which the copied tiles resolve to
+func Copy(read FileReader, ds1 *d2ds1.DS1, rect image.Rectangle, groups []d2ds1.LayerGroupType,
+	withObjects bool) *Region {
+	rect = rect.Intersect(image.Rect(0, 0, ds1.Width(), ds1.Height()))
+
+	result := &Region{
+		Width:   rect.Dx(),
+		Height:  rect.Dy(),
+		Layers:  make(map[d2ds1.LayerGroupType][][]d2ds1.Tile),
+		Objects: make([]d2ds1.Object, 0),
+	}
+
+	for _, t := range groups {
+		for _, layer := range *ds1.GetLayersGroup(t) {
+			tiles := make([]d2ds1.Tile, 0, rect.Dx()*rect.Dy())
+
+			for y := rect.Min.Y; y < rect.Max.Y; y++ {
+				for x := rect.Min.X; x < rect.Max.X; x++ {
+					tiles = append(tiles, *layer.Tile(x, y))
+				}
+			}
+
+			result.Layers[t] = append(result.Layers[t], tiles)
+		}
+	}
+
+	result.Files = usedFiles(read, ds1.Files, result.Layers)
+
+	if !withObjects {
+		return result
+	}
+
+	subtiles := image.Rectangle{Min: rect.Min.Mul(SubtilesPerTile), Max: rect.Max.Mul(SubtilesPerTile)}
+	offset := subtiles.Min.Mul(-1)
+
+	for _, obj := range ds1.Objects {
+		if !image.Pt(obj.X, obj.Y).In(subtiles) {
+			continue
+		}
+
+		result.Objects = append(result.Objects, shiftObject(obj, offset))
+	}
+
+	return result
+}
+
+// usedFiles returns files of the list, which tiles of layers resolve to. Files, which couldn't
+// be loaded, are kept, because it is unknown, whether the tiles use them
+func usedFiles(read FileReader, files []string, layers map[d2ds1.LayerGroupType][][]d2ds1.Tile) []string {
+	tiles, failed := loadTiles(read, files)
+	used := make(map[string]bool)
+
+	for _, f := range failed {
+		used[f.file] = true
+	}
+
+	for _, t := range []d2ds1.LayerGroupType{d2ds1.FloorLayerGroup, d2ds1.WallLayerGroup, d2ds1.ShadowLayerGroup} {
+		for _, records := range layers[t] {
+			for idx := range records {
+				record := &records[idx]
+				if record.Prop1 == 0 || record.Hidden() {
+					continue
+				}
+
+				key := TileKey{Type: tileType(record, t), Style: record.Style, Sequence: record.Sequence}
+
+				for _, variant := range resolveKey(key, tiles).Variants {
+					used[variant.File] = true
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0)
+
+	for _, file := range files {
+		if used[file] {
+			result = append(result, file)
+		}
+	}
+
+	return result
+}
+
+// shiftObject returns a copy of object (and its paths) shifted by offset (in subtiles)
+func shiftObject(obj d2ds1.Object, offset image.Point) d2ds1.Object {
+	obj.X += offset.X
+	obj.Y += offset.Y
+
+	paths := make([]d2path.Path, len(obj.Paths))
+
+	for idx, path := range obj.Paths {
+		paths[idx] = d2path.Path{
+			Action:   path.Action,
+			Position: d2vector.NewPosition(path.Position.X()+float64(offset.X), path.Position.Y()+float64(offset.Y)),
+		}
+	}
+
+	obj.Paths = paths
+
+	return obj
+}
+
+// Paste pastes region into the map with its top left corner on tile x, y. Tiles outside of the map
+// are skipped; missing layers are added if DS1's version can store them. DT1 files, which the region's
+// tiles resolve to, are added to the map's file list, so that tiles reference the same DT1 files
+func Paste(ds1 *d2ds1.DS1, region *Region, x, y int) PasteResult {
+	result := PasteResult{AddedFiles: make([]string, 0)}
+
+	for t, layers := range region.Layers {
+		group := ds1.GetLayersGroup(t)
+
+		for idx, tiles := range layers {
+			if idx >= len(*group) {
+				if err := AddLayer(ds1, t, len(*group)); err != nil {
+					result.SkippedLayers++
+
+					continue
+				}
+			}
+
+			pasteTiles((*group)[idx], region, tiles, x, y)
+		}
+	}
+
+	bounds := image.Rect(0, 0, ds1.Width()*SubtilesPerTile, ds1.Height()*SubtilesPerTile)
+	offset := image.Pt(x, y).Mul(SubtilesPerTile)
+
+	for _, obj := range region.Objects {
+		obj = shiftObject(obj, offset)
+
+		if !image.Pt(obj.X, obj.Y).In(bounds) {
+			result.SkippedObjects++
+
+			continue
+		}
+
+		ds1.Objects = append(ds1.Objects, obj)
+	}
+
+	for _, file := range region.Files {
+		if !hasFile(ds1.Files, file) {
+			ds1.Files = append(ds1.Files, file)
+			result.AddedFiles = append(result.AddedFiles, file)
+		}
+	}
+
+	return result
+}
+
+func pasteTiles(layer *d2ds1.Layer, region *Region, tiles []d2ds1.Tile, x, y int) {
+	width, height := layer.Size()
+
+	for ry := 0; ry < region.Height; ry++ {
+		for rx := 0; rx < region.Width; rx++ {
+			tx, ty := x+rx, y+ry
+			if tx < 0 || ty < 0 || tx >= width || ty >= height {
+				continue
+			}
+
+			*layer.Tile(tx, ty) = tiles[ry*region.Width+rx]
+		}
+	}
+}
+
+// hasFile returns true if files contain path (file paths are case-insensitive)
+func hasFile(files []string, path string) bool {
+	for _, file := range files {
+		if strings.EqualFold(file, path) {
+			return true
+		}
+	}
+
+	return false
+}
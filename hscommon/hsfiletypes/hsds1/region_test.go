@@ -0,0 +1,71 @@
+package hsds1
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2path"
+)
+
+func TestCopyPaste(t *testing.T) {
+	source := testDS1(t, 3, 3)
+	source.Files = []string{
+		`/Act1/Outdoors/Grass.tt`, `/Act1/Outdoors/Tree.tt`, `/Act1/Outdoors/Fence.tt`, `/Act1/Outdoors/Missing.tt`,
+	}
+	source.Objects = []d2ds1.Object{
+		{ID: 1, X: 7, Y: 12, Paths: []d2path.Path{{Position: d2vector.NewPosition(8, 13), Action: 1}}},
+		{ID: 2, X: 1, Y: 1},
+	}
+
+	// copied floors (style 0, sequence 0) resolve to grass and tree; fence isn't used by them,
+	// missing file can't be loaded, so it's kept
+	read := testReader(map[string]string{
+		`data\global\tiles\act1\outdoors\grass.tt`: string(testDT1(TileKey{Type: d2enum.TileFloor})),
+		`data\global\tiles\act1\outdoors\tree.tt`:  string(testDT1(TileKey{Type: d2enum.TileFloor})),
+		`data\global\tiles\act1\outdoors\fence.tt`: string(testDT1(TileKey{Type: d2enum.TileLeftWall})),
+	})
+
+	// tiles (1, 2) - (2, 2) with the first object
+	region := Copy(read, source, image.Rect(1, 2, 3, 4), []d2ds1.LayerGroupType{d2ds1.FloorLayerGroup}, true)
+	if region.Width != 2 || region.Height != 1 || len(region.Objects) != 1 {
+		t.Fatalf("unexpected region %dx%d with %d objects", region.Width, region.Height, len(region.Objects))
+	}
+
+	expected := []string{`/Act1/Outdoors/Grass.tt`, `/Act1/Outdoors/Tree.tt`, `/Act1/Outdoors/Missing.tt`}
+	if !reflect.DeepEqual(region.Files, expected) {
+		t.Fatalf("unexpected region's files %v", region.Files)
+	}
+
+	target := testDS1(t, 2, 2)
+	target.Files = []string{`/act1/outdoors/grass.tt`}
+
+	result := Paste(target, region, 1, 0)
+
+	added := []string{`/Act1/Outdoors/Tree.tt`, `/Act1/Outdoors/Missing.tt`}
+	if !reflect.DeepEqual(result.AddedFiles, added) || result.SkippedLayers != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// source tile (1, 2) has Prop1 8; the other tile of the region is outside of the target map
+	if prop1 := target.Floors[0].Tile(1, 0).Prop1; prop1 != 8 {
+		t.Fatalf("expected pasted tile to have Prop1 8, got %d", prop1)
+	}
+
+	if prop1 := target.Floors[0].Tile(0, 0).Prop1; prop1 != 1 {
+		t.Fatalf("tile outside of the region should be kept, got Prop1 %d", prop1)
+	}
+
+	obj := target.Objects[len(target.Objects)-1]
+	if obj.X != 7 || obj.Y != 2 || obj.Paths[0].Position.X() != 8 || obj.Paths[0].Position.Y() != 3 {
+		t.Fatalf("unexpected pasted object %+v", obj)
+	}
+
+	// source object's path is not changed
+	if source.Objects[0].Paths[0].Position.X() != 8 {
+		t.Fatal("source object's path was changed")
+	}
+}
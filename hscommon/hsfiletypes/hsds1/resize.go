@@ -0,0 +1,148 @@
+package hsds1
+
+import (
+	"errors"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+)
+
+// SubtilesPerTile is a number of subtiles (which objects and paths are placed on) per tile's side
+const SubtilesPerTile = 5
+
+const anchorsPerRow = 3
+
+// Anchor is a point of the map, which stays in place when the map is resized
+type Anchor int
+
+// Anchors
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+	NumAnchors int = iota
+)
+
+func (a Anchor) String() string {
+	lookup := map[Anchor]string{
+		AnchorTopLeft:     "Top left",
+		AnchorTop:         "Top",
+		AnchorTopRight:    "Top right",
+		AnchorLeft:        "Left",
+		AnchorCenter:      "Center",
+		AnchorRight:       "Right",
+		AnchorBottomLeft:  "Bottom left",
+		AnchorBottom:      "Bottom",
+		AnchorBottomRight: "Bottom right",
+	}
+
+	return lookup[a]
+}
+
+// offset returns a shift of map's content in tiles, when map is resized by dw x dh tiles
+func (a Anchor) offset(dw, dh int) (dx, dy int) {
+	column, row := int(a)%anchorsPerRow, int(a)/anchorsPerRow
+
+	// left/top anchored maps grow to the right/bottom, centered ones to both sides
+	return dw * column / (anchorsPerRow - 1), dh * row / (anchorsPerRow - 1)
+}
+
+// Resize resizes the map to width x height tiles; the anchor decides which edges grow or shrink.
+// Tiles of all layers, objects, their paths and substitution groups are shifted together.
+// Objects, which end up outside of the map, are removed and their number is returned;
+// path points outside of the map are moved to its nearest edge
+func Resize(ds1 *d2ds1.DS1, width, height int, anchor Anchor) (removedObjects int, err error) {
+	if width < 1 || height < 1 {
+		return 0, errors.New("map should be at least 1x1 tiles")
+	}
+
+	oldWidth, oldHeight := ds1.Width(), ds1.Height()
+	dx, dy := anchor.offset(width-oldWidth, height-oldHeight)
+
+	for _, t := range []d2ds1.LayerGroupType{
+		d2ds1.FloorLayerGroup, d2ds1.WallLayerGroup, d2ds1.ShadowLayerGroup, d2ds1.SubstitutionLayerGroup,
+	} {
+		group := ds1.GetLayersGroup(t)
+
+		for idx, layer := range *group {
+			(*group)[idx] = shiftLayer(layer, width, height, dx, dy)
+		}
+	}
+
+	ds1.SetSize(width, height)
+
+	removedObjects = shiftObjects(ds1, dx, dy)
+
+	for idx := range ds1.SubstitutionGroups {
+		ds1.SubstitutionGroups[idx].TileX += int32(dx)
+		ds1.SubstitutionGroups[idx].TileY += int32(dy)
+	}
+
+	return removedObjects, nil
+}
+
+// shiftLayer returns a layer of width x height tiles with tiles of layer given shifted by dx, dy
+func shiftLayer(layer *d2ds1.Layer, width, height, dx, dy int) *d2ds1.Layer {
+	result := (&d2ds1.Layer{}).SetSize(width, height)
+	oldWidth, oldHeight := layer.Size()
+
+	for y := 0; y < oldHeight; y++ {
+		for x := 0; x < oldWidth; x++ {
+			nx, ny := x+dx, y+dy
+			if nx < 0 || ny < 0 || nx >= width || ny >= height {
+				continue
+			}
+
+			*result.Tile(nx, ny) = *layer.Tile(x, y)
+		}
+	}
+
+	return result
+}
+
+// shiftObjects shifts objects and their paths by dx, dy tiles and removes objects outside of the map
+func shiftObjects(ds1 *d2ds1.DS1, dx, dy int) (removed int) {
+	maxX, maxY := ds1.Width()*SubtilesPerTile-1, ds1.Height()*SubtilesPerTile-1
+	objects := ds1.Objects[:0]
+
+	for _, obj := range ds1.Objects {
+		obj.X += dx * SubtilesPerTile
+		obj.Y += dy * SubtilesPerTile
+
+		if obj.X < 0 || obj.Y < 0 || obj.X > maxX || obj.Y > maxY {
+			removed++
+
+			continue
+		}
+
+		for idx := range obj.Paths {
+			position := &obj.Paths[idx].Position
+			x := clamp(int(position.X())+dx*SubtilesPerTile, maxX)
+			y := clamp(int(position.Y())+dy*SubtilesPerTile, maxY)
+			*position = d2vector.NewPosition(float64(x), float64(y))
+		}
+
+		objects = append(objects, obj)
+	}
+
+	ds1.Objects = objects
+
+	return removed
+}
+
+func clamp(v, max int) int {
+	switch {
+	case v < 0:
+		return 0
+	case v > max:
+		return max
+	}
+
+	return v
+}
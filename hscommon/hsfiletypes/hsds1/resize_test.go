@@ -0,0 +1,132 @@
+package hsds1
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2path"
+)
+
+// testDS1 returns version 18 map of width x height tiles with a wall, a floor and a shadow layer;
+// floor tiles have Prop1 set to x + y*width + 1
+func testDS1(t *testing.T, width, height int) *d2ds1.DS1 {
+	const (
+		version        = 18
+		numLayerStream = 4 // wall, orientation, floor and shadow
+		floorStream    = 2
+	)
+
+	sw := d2datautils.CreateStreamWriter()
+	sw.PushInt32(version)
+	sw.PushInt32(int32(width - 1))
+	sw.PushInt32(int32(height - 1))
+	sw.PushInt32(0) // act 1
+	sw.PushInt32(0) // substitution type
+	sw.PushInt32(0) // files
+	sw.PushInt32(1) // walls
+	sw.PushInt32(1) // floors
+
+	for stream := 0; stream < numLayerStream; stream++ {
+		for idx := 0; idx < width*height; idx++ {
+			if stream == floorStream {
+				sw.PushUint32(uint32(idx + 1))
+			} else {
+				sw.PushUint32(0)
+			}
+		}
+	}
+
+	sw.PushInt32(0) // objects
+	sw.PushInt32(0) // npcs
+
+	ds1, err := d2ds1.Unmarshal(sw.GetBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ds1
+}
+
+func TestResize(t *testing.T) {
+	ds1 := testDS1(t, 2, 2)
+	ds1.Objects = []d2ds1.Object{
+		{X: 1, Y: 2},
+		{X: 7, Y: 7, Paths: []d2path.Path{{Position: d2vector.NewPosition(9, 0)}}},
+	}
+
+	// shrink from the left and grow up by one tile
+	removed, err := Resize(ds1, 1, 3, AnchorBottomRight)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w, h := ds1.Size(); w != 1 || h != 3 {
+		t.Fatalf("unexpected size %dx%d", w, h)
+	}
+
+	// old tile (1, 0) moved to (0, 1), old column 0 was cut off
+	expected := [][]byte{{0}, {2}, {4}}
+	for y := range expected {
+		for x := range expected[y] {
+			if prop1 := ds1.Floors[0].Tile(x, y).Prop1; prop1 != expected[y][x] {
+				t.Fatalf("tile %d, %d: expected %d, got %d", x, y, expected[y][x], prop1)
+			}
+		}
+	}
+
+	if removed != 1 || len(ds1.Objects) != 1 {
+		t.Fatalf("expected one object to be removed, removed %d", removed)
+	}
+
+	obj := ds1.Objects[0]
+	if obj.X != 2 || obj.Y != 12 {
+		t.Fatalf("unexpected object position %d, %d", obj.X, obj.Y)
+	}
+
+	if x, y := obj.Paths[0].Position.X(), obj.Paths[0].Position.Y(); x != 4 || y != 5 {
+		t.Fatalf("unexpected path position %v, %v", x, y)
+	}
+
+	if _, err := d2ds1.Unmarshal(ds1.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	ds1 := testDS1(t, 2, 2)
+
+	if err := DeleteLayer(ds1, d2ds1.FloorLayerGroup, 0); err == nil {
+		t.Fatal("the only floor should not be deleted")
+	}
+
+	if err := AddLayer(ds1, d2ds1.FloorLayerGroup, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddLayer(ds1, d2ds1.FloorLayerGroup, 0); err == nil {
+		t.Fatal("DS1 can't have more than two floors")
+	}
+
+	if err := AddLayer(ds1, d2ds1.SubstitutionLayerGroup, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if ds1.SubstitutionType == 0 {
+		t.Fatal("substitution type should be set")
+	}
+
+	loaded, err := d2ds1.Unmarshal(ds1.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.Floors) != 2 || len(loaded.Substitutions) != 1 || loaded.Floors[1].Tile(1, 1).Prop1 != 4 {
+		t.Fatalf("unexpected layers: %d floors, %d substitutions", len(loaded.Floors), len(loaded.Substitutions))
+	}
+
+	if err := DeleteLayer(ds1, d2ds1.SubstitutionLayerGroup, 0); err != nil || ds1.SubstitutionType != 0 {
+		t.Fatalf("unexpected error %v or substitution type %d", err, ds1.SubstitutionType)
+	}
+}
@@ -0,0 +1,285 @@
+package hsds1
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+const tilesPath = `data\global\tiles\`
+
+// TileKey identifies DT1 tiles, which a DS1 tile record references
+type TileKey struct {
+	Type     d2enum.TileType
+	Style    byte
+	Sequence byte
+}
+
+func (k TileKey) String() string {
+	return fmt.Sprintf("type %d (%s), style %d, sequence %d", k.Type, k.Type, k.Style, k.Sequence)
+}
+
+// TileVariant is a DT1 tile, which a tile record resolves to;
+// all DT1 tiles with the same key are rarity variants of the tile
+type TileVariant struct {
+	// File is DT1 file as listed in DS1
+	File string
+	// Index is index of the tile in DT1
+	Index  int
+	Type   d2enum.TileType
+	Rarity int32
+}
+
+// TileResolution contains positions (in tiles) of layer's records with the same key
+// and DT1 tiles, which they resolve to
+type TileResolution struct {
+	Key       TileKey
+	Positions []image.Point
+	Variants  []TileVariant
+	// Resolved is false if any tile needed to draw the record is missing
+	Resolved bool
+}
+
+// LayerResolution contains tile records of a layer
+type LayerResolution struct {
+	Group d2ds1.LayerGroupType
+	Index int
+	Tiles []TileResolution
+}
+
+// ResolutionReport describes how tile records of DS1 resolve to tiles of its DT1 files
+type ResolutionReport struct {
+	Layers []LayerResolution
+	// FileErrors describe DT1 files, which couldn't be loaded
+	FileErrors []string
+}
+
+// Counts returns number of tile records and number of unresolved ones
+func (r *ResolutionReport) Counts() (records, unresolved int) {
+	for _, layer := range r.Layers {
+		for _, tile := range layer.Tiles {
+			records += len(tile.Positions)
+
+			if !tile.Resolved {
+				unresolved += len(tile.Positions)
+			}
+		}
+	}
+
+	return records, unresolved
+}
+
+// String returns report as a text
+func (r *ResolutionReport) String() string {
+	var sb strings.Builder
+
+	records, unresolved := r.Counts()
+	fmt.Fprintf(&sb, "%d tile record(s), %d unresolved\n", records, unresolved)
+
+	for _, fileError := range r.FileErrors {
+		fmt.Fprintf(&sb, "%s\n", fileError)
+	}
+
+	for _, layer := range r.Layers {
+		fmt.Fprintf(&sb, "\n%s layer %d\n", layer.Group, layer.Index+1)
+
+		for _, tile := range layer.Tiles {
+			fmt.Fprintf(&sb, "  %s: %d record(s)\n", tile.Key, len(tile.Positions))
+
+			if !tile.Resolved {
+				fmt.Fprintf(&sb, "    UNRESOLVED at %s\n", positionsString(tile.Positions))
+			}
+
+			for _, variant := range tile.Variants {
+				fmt.Fprintf(&sb, "    %s\n", variant)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func (v TileVariant) String() string {
+	return fmt.Sprintf("%s, tile %d (type %d), rarity %d", v.File, v.Index, v.Type, v.Rarity)
+}
+
+func positionsString(positions []image.Point) string {
+	result := make([]string, len(positions))
+
+	for idx, position := range positions {
+		result[idx] = fmt.Sprintf("(%d, %d)", position.X, position.Y)
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// DT1Path converts DT1 file listed in DS1 (e.g. C:\d2\Data\Global\Tiles\ACT1\Town\Floor.tg1)
+// to the path of the file in MPQs (data\global\tiles\act1\town\floor.dt1)
+func DT1Path(file string) string {
+	path := strings.ToLower(strings.ReplaceAll(file, "/", `\`))
+	path = strings.TrimPrefix(path, "c:")
+
+	// DS1 files list DT1s with .tg1 extension
+	if strings.HasSuffix(path, ".tg1") {
+		path = strings.TrimSuffix(path, ".tg1") + ".dt1"
+	}
+
+	if idx := strings.Index(path, tilesPath); idx >= 0 {
+		return path[idx:]
+	}
+
+	return tilesPath + strings.TrimLeft(path, `\`)
+}
+
+type dt1Tile struct {
+	file  string
+	index int
+	tile  *d2dt1.Tile
+}
+
+// fileError is an error reading or loading DT1 file listed in DS1
+type fileError struct {
+	file string
+	err  error
+}
+
+// loadTiles loads tiles of DT1 files listed in DS1; files, which couldn't be loaded, are returned as failed
+func loadTiles(read FileReader, files []string) (tiles []dt1Tile, failed []fileError) {
+	tiles, failed = make([]dt1Tile, 0), make([]fileError, 0)
+
+	for _, file := range files {
+		data, err := read(DT1Path(file))
+		if err != nil {
+			failed = append(failed, fileError{file: file, err: fmt.Errorf("error reading %s: %w", file, err)})
+
+			continue
+		}
+
+		dt1, err := d2dt1.LoadDT1(data)
+		if err != nil {
+			failed = append(failed, fileError{file: file, err: fmt.Errorf("error loading %s: %w", file, err)})
+
+			continue
+		}
+
+		for idx := range dt1.Tiles {
+			tiles = append(tiles, dt1Tile{file: file, index: idx, tile: &dt1.Tiles[idx]})
+		}
+	}
+
+	return tiles, failed
+}
+
+// ResolveTiles resolves tile records of DS1's floor, wall and shadow layers to tiles of DT1 files
+// listed in DS1 the way the game does: by type (orientation), style and sequence.
+// Empty and hidden records aren't drawn by the game, so they are skipped
+func ResolveTiles(read FileReader, ds1 *d2ds1.DS1) *ResolutionReport {
+	report := &ResolutionReport{
+		Layers:     make([]LayerResolution, 0),
+		FileErrors: make([]string, 0),
+	}
+
+	tiles, failed := loadTiles(read, ds1.Files)
+
+	for _, f := range failed {
+		report.FileErrors = append(report.FileErrors, f.err.Error())
+	}
+
+	for _, t := range []d2ds1.LayerGroupType{d2ds1.FloorLayerGroup, d2ds1.WallLayerGroup, d2ds1.ShadowLayerGroup} {
+		for idx, layer := range *ds1.GetLayersGroup(t) {
+			report.Layers = append(report.Layers, LayerResolution{
+				Group: t,
+				Index: idx,
+				Tiles: resolveLayer(layer, t, tiles),
+			})
+		}
+	}
+
+	return report
+}
+
+// resolveLayer groups used records of the layer by key (in order of appearance) and resolves them
+func resolveLayer(layer *d2ds1.Layer, t d2ds1.LayerGroupType, tiles []dt1Tile) []TileResolution {
+	result := make([]TileResolution, 0)
+	lookup := make(map[TileKey]int)
+	width, height := layer.Size()
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			record := layer.Tile(x, y)
+			if record.Prop1 == 0 || record.Hidden() {
+				continue
+			}
+
+			key := TileKey{Type: tileType(record, t), Style: record.Style, Sequence: record.Sequence}
+
+			idx, found := lookup[key]
+			if !found {
+				idx = len(result)
+				lookup[key] = idx
+
+				result = append(result, resolveKey(key, tiles))
+			}
+
+			result[idx].Positions = append(result[idx].Positions, image.Pt(x, y))
+		}
+	}
+
+	return result
+}
+
+// tileType returns type of DT1 tiles, which a record of layer group given references
+func tileType(record *d2ds1.Tile, t d2ds1.LayerGroupType) d2enum.TileType {
+	switch t {
+	case d2ds1.FloorLayerGroup:
+		return d2enum.TileFloor
+	case d2ds1.ShadowLayerGroup:
+		return d2enum.TileShadow
+	}
+
+	return record.Type
+}
+
+// resolveKey returns DT1 tiles matching the key; right part of north corner wall
+// is drawn together with the left one, so both of them have to be found
+func resolveKey(key TileKey, tiles []dt1Tile) TileResolution {
+	types := []d2enum.TileType{key.Type}
+	if key.Type == d2enum.TileRightPartOfNorthCornerWall {
+		types = append(types, d2enum.TileLeftPartOfNorthCornerWall)
+	}
+
+	result := TileResolution{
+		Key:       key,
+		Positions: make([]image.Point, 0),
+		Variants:  make([]TileVariant, 0),
+		Resolved:  true,
+	}
+
+	for _, variantType := range types {
+		found := false
+
+		for _, tile := range tiles {
+			if tile.tile.Type != int32(variantType) || tile.tile.Style != int32(key.Style) ||
+				tile.tile.Sequence != int32(key.Sequence) {
+				continue
+			}
+
+			found = true
+
+			result.Variants = append(result.Variants, TileVariant{
+				File:   tile.file,
+				Index:  tile.index,
+				Type:   variantType,
+				Rarity: tile.tile.RarityFrameIndex,
+			})
+		}
+
+		result.Resolved = result.Resolved && found
+	}
+
+	return result
+}
@@ -0,0 +1,76 @@
+package hsds1
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// testDT1 encodes DT1 (without graphics) containing tiles of keys given; every tile has rarity 1
+func testDT1(keys ...TileKey) []byte {
+	const (
+		headerSize         = 276
+		numUnknownHeader   = 260
+		numTileBytesBefore = 16 // direction, roof height, material flags, height and width
+		numTileBytesAfter  = 64 // unknown, subtile flags, unknown, block pointer, size, count and unknown
+	)
+
+	sw := d2datautils.CreateStreamWriter()
+	sw.PushInt32(7)
+	sw.PushInt32(6)
+	sw.PushBytes(make([]byte, numUnknownHeader)...)
+	sw.PushInt32(int32(len(keys)))
+	sw.PushInt32(headerSize)
+
+	for _, key := range keys {
+		sw.PushBytes(make([]byte, numTileBytesBefore)...)
+		sw.PushInt32(0)
+		sw.PushInt32(int32(key.Type))
+		sw.PushInt32(int32(key.Style))
+		sw.PushInt32(int32(key.Sequence))
+		sw.PushInt32(1)
+		sw.PushBytes(make([]byte, numTileBytesAfter)...)
+	}
+
+	return sw.GetBytes()
+}
+
+func TestResolveTiles(t *testing.T) {
+	ds1 := testDS1(t, 2, 1)
+	ds1.Files = []string{`C:\d2\Data\Global\Tiles\ACT1\Town\Floor.tg1`, `\d2\data\global\tiles\missing.tg1`}
+
+	// floor (0, 0) references style 0, sequence 0; (1, 0) is set to style 1, sequence 2
+	ds1.Floors[0].Tile(1, 0).Style = 1
+	ds1.Floors[0].Tile(1, 0).Sequence = 2
+
+	read := testReader(map[string]string{
+		`data\global\tiles\act1\town\floor.dt1`: string(testDT1(
+			TileKey{Type: d2enum.TileFloor}, TileKey{Type: d2enum.TileShadow}, TileKey{Type: d2enum.TileFloor},
+		)),
+	})
+
+	report := ResolveTiles(read, ds1)
+
+	if len(report.FileErrors) != 1 {
+		t.Fatalf("expected an error loading missing DT1, got %q", report.FileErrors)
+	}
+
+	if records, unresolved := report.Counts(); records != 2 || unresolved != 1 {
+		t.Fatalf("expected 2 records, 1 unresolved, got %d, %d", records, unresolved)
+	}
+
+	floors := report.Layers[0].Tiles
+	if len(floors) != 2 || !floors[0].Resolved || floors[1].Resolved {
+		t.Fatalf("unexpected floor resolution %+v", floors)
+	}
+
+	// both floor tiles of the DT1 are rarity variants of the record
+	if variants := floors[0].Variants; len(variants) != 2 || variants[0].Index != 0 || variants[1].Index != 2 {
+		t.Fatalf("unexpected variants %+v", variants)
+	}
+
+	if position := floors[1].Positions[0]; position.X != 1 || position.Y != 0 {
+		t.Fatalf("unexpected position of unresolved tile %v", position)
+	}
+}
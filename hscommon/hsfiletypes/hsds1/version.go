@@ -0,0 +1,176 @@
+package hsds1
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+)
+
+// DS1 versions, which change what is stored in DS1
+const (
+	// d2ds1 can't load older versions (they have a single layer of each type)
+	MinVersion = 4
+	// MaxVersion is the newest DS1 version
+	MaxVersion = 18
+	// older versions always have a single layer of each type
+	versionWithLayerCounts = 4
+	// map's act is stored since this version
+	versionWithAct = 8
+	// substitution layer is stored only when header's substitution type is 1 or 2
+	versionWithSubstitutionType = 10
+	// substitution groups are stored since this version
+	versionWithSubstitutionGroups = 12
+	// paths of objects (NPCs) are stored since this version
+	versionWithNPCs = 15
+	// number of floors is stored since this version (older ones have a single floor)
+	versionWithFloorCount = 16
+	// actions of path points are stored since this version
+	versionWithNPCActions = 16
+	// substitutions have an unknown dword since this version
+	versionWithUnknown2 = 18
+)
+
+// substitution types, which make DS1 store a substitution layer
+const (
+	noSubstitutionType      = 0
+	defaultSubstitutionType = 1
+	maxSubstitutionType     = 2
+)
+
+// StoresPaths returns true if DS1's version stores paths of objects
+func StoresPaths(ds1 *d2ds1.DS1) bool {
+	return ds1.Version() >= versionWithNPCs
+}
+
+// ConversionWarnings returns descriptions of data, which would be dropped by converting DS1 to version given
+func ConversionWarnings(ds1 *d2ds1.DS1, version int) []string {
+	return convertVersion(ds1, version, false)
+}
+
+// ConvertVersion converts DS1 to version given: data, which the version can't store, is dropped
+// (see ConversionWarnings) and missing layers are added, so that Marshal produces a file,
+// which is loaded the same way for the version
+func ConvertVersion(ds1 *d2ds1.DS1, version int) (warnings []string, err error) {
+	if version < MinVersion || version > MaxVersion {
+		return nil, fmt.Errorf("DS1 version should be between %d and %d", MinVersion, MaxVersion)
+	}
+
+	return convertVersion(ds1, version, true), nil
+}
+
+// convertVersion returns warnings about data dropped by conversion; DS1 is changed only if apply is set
+func convertVersion(ds1 *d2ds1.DS1, version int, apply bool) (warnings []string) {
+	if version < versionWithFloorCount && len(ds1.Floors) > 1 {
+		warnings = append(warnings, fmt.Sprintf("%d floor layer(s) will be dropped (versions older than %d have a single floor)",
+			len(ds1.Floors)-1, versionWithFloorCount))
+
+		if apply {
+			ds1.Floors = ds1.Floors[:1]
+		}
+	}
+
+	if version < versionWithAct && ds1.Version() >= versionWithAct {
+		warnings = append(warnings, fmt.Sprintf("act %d will not be stored (versions older than %d don't store it)",
+			ds1.Act, versionWithAct))
+	}
+
+	warnings = append(warnings, convertSubstitutions(ds1, version, apply)...)
+	warnings = append(warnings, convertPaths(ds1, version, apply)...)
+
+	if !apply {
+		return warnings
+	}
+
+	ds1.SetVersion(version)
+
+	// every version stores at least one wall, floor and shadow layer
+	for _, t := range []d2ds1.LayerGroupType{d2ds1.WallLayerGroup, d2ds1.FloorLayerGroup, d2ds1.ShadowLayerGroup} {
+		if group := ds1.GetLayersGroup(t); len(*group) == 0 {
+			*group = append(*group, (&d2ds1.Layer{}).SetSize(ds1.Width(), ds1.Height()))
+		}
+	}
+
+	return warnings
+}
+
+func convertSubstitutions(ds1 *d2ds1.DS1, version int, apply bool) (warnings []string) {
+	hasType := ds1.SubstitutionType > noSubstitutionType && ds1.SubstitutionType <= maxSubstitutionType
+
+	switch {
+	case version < versionWithSubstitutionType:
+		if len(ds1.Substitutions) > 0 || len(ds1.SubstitutionGroups) > 0 {
+			warnings = append(warnings, fmt.Sprintf("substitution layer and %d substitution group(s) will be dropped "+
+				"(versions older than %d don't store them)", len(ds1.SubstitutionGroups), versionWithSubstitutionType))
+		}
+
+		hasType = false
+	case version < versionWithSubstitutionGroups:
+		if len(ds1.SubstitutionGroups) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d substitution group(s) will be dropped "+
+				"(versions older than %d don't store them)", len(ds1.SubstitutionGroups), versionWithSubstitutionGroups))
+		}
+	case !hasType && len(ds1.Substitutions) > 0:
+		warnings = append(warnings, fmt.Sprintf("substitution layer will be dropped (substitution type is %d, "+
+			"layer is stored only for types 1 and 2)", ds1.SubstitutionType))
+	}
+
+	if !apply {
+		return warnings
+	}
+
+	if version < versionWithSubstitutionGroups || !hasType {
+		ds1.SubstitutionGroups = make([]d2ds1.SubstitutionGroup, 0)
+	}
+
+	switch {
+	case !hasType:
+		ds1.SubstitutionType = noSubstitutionType
+		ds1.Substitutions = ds1.Substitutions[:0]
+	case len(ds1.Substitutions) == 0:
+		// the layer is read for substitution types 1 and 2
+		ds1.Substitutions = append(ds1.Substitutions, (&d2ds1.Layer{}).SetSize(ds1.Width(), ds1.Height()))
+	}
+
+	return warnings
+}
+
+func convertPaths(ds1 *d2ds1.DS1, version int, apply bool) (warnings []string) {
+	numPaths, numActions := 0, 0
+
+	for _, obj := range ds1.Objects {
+		if len(obj.Paths) > 0 {
+			numPaths++
+		}
+
+		for _, path := range obj.Paths {
+			if path.Action != 0 {
+				numActions++
+			}
+		}
+	}
+
+	switch {
+	case version < versionWithNPCs && numPaths > 0:
+		warnings = append(warnings, fmt.Sprintf("paths of %d object(s) will be dropped (versions older than %d don't store them)",
+			numPaths, versionWithNPCs))
+
+		if apply {
+			for idx := range ds1.Objects {
+				ds1.Objects[idx].Paths = nil
+			}
+		}
+	case version >= versionWithNPCs && version < versionWithNPCActions && numActions > 0:
+		warnings = append(warnings, fmt.Sprintf("actions of %d path point(s) will be dropped "+
+			"(versions older than %d don't store them)", numActions, versionWithNPCActions))
+
+		if apply {
+			for idx := range ds1.Objects {
+				for pathIdx := range ds1.Objects[idx].Paths {
+					ds1.Objects[idx].Paths[pathIdx].Action = 0
+				}
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,94 @@
+package hsds1
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2path"
+)
+
+func TestConvertVersion(t *testing.T) {
+	ds1 := testDS1(t, 2, 2)
+	ds1.Objects = []d2ds1.Object{
+		{Type: 1, ID: 1, X: 1, Y: 2},
+		{Type: 1, ID: 2, X: 7, Y: 7, Paths: []d2path.Path{
+			{Position: d2vector.NewPosition(9, 0), Action: 2},
+			{Position: d2vector.NewPosition(3, 4), Action: 1},
+		}},
+	}
+
+	if err := AddLayer(ds1, d2ds1.FloorLayerGroup, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddLayer(ds1, d2ds1.SubstitutionLayerGroup, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	ds1.SubstitutionGroups = []d2ds1.SubstitutionGroup{{TileX: 1, TileY: 1, WidthInTiles: 1, HeightInTiles: 1}}
+
+	if _, err := ConvertVersion(ds1, MaxVersion+1); err == nil {
+		t.Fatal("expected an error converting to unknown version")
+	}
+
+	// floors and path actions are dropped, substitutions and paths are kept
+	warnings, err := ConvertVersion(ds1, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %q", warnings)
+	}
+
+	loaded := marshalAndLoad(t, ds1)
+
+	if len(loaded.Floors) != 1 || len(loaded.Substitutions) != 1 || len(loaded.SubstitutionGroups) != 1 {
+		t.Fatalf("unexpected layers: %d floor(s), %d substitution(s), %d group(s)",
+			len(loaded.Floors), len(loaded.Substitutions), len(loaded.SubstitutionGroups))
+	}
+
+	if len(loaded.Objects[0].Paths) != 0 || len(loaded.Objects[1].Paths) != 2 {
+		t.Fatal("paths should be loaded for the second object only")
+	}
+
+	if path := loaded.Objects[1].Paths[1]; path.Position.X() != 3 || path.Position.Y() != 4 {
+		t.Fatalf("unexpected path point %v", path.Position)
+	}
+
+	// substitutions and paths are dropped
+	warnings, err = ConvertVersion(ds1, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %q", warnings)
+	}
+
+	loaded = marshalAndLoad(t, ds1)
+
+	if loaded.Version() != 9 || len(loaded.Substitutions) != 0 || len(loaded.Objects) != 2 {
+		t.Fatalf("unexpected map: version %d, %d substitution(s), %d object(s)",
+			loaded.Version(), len(loaded.Substitutions), len(loaded.Objects))
+	}
+
+	if prop1 := loaded.Floors[0].Tile(1, 1).Prop1; prop1 != 4 {
+		t.Fatalf("unexpected floor tile %d", prop1)
+	}
+
+	// upgrading doesn't drop anything
+	if warnings := ConversionWarnings(ds1, MaxVersion); len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %q", warnings)
+	}
+}
+
+func marshalAndLoad(t *testing.T, ds1 *d2ds1.DS1) *d2ds1.DS1 {
+	loaded, err := d2ds1.Unmarshal(Marshal(ds1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loaded
+}
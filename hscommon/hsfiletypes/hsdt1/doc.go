@@ -0,0 +1,6 @@
+// Package hsdt1 contains helpers for editing DT1 tilesets: it creates floor and wall
+// tiles from indexed (palettized) images by splitting them into isometric and RLE
+// blocks, decodes tiles back into images, copies subtile flag patterns between tiles,
+// merges, splits and deduplicates tilesets and encodes DT1 files with block offsets
+// computed from scratch, so that tiles can be added, replaced and deleted.
+package hsdt1
@@ -0,0 +1,152 @@
+package hsdt1
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+const (
+	majorVersion = 7
+	minorVersion = 6
+
+	numUnknownHeaderBytes = 260
+	numUnknownTileBytes1  = 4
+	numUnknownTileBytes2  = 4
+	numUnknownTileBytes3  = 7
+	numUnknownTileBytes4  = 12
+	numUnknownBlockBytes  = 2
+
+	headerSize      = 276
+	tileHeaderSize  = 96
+	unknown2Offset  = 36 // offset of tile's unknown2 bytes in tile's header
+	blockHeaderSize = 20
+
+	isometricFormat = 1
+	rleFormat       = 0
+)
+
+// block is a block of tile's graphics, which is going to be encoded
+type block struct {
+	x, y         int16
+	gridX, gridY byte
+	format       int16
+	data         []byte
+}
+
+// tileBlocks returns blocks of tile in encodable form
+func tileBlocks(tile *d2dt1.Tile) []block {
+	result := make([]block, len(tile.Blocks))
+
+	for idx := range tile.Blocks {
+		b := &tile.Blocks[idx]
+
+		format := int16(rleFormat)
+		if b.Format() == d2dt1.BlockFormatIsometric {
+			format = isometricFormat
+		}
+
+		result[idx] = block{x: b.X, y: b.Y, gridX: b.GridX, gridY: b.GridY, format: format, data: b.EncodedData}
+	}
+
+	return result
+}
+
+// tileUnknown2 returns tile's unknown2 bytes loaded by d2dt1 (zeros for tiles created by editor);
+// d2dt1 doesn't export them, so they're taken from d2dt1's encoding of the tile's header
+func tileUnknown2(tile *d2dt1.Tile) []byte {
+	header := *tile
+	header.Blocks = nil
+
+	data := (&d2dt1.DT1{Tiles: []d2dt1.Tile{header}}).Marshal()
+	if len(data) != headerSize+tileHeaderSize {
+		return make([]byte, numUnknownTileBytes2)
+	}
+
+	start := headerSize + unknown2Offset
+
+	return data[start : start+numUnknownTileBytes2]
+}
+
+// Marshal encodes DT1. Unlike d2dt1's Marshal, it computes number of tiles and offsets of
+// tiles' blocks, so it encodes tiles, which were added, deleted or whose graphics changed.
+// Tiles' unknown2 bytes are kept, other unknown bytes of headers are written as zeros
+func Marshal(dt1 *d2dt1.DT1) []byte {
+	tiles := make([]*d2dt1.Tile, len(dt1.Tiles))
+	blocks := make([][]block, len(dt1.Tiles))
+
+	for idx := range dt1.Tiles {
+		tiles[idx] = &dt1.Tiles[idx]
+		blocks[idx] = tileBlocks(tiles[idx])
+	}
+
+	return encode(tiles, blocks)
+}
+
+// encode encodes DT1 of tiles given; blocks[i] are blocks of tiles[i] (tile's own Blocks are ignored)
+func encode(tiles []*d2dt1.Tile, blocks [][]block) []byte {
+	sw := d2datautils.CreateStreamWriter()
+
+	sw.PushInt32(majorVersion)
+	sw.PushInt32(minorVersion)
+	sw.PushBytes(make([]byte, numUnknownHeaderBytes)...)
+	sw.PushInt32(int32(len(tiles)))
+	sw.PushInt32(headerSize)
+
+	// blocks of tiles follow tile headers
+	offset := int32(headerSize + len(tiles)*tileHeaderSize)
+
+	for idx, tile := range tiles {
+		size := int32(len(blocks[idx]) * blockHeaderSize)
+		for _, b := range blocks[idx] {
+			size += int32(len(b.data))
+		}
+
+		sw.PushInt32(tile.Direction)
+		sw.PushInt16(tile.RoofHeight)
+		sw.PushUint16(tile.MaterialFlags.Encode())
+		sw.PushInt32(tile.Height)
+		sw.PushInt32(tile.Width)
+		sw.PushBytes(make([]byte, numUnknownTileBytes1)...)
+		sw.PushInt32(tile.Type)
+		sw.PushInt32(tile.Style)
+		sw.PushInt32(tile.Sequence)
+		sw.PushInt32(tile.RarityFrameIndex)
+		sw.PushBytes(tileUnknown2(tile)...)
+
+		for i := range tile.SubTileFlags {
+			sw.PushBytes(tile.SubTileFlags[i].Encode())
+		}
+
+		sw.PushBytes(make([]byte, numUnknownTileBytes3)...)
+		sw.PushInt32(offset)
+		sw.PushInt32(size)
+		sw.PushInt32(int32(len(blocks[idx])))
+		sw.PushBytes(make([]byte, numUnknownTileBytes4)...)
+
+		offset += size
+	}
+
+	for idx := range tiles {
+		// offsets of blocks' data are relative to the first block's header
+		dataOffset := int32(len(blocks[idx]) * blockHeaderSize)
+
+		for _, b := range blocks[idx] {
+			sw.PushInt16(b.x)
+			sw.PushInt16(b.y)
+			sw.PushBytes(make([]byte, numUnknownBlockBytes)...)
+			sw.PushBytes(b.gridX, b.gridY)
+			sw.PushInt16(b.format)
+			sw.PushInt32(int32(len(b.data)))
+			sw.PushBytes(make([]byte, numUnknownBlockBytes)...)
+			sw.PushInt32(dataOffset)
+
+			dataOffset += int32(len(b.data))
+		}
+
+		for _, b := range blocks[idx] {
+			sw.PushBytes(b.data...)
+		}
+	}
+
+	return sw.GetBytes()
+}
@@ -0,0 +1,67 @@
+package hsdt1
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+// NumSubtiles is number of subtiles (5x5) of a tile, each of them has its own flags
+const NumSubtiles = subtilesPerSide * subtilesPerSide
+
+// FlagPattern is a copy of subtile flags of a tile
+type FlagPattern [NumSubtiles]d2dt1.SubTileFlags
+
+// CopyFlags returns subtile flags of the tile
+func CopyFlags(tile *d2dt1.Tile) FlagPattern {
+	return tile.SubTileFlags
+}
+
+// ApplyFlags replaces subtile flags of the tiles with the pattern
+func ApplyFlags(pattern FlagPattern, tiles ...*d2dt1.Tile) {
+	for _, tile := range tiles {
+		tile.SubTileFlags = pattern
+	}
+}
+
+// ApplyFlagsToStyle replaces subtile flags of all DT1's tiles of style and sequence given
+// (of any type) with the pattern and returns number of tiles changed
+func ApplyFlagsToStyle(dt1 *d2dt1.DT1, pattern FlagPattern, style, sequence int32) int {
+	count := 0
+
+	for idx := range dt1.Tiles {
+		tile := &dt1.Tiles[idx]
+		if tile.Style != style || tile.Sequence != sequence {
+			continue
+		}
+
+		ApplyFlags(pattern, tile)
+
+		count++
+	}
+
+	return count
+}
+
+// SubtileFlag returns whether bit of subtile's flags is set
+func SubtileFlag(tile *d2dt1.Tile, subtile int, bit uint) bool {
+	return tile.SubTileFlags[subtile].Encode()&(1<<bit) != 0
+}
+
+// SetSubtileFlag sets (or clears) bit of subtile's flags
+func SetSubtileFlag(tile *d2dt1.Tile, subtile int, bit uint, value bool) {
+	flags := tile.SubTileFlags[subtile].Encode()
+
+	if value {
+		flags |= 1 << bit
+	} else {
+		flags &^= 1 << bit
+	}
+
+	tile.SubTileFlags[subtile] = d2dt1.NewSubTileFlags(flags)
+}
+
+// FillSubtileFlag sets (or clears) bit of flags of all tile's subtiles
+func FillSubtileFlag(tile *d2dt1.Tile, bit uint, value bool) {
+	for subtile := range tile.SubTileFlags {
+		SetSubtileFlag(tile, subtile, bit, value)
+	}
+}
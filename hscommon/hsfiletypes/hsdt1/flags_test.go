@@ -0,0 +1,37 @@
+package hsdt1
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+func TestApplyFlagsToStyle(t *testing.T) {
+	const (
+		blockWalk  = 0
+		blockLight = 5
+	)
+
+	dt1 := &d2dt1.DT1{Tiles: []d2dt1.Tile{
+		{Style: 1, Sequence: 2},
+		{Style: 1, Sequence: 2, Type: 1},
+		{Style: 1, Sequence: 3},
+	}}
+
+	source := &d2dt1.Tile{}
+	FillSubtileFlag(source, blockWalk, true)
+	SetSubtileFlag(source, 12, blockWalk, false)
+	SetSubtileFlag(source, 12, blockLight, true)
+
+	if SubtileFlag(source, 12, blockWalk) || !SubtileFlag(source, 12, blockLight) || !source.SubTileFlags[0].BlockWalk {
+		t.Fatal("unexpected subtile flags")
+	}
+
+	if n := ApplyFlagsToStyle(dt1, CopyFlags(source), 1, 2); n != 2 {
+		t.Fatalf("expected 2 tiles changed, got %d", n)
+	}
+
+	if dt1.Tiles[1].SubTileFlags != source.SubTileFlags || dt1.Tiles[2].SubTileFlags[0].BlockWalk {
+		t.Fatal("flags applied to wrong tiles")
+	}
+}
@@ -0,0 +1,90 @@
+package hsdt1
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+// blockHeight returns number of pixel rows of the block
+func blockHeight(b *d2dt1.Block) int {
+	if b.Format() == d2dt1.BlockFormatIsometric {
+		return isometricBlockHeight
+	}
+
+	rows, inRow := 0, false
+
+	for idx := 0; idx+1 < len(b.EncodedData); {
+		skip, run := b.EncodedData[idx], b.EncodedData[idx+1]
+		idx += 2 + int(run)
+
+		if skip|run == 0 {
+			rows++
+			inRow = false
+
+			continue
+		}
+
+		inRow = true
+	}
+
+	// the last row may miss its terminator
+	if inRow {
+		rows++
+	}
+
+	return rows
+}
+
+// Bounds returns rectangle covered by tile's pixels (relative to the top corner of the floor);
+// floors (which have positive height) start at y = 0, walls end at y = 0
+func Bounds(tile *d2dt1.Tile) image.Rectangle {
+	result := image.Rect(0, 0, int(tile.Width), 0)
+
+	if tile.Height > 0 {
+		result.Max.Y = int(tile.Height)
+	}
+
+	for idx := range tile.Blocks {
+		b := &tile.Blocks[idx]
+
+		if y := int(b.Y); y < result.Min.Y {
+			result.Min.Y = y
+		}
+
+		if y := int(b.Y) + blockHeight(b); y > result.Max.Y {
+			result.Max.Y = y
+		}
+	}
+
+	return result
+}
+
+// Indices decodes tile's graphics into palette indices of pixels (0 is transparent)
+// covering Bounds(tile); indices[x+y*width] is a pixel of row Bounds(tile).Min.Y + y
+func Indices(tile *d2dt1.Tile) (indices []byte, width, height int) {
+	bounds := Bounds(tile)
+	width, height = bounds.Dx(), bounds.Dy()
+	indices = make([]byte, width*height)
+
+	d2dt1.DecodeTileGfxData(tile.Blocks, &indices, int32(-bounds.Min.Y), int32(width))
+
+	return indices, width, height
+}
+
+// Image decodes tile's graphics into an image using the palette given
+func Image(tile *d2dt1.Tile, palette *[256]color.RGBA) *image.RGBA {
+	indices, width, height := Indices(tile)
+	result := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for idx, paletteIdx := range indices {
+		if paletteIdx == 0 {
+			continue
+		}
+
+		result.SetRGBA(idx%width, idx/width, palette[paletteIdx])
+	}
+
+	return result
+}
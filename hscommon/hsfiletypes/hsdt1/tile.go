@@ -0,0 +1,269 @@
+package hsdt1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+// Tile sizes in pixels
+const (
+	TileWidth   = 160
+	FloorHeight = 80
+)
+
+const (
+	subtilesPerSide = 5
+	// isometric blocks are 32x15 diamonds, subtiles are placed every 16x8 pixels
+	isometricBlockWidth  = 32
+	isometricBlockHeight = 15
+	isometricBlockSize   = 256
+	subtileOffsetX       = 16
+	subtileOffsetY       = 8
+	// RLE blocks of walls are (up to) 32x32 pixels
+	rleBlockSize   = 32
+	wallBlocksWide = TileWidth / rleBlockSize
+	maxTileHeight  = 1024
+	defaultRarity  = 1
+)
+
+// TileKind decides how an image is split into tile's blocks
+type TileKind int
+
+// Tile kinds
+const (
+	// TileKindFloor is a 160x80 isometric tile (also used for shadows)
+	TileKindFloor TileKind = iota
+	// TileKindWall is a 160 pixels wide tile standing on the top corner of the floor
+	TileKindWall
+)
+
+// isometricRows returns x offset and number of pixels of isometric block's rows
+func isometricRows() (xjump, nbpix [isometricBlockHeight]int) {
+	return [isometricBlockHeight]int{14, 12, 10, 8, 6, 4, 2, 0, 2, 4, 6, 8, 10, 12, 14},
+		[isometricBlockHeight]int{4, 8, 12, 16, 20, 24, 28, 32, 28, 24, 20, 16, 12, 8, 4}
+}
+
+// NewTile creates a tile of kind given from an indexed image (indices[x+y*width] is palette index
+// of pixel, 0 is transparent). Floor images should be 160x80 pixels, pixels outside of the floor's
+// diamond are skipped. Wall images should be 160 pixels wide; the bottom of the image is placed
+// on the top corner of the floor
+func NewTile(kind TileKind, indices []byte, width, height int) (*d2dt1.Tile, error) {
+	if len(indices) != width*height {
+		return nil, errors.New("image data doesn't match its size")
+	}
+
+	tile := &d2dt1.Tile{RarityFrameIndex: defaultRarity}
+
+	var blocks []block
+
+	switch kind {
+	case TileKindFloor:
+		if width != TileWidth || height != FloorHeight {
+			return nil, fmt.Errorf("floor image should be %dx%d pixels", TileWidth, FloorHeight)
+		}
+
+		tile.Type = int32(d2enum.TileFloor)
+		tile.Width, tile.Height = TileWidth, FloorHeight
+		blocks = floorBlocks(indices)
+	case TileKindWall:
+		if width != TileWidth || height < 1 || height > maxTileHeight {
+			return nil, fmt.Errorf("wall image should be %d pixels wide and at most %d pixels high", TileWidth, maxTileHeight)
+		}
+
+		tile.Type = int32(d2enum.TileLeftWall)
+		tile.Width = TileWidth
+		blocks = wallBlocks(indices, height)
+
+		for _, b := range blocks {
+			if int32(b.y) < tile.Height {
+				tile.Height = int32(b.y)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown tile kind %d", kind)
+	}
+
+	// blocks' formats can be set only by loading them
+	loaded, err := d2dt1.LoadDT1(encode([]*d2dt1.Tile{tile}, [][]block{blocks}))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding tile: %w", err)
+	}
+
+	return &loaded.Tiles[0], nil
+}
+
+// floorBlocks splits a floor image into blocks of subtiles; fully opaque subtiles are
+// encoded as isometric blocks, partially transparent ones as RLE blocks
+func floorBlocks(indices []byte) []block {
+	xjump, nbpix := isometricRows()
+	result := make([]block, 0, subtilesPerSide*subtilesPerSide)
+
+	for gridY := 0; gridY < subtilesPerSide; gridY++ {
+		for gridX := 0; gridX < subtilesPerSide; gridX++ {
+			x := (TileWidth-isometricBlockWidth)/2 + (gridX-gridY)*subtileOffsetX
+			y := (gridX + gridY) * subtileOffsetY
+
+			rows := make([][]byte, isometricBlockHeight)
+			opaque, empty := true, true
+
+			for row := range rows {
+				start := (y+row)*TileWidth + x + xjump[row]
+				rows[row] = indices[start : start+nbpix[row]]
+
+				for _, idx := range rows[row] {
+					opaque = opaque && idx != 0
+					empty = empty && idx == 0
+				}
+			}
+
+			if empty {
+				continue
+			}
+
+			b := block{x: int16(x), y: int16(y), gridX: byte(gridX), gridY: byte(gridY), format: isometricFormat}
+
+			if opaque {
+				b.data = make([]byte, 0, isometricBlockSize)
+
+				for _, row := range rows {
+					b.data = append(b.data, row...)
+				}
+			} else {
+				b.format = rleFormat
+				b.data = encodeRLE(rows, xjump[:])
+			}
+
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
+// wallBlocks splits a wall image into 32x32 RLE blocks placed above the floor (negative y);
+// fully transparent blocks are skipped
+func wallBlocks(indices []byte, height int) []block {
+	numRows := (height + rleBlockSize - 1) / rleBlockSize
+	padding := numRows*rleBlockSize - height
+	result := make([]block, 0, numRows*wallBlocksWide)
+
+	for blockRow := 0; blockRow < numRows; blockRow++ {
+		for blockCol := 0; blockCol < wallBlocksWide; blockCol++ {
+			rows := make([][]byte, rleBlockSize)
+			offsets := make([]int, rleBlockSize)
+			empty := true
+
+			for row := range rows {
+				rows[row] = make([]byte, rleBlockSize)
+
+				y := blockRow*rleBlockSize + row - padding
+				if y < 0 {
+					continue
+				}
+
+				start := y*TileWidth + blockCol*rleBlockSize
+				copy(rows[row], indices[start:start+rleBlockSize])
+
+				for _, idx := range rows[row] {
+					empty = empty && idx == 0
+				}
+			}
+
+			if empty {
+				continue
+			}
+
+			result = append(result, block{
+				x:      int16(blockCol * rleBlockSize),
+				y:      int16(blockRow*rleBlockSize - numRows*rleBlockSize),
+				gridX:  byte(blockCol),
+				gridY:  byte(blockRow),
+				format: rleFormat,
+				data:   encodeRLE(rows, offsets),
+			})
+		}
+	}
+
+	return result
+}
+
+// encodeRLE encodes rows of block's pixels; row i starts offsets[i] pixels from block's left edge.
+// Every row is a list of (number of transparent pixels to skip, number of pixels, pixels) runs
+// terminated by 0, 0; trailing empty rows are omitted
+func encodeRLE(rows [][]byte, offsets []int) []byte {
+	result := make([]byte, 0)
+	emptyRows := 0
+
+	for rowIdx, row := range rows {
+		encoded := make([]byte, 0)
+		runEnd := 0
+
+		for pos := 0; pos < len(row); {
+			if row[pos] == 0 {
+				pos++
+
+				continue
+			}
+
+			run := 0
+			for pos+run < len(row) && row[pos+run] != 0 {
+				run++
+			}
+
+			x := offsets[rowIdx] + pos
+			encoded = append(encoded, byte(x-runEnd), byte(run))
+			encoded = append(encoded, row[pos:pos+run]...)
+
+			pos += run
+			runEnd = x + run
+		}
+
+		if len(encoded) == 0 {
+			emptyRows++
+
+			continue
+		}
+
+		for ; emptyRows > 0; emptyRows-- {
+			result = append(result, 0, 0)
+		}
+
+		result = append(result, encoded...)
+		result = append(result, 0, 0)
+	}
+
+	return result
+}
+
+// ReplaceGraphics replaces graphics of the tile with an indexed image (see NewTile);
+// tile's type, style, sequence and flags are kept
+func ReplaceGraphics(tile *d2dt1.Tile, kind TileKind, indices []byte, width, height int) error {
+	replacement, err := NewTile(kind, indices, width, height)
+	if err != nil {
+		return err
+	}
+
+	tile.Width, tile.Height = replacement.Width, replacement.Height
+	tile.Blocks = replacement.Blocks
+
+	return nil
+}
+
+// AddTile appends a tile to the DT1
+func AddTile(dt1 *d2dt1.DT1, tile *d2dt1.Tile) {
+	dt1.Tiles = append(dt1.Tiles, *tile)
+}
+
+// DeleteTile deletes tile of index given
+func DeleteTile(dt1 *d2dt1.DT1, index int) error {
+	if index < 0 || index >= len(dt1.Tiles) {
+		return fmt.Errorf("there is no tile %d", index)
+	}
+
+	dt1.Tiles = append(dt1.Tiles[:index], dt1.Tiles[index+1:]...)
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package hsdt1
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+// testFloor returns a floor image with all subtiles filled but the first one, which has a hole
+func testFloor() []byte {
+	xjump, nbpix := isometricRows()
+	indices := make([]byte, TileWidth*FloorHeight)
+
+	for gridY := 0; gridY < subtilesPerSide; gridY++ {
+		for gridX := 0; gridX < subtilesPerSide; gridX++ {
+			x := (TileWidth-isometricBlockWidth)/2 + (gridX-gridY)*subtileOffsetX
+			y := (gridX + gridY) * subtileOffsetY
+
+			for row := 0; row < isometricBlockHeight; row++ {
+				for px := 0; px < nbpix[row]; px++ {
+					if gridX == 0 && gridY == 0 && px == 1 {
+						continue
+					}
+
+					indices[(y+row)*TileWidth+x+xjump[row]+px] = byte(gridX + gridY*subtilesPerSide + 1)
+				}
+			}
+		}
+	}
+
+	return indices
+}
+
+// testWall returns a wall image of height given with a transparent column
+func testWall(height int) []byte {
+	indices := make([]byte, TileWidth*height)
+
+	for idx := range indices {
+		if idx%TileWidth != 40 {
+			indices[idx] = byte(idx%251 + 1)
+		}
+	}
+
+	return indices
+}
+
+func TestNewTile(t *testing.T) {
+	floor, err := NewTile(TileKindFloor, testFloor(), TileWidth, FloorHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numIsometric := 0
+
+	for idx := range floor.Blocks {
+		if floor.Blocks[idx].Format() == d2dt1.BlockFormatIsometric {
+			numIsometric++
+		}
+	}
+
+	if len(floor.Blocks) != subtilesPerSide*subtilesPerSide || numIsometric != len(floor.Blocks)-1 {
+		t.Fatalf("expected 24 isometric and 1 RLE block, got %d blocks, %d isometric", len(floor.Blocks), numIsometric)
+	}
+
+	if indices, w, h := Indices(floor); w != TileWidth || h != FloorHeight || !bytes.Equal(indices, testFloor()) {
+		t.Fatal("decoded floor doesn't match its image")
+	}
+
+	const wallHeight = 50
+
+	wall, err := NewTile(TileKindWall, testWall(wallHeight), TileWidth, wallHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the image is padded to whole blocks at the top
+	indices, w, h := Indices(wall)
+	if w != TileWidth || h != 2*rleBlockSize || wall.Height != -2*rleBlockSize {
+		t.Fatalf("unexpected wall size %dx%d (height %d)", w, h, wall.Height)
+	}
+
+	if !bytes.Equal(indices[(h-wallHeight)*w:], testWall(wallHeight)) {
+		t.Fatal("decoded wall doesn't match its image")
+	}
+
+	if _, err := NewTile(TileKindFloor, testWall(wallHeight), TileWidth, wallHeight); err == nil {
+		t.Fatal("expected an error creating floor of wrong size")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	dt1 := d2dt1.New()
+
+	for _, kind := range []TileKind{TileKindFloor, TileKindWall, TileKindFloor} {
+		indices, height := testFloor(), FloorHeight
+		if kind == TileKindWall {
+			indices, height = testWall(rleBlockSize), rleBlockSize
+		}
+
+		tile, err := NewTile(kind, indices, TileWidth, height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tile.Style = int32(len(dt1.Tiles))
+		AddTile(dt1, tile)
+	}
+
+	if err := DeleteTile(dt1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := d2dt1.LoadDT1(Marshal(dt1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.Tiles) != 2 || loaded.Tiles[0].Style != 1 || loaded.Tiles[1].Style != 2 {
+		t.Fatalf("unexpected tiles %d", len(loaded.Tiles))
+	}
+
+	if indices, _, _ := Indices(&loaded.Tiles[0]); !bytes.Equal(indices, testWall(rleBlockSize)) {
+		t.Fatal("decoded wall doesn't match its image")
+	}
+
+	if indices, _, _ := Indices(&loaded.Tiles[1]); !bytes.Equal(indices, testFloor()) {
+		t.Fatal("decoded floor doesn't match its image")
+	}
+}
+
+func TestReplaceGraphics(t *testing.T) {
+	const wallHeight = 2 * rleBlockSize
+
+	dt1 := d2dt1.New()
+
+	wall, err := NewTile(TileKindWall, testWall(rleBlockSize), TileWidth, rleBlockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AddTile(dt1, wall)
+
+	if err := ReplaceGraphics(&dt1.Tiles[0], TileKindWall, testWall(wallHeight), TileWidth, wallHeight); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := d2dt1.LoadDT1(Marshal(dt1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// walls keep negative height, so that they aren't drawn (and replaced) as floors
+	if tile := &loaded.Tiles[0]; tile.Height != -wallHeight {
+		t.Fatalf("expected wall height %d, got %d", -wallHeight, tile.Height)
+	}
+
+	if indices, _, _ := Indices(&loaded.Tiles[0]); !bytes.Equal(indices, testWall(wallHeight)) {
+		t.Fatal("decoded wall doesn't match its replacement")
+	}
+}
+
+func TestMarshalKeepsUnknownBytes(t *testing.T) {
+	tile, err := NewTile(TileKindFloor, testFloor(), TileWidth, FloorHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dt1 := d2dt1.New()
+	AddTile(dt1, tile)
+
+	const start, end = headerSize + unknown2Offset, headerSize + unknown2Offset + numUnknownTileBytes2
+
+	data := Marshal(dt1)
+	if !bytes.Equal(data[start:end], make([]byte, numUnknownTileBytes2)) {
+		t.Fatalf("expected zeros for new tile, got %v", data[start:end])
+	}
+
+	copy(data[start:end], []byte{1, 2, 3, 4})
+
+	loaded, err := d2dt1.LoadDT1(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := Marshal(loaded)
+	if !bytes.Equal(encoded, data) {
+		t.Fatalf("unchanged DT1 isn't encoded the same way, unknown2 %v", encoded[start:end])
+	}
+}
@@ -0,0 +1,97 @@
+package hsdt1
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+// TileFilter selects tiles of a DT1 (index is tile's index in DT1)
+type TileFilter func(index int, tile *d2dt1.Tile) bool
+
+// ByStyle selects tiles of style and sequence given
+func ByStyle(style, sequence int32) TileFilter {
+	return func(_ int, tile *d2dt1.Tile) bool {
+		return tile.Style == style && tile.Sequence == sequence
+	}
+}
+
+// ByType selects tiles of type given
+func ByType(tileType int32) TileFilter {
+	return func(_ int, tile *d2dt1.Tile) bool {
+		return tile.Type == tileType
+	}
+}
+
+// BySelection selects tiles, which are set in selected (indexed by tile's index)
+func BySelection(selected []bool) TileFilter {
+	return func(index int, _ *d2dt1.Tile) bool {
+		return index < len(selected) && selected[index]
+	}
+}
+
+// Merge appends tiles of other DT1s to dt1
+func Merge(dt1 *d2dt1.DT1, others ...*d2dt1.DT1) {
+	for _, other := range others {
+		dt1.Tiles = append(dt1.Tiles, other.Tiles...)
+	}
+}
+
+// Extract copies tiles selected by the filter into a new DT1; if remove is set,
+// the tiles are deleted from dt1
+func Extract(dt1 *d2dt1.DT1, filter TileFilter, remove bool) *d2dt1.DT1 {
+	result := d2dt1.New()
+	kept := make([]d2dt1.Tile, 0, len(dt1.Tiles))
+
+	for idx := range dt1.Tiles {
+		if filter(idx, &dt1.Tiles[idx]) {
+			result.Tiles = append(result.Tiles, dt1.Tiles[idx])
+		} else {
+			kept = append(kept, dt1.Tiles[idx])
+		}
+	}
+
+	if remove {
+		dt1.Tiles = kept
+	}
+
+	return result
+}
+
+// Duplicates returns groups of indices of tiles, whose graphics are pixel-identical;
+// every group contains at least two tiles. Tiles without graphics are skipped
+// (they are often used on purpose, e.g. as invisible markers)
+func Duplicates(dt1 *d2dt1.DT1) [][]int {
+	result := make([][]int, 0)
+	lookup := make(map[string]int)
+
+	for idx := range dt1.Tiles {
+		tile := &dt1.Tiles[idx]
+		if len(tile.Blocks) == 0 {
+			continue
+		}
+
+		indices, _, _ := Indices(tile)
+		key := fmt.Sprintf("%v:%s", Bounds(tile), indices)
+
+		groupIdx, found := lookup[key]
+		if !found {
+			groupIdx = len(result)
+			lookup[key] = groupIdx
+
+			result = append(result, make([]int, 0, 1))
+		}
+
+		result[groupIdx] = append(result[groupIdx], idx)
+	}
+
+	duplicates := result[:0]
+
+	for _, group := range result {
+		if len(group) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+
+	return duplicates
+}
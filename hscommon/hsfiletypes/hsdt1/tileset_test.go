@@ -0,0 +1,41 @@
+package hsdt1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+)
+
+func TestTileset(t *testing.T) {
+	floor, err := NewTile(TileKindFloor, testFloor(), TileWidth, FloorHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wall, err := NewTile(TileKindWall, testWall(rleBlockSize), TileWidth, rleBlockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dt1, other := d2dt1.New(), d2dt1.New()
+	AddTile(dt1, floor)
+	AddTile(dt1, wall)
+	AddTile(other, floor)
+	AddTile(other, &d2dt1.Tile{Style: 1})
+
+	Merge(dt1, other)
+
+	if duplicates := Duplicates(dt1); !reflect.DeepEqual(duplicates, [][]int{{0, 2}}) {
+		t.Fatalf("unexpected duplicates %v", duplicates)
+	}
+
+	extracted := Extract(dt1, BySelection([]bool{false, true, false, true}), true)
+	if len(extracted.Tiles) != 2 || len(dt1.Tiles) != 2 || extracted.Tiles[1].Style != 1 {
+		t.Fatalf("unexpected tiles extracted: %d extracted, %d kept", len(extracted.Tiles), len(dt1.Tiles))
+	}
+
+	if n := len(Extract(dt1, ByType(floor.Type), false).Tiles); n != 2 || len(dt1.Tiles) != 2 {
+		t.Fatalf("expected 2 floors copied, got %d", n)
+	}
+}
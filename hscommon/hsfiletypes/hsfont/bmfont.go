@@ -0,0 +1,161 @@
+package hsfont
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hstbl"
+)
+
+const (
+	// atlasSpacing is a number of empty pixels between glyphs in atlas
+	atlasSpacing = 1
+	// atlasMaxSize is a maximal width of BMFont atlas
+	atlasMaxSize = 4096
+	// bmFontAllChannels is a value of BMFont's chnl attribute, which means glyph is stored in all channels
+	bmFontAllChannels = 15
+)
+
+// atlasGlyph is a glyph placed in the atlas
+type atlasGlyph struct {
+	char          rune
+	x, y          int
+	width, height int
+}
+
+// nextPowerOfTwo returns the smallest power of two, which is greater or equal to n
+func nextPowerOfTwo(n int) int {
+	result := 1
+	for result < n {
+		result *= 2
+	}
+
+	return result
+}
+
+// packGlyphs places glyphs in rows of atlas (the highest glyphs first) and returns the atlas size
+func (r *Renderer) packGlyphs() (glyphs []atlasGlyph, width, height int) {
+	area, widest := 0, 0
+
+	for _, c := range r.Runes() {
+		g := atlasGlyph{char: c}
+
+		if r.hasGlyph(c) {
+			frame := r.Sprite.Frames[r.Table.Glyphs[c].FrameIndex()]
+			g.width, g.height = int(frame.Width), int(frame.Height)
+		}
+
+		if g.width > widest {
+			widest = g.width
+		}
+
+		area += (g.width + atlasSpacing) * (g.height + atlasSpacing)
+		glyphs = append(glyphs, g)
+	}
+
+	width = nextPowerOfTwo(int(math.Sqrt(float64(area))))
+	if width < widest+atlasSpacing {
+		width = nextPowerOfTwo(widest + atlasSpacing)
+	}
+
+	if width > atlasMaxSize {
+		width = atlasMaxSize
+	}
+
+	sort.SliceStable(glyphs, func(i, j int) bool { return glyphs[i].height > glyphs[j].height })
+
+	x, y, rowHeight := 0, 0, 0
+
+	for idx := range glyphs {
+		g := &glyphs[idx]
+		if g.width == 0 || g.height == 0 {
+			continue
+		}
+
+		if x+g.width > width {
+			x, y, rowHeight = 0, y+rowHeight+atlasSpacing, 0
+		}
+
+		g.x, g.y = x, y
+		x += g.width + atlasSpacing
+
+		if g.height > rowHeight {
+			rowHeight = g.height
+		}
+	}
+
+	height = nextPowerOfTwo(y + rowHeight)
+
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i].char < glyphs[j].char })
+
+	return glyphs, width, height
+}
+
+// ExportBMFont creates AngelCode BMFont text descriptor and its (single page) atlas.
+// Advance widths and line height are taken from the font table; glyphs are aligned
+// to the bottom of line like the game does it
+func (r *Renderer) ExportBMFont(face, pageFile string) (descriptor []byte, atlas *image.RGBA, err error) {
+	if len(r.Table.Glyphs) == 0 {
+		return nil, nil, errors.New("font table has no glyphs")
+	}
+
+	glyphs, width, height := r.packGlyphs()
+	lineHeight := r.LineHeight()
+	atlas = image.NewRGBA(image.Rect(0, 0, width, height))
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "info face=%q size=%d bold=0 italic=0 charset=\"\" unicode=1 stretchH=100 smooth=0 aa=1 "+
+		"padding=0,0,0,0 spacing=%d,%d\n", face, lineHeight, atlasSpacing, atlasSpacing)
+	fmt.Fprintf(&sb, "common lineHeight=%d base=%d scaleW=%d scaleH=%d pages=1 packed=0\n",
+		lineHeight, lineHeight, width, height)
+	fmt.Fprintf(&sb, "page id=0 file=%q\n", pageFile)
+	fmt.Fprintf(&sb, "chars count=%d\n", len(glyphs))
+
+	for _, g := range glyphs {
+		if g.width > 0 && g.height > 0 {
+			r.drawGlyph(atlas, g.char, g.x, g.y, g.height, hstbl.TextColorWhite)
+		}
+
+		fmt.Fprintf(&sb, "char id=%d x=%d y=%d width=%d height=%d xoffset=0 yoffset=%d xadvance=%d page=0 chnl=%d\n",
+			g.char, g.x, g.y, g.width, g.height, lineHeight-g.height, r.Table.Glyphs[g.char].Width(), bmFontAllChannels)
+	}
+
+	return []byte(sb.String()), atlas, nil
+}
+
+// SaveBMFont writes BMFont descriptor to path given and its atlas to png file of the same name
+func (r *Renderer) SaveBMFont(path string) error {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	pageFile := base + ".png"
+
+	descriptor, atlas, err := r.ExportBMFont(filepath.Base(base), filepath.Base(pageFile))
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, atlas); err != nil {
+		return fmt.Errorf("cannot encode atlas: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(pageFile), buf.Bytes(), os.FileMode(newFilePerms)); err != nil {
+		return fmt.Errorf("cannot write to file %s: %w", pageFile, err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(path), descriptor, os.FileMode(newFilePerms)); err != nil {
+		return fmt.Errorf("cannot write to file %s: %w", path, err)
+	}
+
+	return nil
+}
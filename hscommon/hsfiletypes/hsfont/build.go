@@ -0,0 +1,135 @@
+package hsfont
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font/d2fontglyph"
+)
+
+// maxCharRange is a maximal number of characters of a range; fonts of the game have a few hundreds of them
+const maxCharRange = 0x1000
+
+// CharRange returns characters from first to last (inclusive)
+func CharRange(first, last rune) ([]rune, error) {
+	if last < first || first < 0 {
+		return nil, fmt.Errorf("invalid character range %d - %d", first, last)
+	}
+
+	if last > maxTableRune {
+		return nil, fmt.Errorf("character %d can't be stored in font table (max %d)", last, maxTableRune)
+	}
+
+	if last-first+1 > maxCharRange {
+		return nil, fmt.Errorf("character range %d - %d is too large (max %d characters)", first, last, maxCharRange)
+	}
+
+	result := make([]rune, 0, last-first+1)
+
+	for c := first; c <= last; c++ {
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+// CharsFromText returns characters of text in order of their first occurrence;
+// line breaks are skipped, so characters could be listed in many lines
+func CharsFromText(text string) []rune {
+	result := make([]rune, 0)
+	known := make(map[rune]bool)
+
+	for _, c := range text {
+		if c == '\n' || c == '\r' || known[c] {
+			continue
+		}
+
+		known[c] = true
+
+		result = append(result, c)
+	}
+
+	return result
+}
+
+// MeasureFrame returns size of glyph stored in DC6 frame: the width and the height
+// are measured to the rightmost and the lowest non-transparent pixel.
+// Empty frames (e.g. space) keep their size.
+func MeasureFrame(sprite *d2dc6.DC6, frameIdx int) (w, h int) {
+	frame := sprite.Frames[frameIdx]
+	frameW, frameH := int(frame.Width), int(frame.Height)
+
+	if frameW == 0 || frameH == 0 {
+		return frameW, frameH
+	}
+
+	indices := sprite.DecodeFrame(frameIdx)
+
+	for y := 0; y < frameH; y++ {
+		for x := 0; x < frameW; x++ {
+			if indices[x+y*frameW] == 0 {
+				continue
+			}
+
+			if x+1 > w {
+				w = x + 1
+			}
+
+			if y+1 > h {
+				h = y + 1
+			}
+		}
+	}
+
+	if w == 0 || h == 0 {
+		return frameW, frameH
+	}
+
+	return w, h
+}
+
+// BuildGlyphs assigns DC6 frames to characters given (chars[i] gets frame firstFrame+i)
+// and sets glyph sizes measured from frame pixels. If onlyMissing is true, glyphs,
+// which already exist in the table, are left untouched.
+// Returns number of glyphs created or updated
+func BuildGlyphs(table *d2font.Font, sprite *d2dc6.DC6, chars []rune, firstFrame int, onlyMissing bool) (int, error) {
+	if len(chars) == 0 {
+		return 0, errors.New("no characters given")
+	}
+
+	if firstFrame < 0 || firstFrame >= len(sprite.Frames) {
+		return 0, fmt.Errorf("first frame %d is out of range (sprite has %d frames)", firstFrame, len(sprite.Frames))
+	}
+
+	if table.Glyphs == nil {
+		table.Glyphs = make(map[rune]*d2fontglyph.FontGlyph)
+	}
+
+	count := 0
+
+	for idx, c := range chars {
+		frameIdx := firstFrame + idx
+		if frameIdx >= len(sprite.Frames) {
+			break
+		}
+
+		if _, exist := table.Glyphs[c]; exist && onlyMissing {
+			continue
+		}
+
+		w, h := MeasureFrame(sprite, frameIdx)
+
+		if glyph, exist := table.Glyphs[c]; exist {
+			glyph.SetFrameIndex(frameIdx)
+			glyph.SetSize(w, h)
+		} else {
+			table.Glyphs[c] = d2fontglyph.Create(frameIdx, w, h)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
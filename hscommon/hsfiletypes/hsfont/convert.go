@@ -0,0 +1,291 @@
+package hsfont
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font/d2fontglyph"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdc6"
+)
+
+const (
+	// rasterizeDPI makes font size equal to size in pixels
+	rasterizeDPI = 72
+	// maxGlyphSize is a maximal width/height of glyph, which could be stored in font table
+	maxGlyphSize = 255
+)
+
+// SourceGlyph is a glyph of an imported font. Image is a cell as wide as the glyph's advance
+// and as high as the line; glyphs are drawn with the baseline at the same height in all cells
+type SourceGlyph struct {
+	Char  rune
+	Image *image.RGBA
+}
+
+// RasterizeTrueType renders characters given using TrueType/OpenType font of size (in pixels) given.
+// Characters, which the font has no glyph for, are skipped
+func RasterizeTrueType(data []byte, size float64, chars []rune) ([]SourceGlyph, error) {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse font: %w", err)
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     rasterizeDPI,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create font face: %w", err)
+	}
+
+	defer func() {
+		_ = face.Close()
+	}()
+
+	metrics := face.Metrics()
+	ascent := metrics.Ascent.Ceil()
+	lineHeight := ascent + metrics.Descent.Ceil()
+
+	result := make([]SourceGlyph, 0, len(chars))
+
+	var buf sfnt.Buffer
+
+	for _, c := range chars {
+		if idx, err := f.GlyphIndex(&buf, c); err != nil || idx == 0 {
+			continue
+		}
+
+		advance, ok := face.GlyphAdvance(c)
+		if !ok {
+			continue
+		}
+
+		width := advance.Ceil()
+		if width < 1 {
+			width = 1
+		}
+
+		img := image.NewRGBA(image.Rect(0, 0, width, lineHeight))
+
+		drawer := font.Drawer{
+			Dst:  img,
+			Src:  image.White,
+			Face: face,
+			Dot:  fixed.P(0, ascent),
+		}
+
+		drawer.DrawString(string(c))
+
+		result = append(result, SourceGlyph{Char: c, Image: img})
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("font has no glyphs for characters given")
+	}
+
+	return result, nil
+}
+
+// bmFontChar is a char entry of BMFont descriptor
+type bmFontChar struct {
+	id, x, y, width, height, xOffset, yOffset, xAdvance, page int
+}
+
+// parseBMFontLine splits line of BMFont descriptor into a tag and its attributes
+func parseBMFontLine(line string) (tag string, attributes map[string]string) {
+	attributes = make(map[string]string)
+
+	line = strings.TrimSpace(line)
+
+	idx := strings.IndexAny(line, " \t")
+	if idx < 0 {
+		return line, attributes
+	}
+
+	tag, line = line[:idx], line[idx:]
+
+	for {
+		line = strings.TrimLeft(line, " \t")
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			break
+		}
+
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var value string
+
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				value, line = line[1:], ""
+			} else {
+				value, line = line[1:end+1], line[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+
+			value, line = line[:end], line[end:]
+		}
+
+		attributes[key] = value
+	}
+
+	return tag, attributes
+}
+
+// LoadBMFont reads glyphs of AngelCode BMFont (text descriptor and page images).
+// loadPage is called for each page file referenced by descriptor
+func LoadBMFont(data []byte, loadPage func(fileName string) (image.Image, error)) ([]SourceGlyph, error) {
+	if bytes.HasPrefix(data, []byte("BMF")) || bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return nil, errors.New("only text BMFont descriptors are supported")
+	}
+
+	lineHeight := 0
+	pages := make(map[int]image.Image)
+	chars := make([]bmFontChar, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		tag, attributes := parseBMFontLine(scanner.Text())
+
+		number := func(key string) int {
+			n, _ := strconv.Atoi(attributes[key])
+
+			return n
+		}
+
+		switch tag {
+		case "common":
+			lineHeight = number("lineHeight")
+		case "page":
+			page, err := loadPage(attributes["file"])
+			if err != nil {
+				return nil, fmt.Errorf("cannot load page %s: %w", attributes["file"], err)
+			}
+
+			pages[number("id")] = page
+		case "char":
+			chars = append(chars, bmFontChar{
+				id: number("id"), x: number("x"), y: number("y"),
+				width: number("width"), height: number("height"),
+				xOffset: number("xoffset"), yOffset: number("yoffset"),
+				xAdvance: number("xadvance"), page: number("page"),
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read BMFont descriptor: %w", err)
+	}
+
+	if lineHeight <= 0 {
+		return nil, errors.New("BMFont descriptor has no line height")
+	}
+
+	result := make([]SourceGlyph, 0, len(chars))
+
+	for _, c := range chars {
+		page, found := pages[c.page]
+		if !found {
+			return nil, fmt.Errorf("char %d: page %d not found", c.id, c.page)
+		}
+
+		width := c.xAdvance
+		if width < 1 {
+			width = 1
+		}
+
+		img := image.NewRGBA(image.Rect(0, 0, width, lineHeight))
+		dst := image.Rect(c.xOffset, c.yOffset, c.xOffset+c.width, c.yOffset+c.height)
+
+		draw.Draw(img, dst, page, image.Pt(c.x, c.y), draw.Over)
+
+		result = append(result, SourceGlyph{Char: rune(c.id), Image: img})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Char < result[j].Char })
+
+	return result, nil
+}
+
+// Convert creates a DC6 sprite (a frame per glyph) and a font table of glyphs given.
+// Colors are mapped to the nearest colors of palette
+func Convert(glyphs []SourceGlyph, palette *[256]color.RGBA) (*d2dc6.DC6, *d2font.Font, error) {
+	q := hsdc6.NewQuantizer(palette)
+	frames := make([]hsdc6.Frame, len(glyphs))
+	table := &d2font.Font{Glyphs: make(map[rune]*d2fontglyph.FontGlyph)}
+
+	for idx, glyph := range glyphs {
+		size := glyph.Image.Bounds().Size()
+		if size.X > maxGlyphSize || size.Y > maxGlyphSize {
+			return nil, nil, fmt.Errorf("glyph %q is too large (%dx%d, max %d)", glyph.Char, size.X, size.Y, maxGlyphSize)
+		}
+
+		frames[idx] = hsdc6.FrameFromImage(glyph.Image, q)
+		table.Glyphs[glyph.Char] = d2fontglyph.Create(idx, size.X, size.Y)
+	}
+
+	sprite, err := hsdc6.New(1, frames)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create DC6 sprite: %w", err)
+	}
+
+	return sprite, table, nil
+}
+
+// SaveConverted writes sprite (basePath.dc6), font table (basePath.tbl) and a font file (basePath.hsf),
+// which ties them together with the palette given
+func SaveConverted(basePath, palettePath string, sprite *d2dc6.DC6, table *d2font.Font) (*Font, error) {
+	// table is encoded first, so that no file is written if it can't be
+	tableData, err := MarshalTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Font{
+		filePath:    basePath + ".hsf",
+		TableFile:   basePath + ".tbl",
+		SpriteFile:  basePath + ".dc6",
+		PaletteFile: palettePath,
+	}
+
+	if err := ioutil.WriteFile(result.SpriteFile, sprite.Marshal(), os.FileMode(newFilePerms)); err != nil {
+		return nil, fmt.Errorf("cannot write to file %s: %w", result.SpriteFile, err)
+	}
+
+	if err := ioutil.WriteFile(result.TableFile, tableData, os.FileMode(newFilePerms)); err != nil {
+		return nil, fmt.Errorf("cannot write to file %s: %w", result.TableFile, err)
+	}
+
+	if err := result.SaveToFile(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package hsfont
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font/d2fontglyph"
+)
+
+func testTable() *d2font.Font {
+	return &d2font.Font{Glyphs: map[rune]*d2fontglyph.FontGlyph{
+		'A': d2fontglyph.Create(0, 5, 8),
+		'b': d2fontglyph.Create(1, 4, 6),
+		'é': d2fontglyph.Create(2, 6, 9),
+	}}
+}
+
+func equalTables(t *testing.T, expected, got *d2font.Font) {
+	t.Helper()
+
+	if len(expected.Glyphs) != len(got.Glyphs) {
+		t.Fatalf("expected %d glyphs, got %d", len(expected.Glyphs), len(got.Glyphs))
+	}
+
+	for c, e := range expected.Glyphs {
+		g, found := got.Glyphs[c]
+		if !found {
+			t.Fatalf("glyph %q not found", c)
+		}
+
+		if e.FrameIndex() != g.FrameIndex() || e.Width() != g.Width() || e.Height() != g.Height() {
+			t.Fatalf("glyph %q: expected frame %d, %dx%d, got frame %d, %dx%d", c,
+				e.FrameIndex(), e.Width(), e.Height(), g.FrameIndex(), g.Width(), g.Height())
+		}
+	}
+}
+
+func TestMarshalTable(t *testing.T) {
+	table := testTable()
+
+	data, err := MarshalTable(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := d2font.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalTables(t, table, loaded)
+
+	table.Glyphs['😀'] = d2fontglyph.Create(3, 8, 8)
+	if _, err := MarshalTable(table); err == nil {
+		t.Fatal("expected an error encoding character above U+FFFF")
+	}
+}
+
+func TestCharRange(t *testing.T) {
+	chars, err := CharRange('a', 'c')
+	if err != nil || string(chars) != "abc" {
+		t.Fatalf("unexpected range %q: %v", string(chars), err)
+	}
+
+	for _, r := range [][2]rune{{'c', 'a'}, {0, maxCharRange}, {0x10000, 0x10001}} {
+		if _, err := CharRange(r[0], r[1]); err == nil {
+			t.Fatalf("expected an error for range %d - %d", r[0], r[1])
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	var palette [256]color.RGBA
+	for idx := range palette {
+		palette[idx] = color.RGBA{R: uint8(idx), G: uint8(idx), B: uint8(idx), A: 0xff}
+	}
+
+	glyphs := make([]SourceGlyph, 0)
+
+	for idx, c := range []rune{'A', 'B'} {
+		img := image.NewRGBA(image.Rect(0, 0, 4+idx, 7))
+		img.Set(1, 2, color.White)
+		glyphs = append(glyphs, SourceGlyph{Char: c, Image: img})
+	}
+
+	sprite, table, err := Convert(glyphs, &palette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadedSprite, err := d2dc6.Load(sprite.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loadedSprite.Frames) != len(glyphs) {
+		t.Fatalf("expected %d frames, got %d", len(glyphs), len(loadedSprite.Frames))
+	}
+
+	data, err := MarshalTable(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadedTable, err := d2font.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalTables(t, table, loadedTable)
+
+	if w := loadedTable.Glyphs['B'].Width(); w != 5 {
+		t.Fatalf("expected width of B to be 5, got %d", w)
+	}
+}
+
+func TestFontJSON(t *testing.T) {
+	font := &Font{TableFile: "font16.tbl", SpriteFile: "font16.dc6", PaletteFile: "pal.dat"}
+
+	data, err := font.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *loaded != *font {
+		t.Fatalf("expected %+v, got %+v", font, loaded)
+	}
+}
+
+func TestMissingGlyphs(t *testing.T) {
+	r := &Renderer{
+		Table:  testTable(),
+		Sprite: &d2dc6.DC6{Frames: make([]*d2dc6.DC6Frame, 3)},
+	}
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Ab", ""},
+		{"ÿc1Aÿc2b\nÿc;é", ""},
+		{"\xffc1A\xffc2b", ""},
+		{"ÿc1AxbAx?", "x?"},
+	}
+
+	for _, test := range tests {
+		if got := string(r.MissingGlyphs(test.text)); got != test.expected {
+			t.Fatalf("%q: expected %q, got %q", test.text, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package hsfont
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hstbl"
+)
+
+const (
+	maxAlpha = 0xff
+	// missingGlyphW is a width of missing glyph placeholder relative to line height
+	missingGlyphW = 2
+	glyphGridGap  = 2
+	half          = 2
+	// textColorWhiteSlashIndex is an index of ÿc/ in PL2's text colors
+	textColorWhiteSlashIndex = 12
+)
+
+// Renderer renders text using font table, DC6 sprite and palette referenced by a font
+type Renderer struct {
+	Table  *d2font.Font
+	Sprite *d2dc6.DC6
+
+	palette [256]color.RGBA
+	// textColors are text color shifts of PL2 palette (nil if font uses dat palette)
+	textColors *[13]d2pl2.PL2PaletteTransform
+	// frames are decoded (indexed) DC6 frames
+	frames map[int][]byte
+}
+
+// NewRenderer loads files referenced by the font and creates a new renderer.
+// If font has no palette, glyphs are rendered in grayscale
+func (f *Font) NewRenderer() (*Renderer, error) {
+	if f.TableFile == "" || f.SpriteFile == "" {
+		return nil, errors.New("font table and DC6 sprite should be set to render font")
+	}
+
+	tableData, err := ioutil.ReadFile(filepath.Clean(f.TableFile))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read font table: %w", err)
+	}
+
+	table, err := d2font.Load(tableData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load font table: %w", err)
+	}
+
+	spriteData, err := ioutil.ReadFile(filepath.Clean(f.SpriteFile))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read DC6 sprite: %w", err)
+	}
+
+	sprite, err := d2dc6.Load(spriteData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load DC6 sprite: %w", err)
+	}
+
+	result := &Renderer{
+		Table:  table,
+		Sprite: sprite,
+		frames: make(map[int][]byte),
+	}
+
+	for idx := range result.palette {
+		result.palette[idx] = color.RGBA{R: uint8(idx), G: uint8(idx), B: uint8(idx), A: maxAlpha}
+	}
+
+	if f.PaletteFile != "" {
+		if result.palette, result.textColors, err = hspalette.LoadFile(f.PaletteFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// hasGlyph returns true if rune has a glyph, which points to an existing sprite frame
+func (r *Renderer) hasGlyph(c rune) bool {
+	glyph, found := r.Table.Glyphs[c]
+
+	return found && glyph.FrameIndex() >= 0 && glyph.FrameIndex() < len(r.Sprite.Frames)
+}
+
+// MissingGlyphs returns characters of text given, which have no glyph in font;
+// color codes aren't printed, so they're skipped
+func (r *Renderer) MissingGlyphs(text string) []rune {
+	result := make([]rune, 0)
+	known := make(map[rune]bool)
+	lines, _ := hstbl.ParseColorCodes(text)
+
+	for _, line := range lines {
+		for _, c := range line.String() {
+			if known[c] {
+				continue
+			}
+
+			known[c] = true
+
+			if !r.hasGlyph(c) {
+				result = append(result, c)
+			}
+		}
+	}
+
+	return result
+}
+
+// LineHeight returns height of line (the height of the highest glyph)
+func (r *Renderer) LineHeight() int {
+	result := 0
+
+	for _, glyph := range r.Table.Glyphs {
+		if glyph.Height() > result {
+			result = glyph.Height()
+		}
+	}
+
+	return result
+}
+
+// Runes returns sorted runes of all glyphs in font table
+func (r *Renderer) Runes() []rune {
+	result := make([]rune, 0, len(r.Table.Glyphs))
+
+	for c := range r.Table.Glyphs {
+		result = append(result, c)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+
+	return result
+}
+
+func (r *Renderer) frame(idx int) []byte {
+	if data, found := r.frames[idx]; found {
+		return data
+	}
+
+	r.frames[idx] = r.Sprite.DecodeFrame(idx)
+
+	return r.frames[idx]
+}
+
+// advance returns width of character (missing glyphs are drawn as boxes)
+func (r *Renderer) advance(c rune) int {
+	if r.hasGlyph(c) {
+		return r.Table.Glyphs[c].Width()
+	}
+
+	return r.LineHeight() / missingGlyphW
+}
+
+// lineWidth returns width of line in pixels
+func (r *Renderer) lineWidth(line hstbl.TextLine) int {
+	result := 0
+
+	for _, c := range line.String() {
+		result += r.advance(c)
+	}
+
+	return result
+}
+
+// textColorIndex returns index of color in PL2's text color shifts
+func textColorIndex(c hstbl.TextColor) int {
+	if c == hstbl.TextColorWhiteSlash {
+		return textColorWhiteSlashIndex
+	}
+
+	return int(c - hstbl.TextColorWhite)
+}
+
+// drawGlyph draws glyph of character at x, y (top-left corner of line) and returns true on success
+func (r *Renderer) drawGlyph(img *image.RGBA, c rune, x, y, lineHeight int, textColor hstbl.TextColor) bool {
+	if !r.hasGlyph(c) {
+		return false
+	}
+
+	frameIdx := r.Table.Glyphs[c].FrameIndex()
+	frame := r.Sprite.Frames[frameIdx]
+
+	// e.g. space
+	if frame.Width == 0 || frame.Height == 0 {
+		return true
+	}
+
+	indices := r.frame(frameIdx)
+
+	var shift *d2pl2.PL2PaletteTransform
+
+	if r.textColors != nil {
+		if idx := textColorIndex(textColor); idx >= 0 && idx < len(r.textColors) {
+			shift = &r.textColors[idx]
+		}
+	}
+
+	// glyphs are aligned to the bottom of line
+	top := y + lineHeight - int(frame.Height)
+
+	for fy := 0; fy < int(frame.Height); fy++ {
+		for fx := 0; fx < int(frame.Width); fx++ {
+			idx := indices[fx+fy*int(frame.Width)]
+			if idx == 0 {
+				continue
+			}
+
+			if shift != nil {
+				idx = shift.Indices[idx]
+			}
+
+			img.Set(x+fx, top+fy, r.palette[idx])
+		}
+	}
+
+	return true
+}
+
+// drawMissingGlyph draws a red box in place of character, which has no glyph
+func drawMissingGlyph(img *image.RGBA, x, y, w, h int) {
+	missing := color.RGBA{R: maxAlpha, A: maxAlpha}
+
+	for px := x; px < x+w; px++ {
+		img.Set(px, y, missing)
+		img.Set(px, y+h-1, missing)
+	}
+
+	for py := y; py < y+h; py++ {
+		img.Set(x, py, missing)
+		img.Set(x+w-1, py, missing)
+	}
+}
+
+// RenderLines renders lines of colored text (from top to bottom), centered if center is true.
+// Characters without glyph are drawn as red boxes.
+func (r *Renderer) RenderLines(lines []hstbl.TextLine, center bool) *image.RGBA {
+	lineHeight := r.LineHeight()
+	width := 1
+
+	for _, line := range lines {
+		if w := r.lineWidth(line); w > width {
+			width = w
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, lineHeight*len(lines)+1))
+
+	for lineIdx, line := range lines {
+		x, y := 0, lineIdx*lineHeight
+
+		if center {
+			x = (width - r.lineWidth(line)) / half
+		}
+
+		for _, span := range line {
+			for _, c := range span.Text {
+				if !r.drawGlyph(img, c, x, y, lineHeight, span.Color) {
+					drawMissingGlyph(img, x, y, r.advance(c), lineHeight)
+				}
+
+				x += r.advance(c)
+			}
+		}
+	}
+
+	return img
+}
+
+// RenderText renders text, which could contain color codes and line breaks
+func (r *Renderer) RenderText(text string) *image.RGBA {
+	lines, _ := hstbl.ParseColorCodes(text)
+
+	return r.RenderLines(lines, false)
+}
+
+// RenderGlyphGrid renders all glyphs of font in a grid of columns given.
+// Returns the image and size of a single cell
+func (r *Renderer) RenderGlyphGrid(columns int) (img *image.RGBA, cellW, cellH int) {
+	runes := r.Runes()
+
+	for _, c := range runes {
+		if w := r.advance(c); w > cellW {
+			cellW = w
+		}
+	}
+
+	cellW += glyphGridGap
+	cellH = r.LineHeight() + glyphGridGap
+
+	rows := (len(runes) + columns - 1) / columns
+
+	img = image.NewRGBA(image.Rect(0, 0, cellW*columns, cellH*rows+1))
+
+	for idx, c := range runes {
+		x, y := (idx%columns)*cellW, (idx/columns)*cellH
+
+		if !r.drawGlyph(img, c, x, y, cellH-glyphGridGap, hstbl.TextColorWhite) {
+			drawMissingGlyph(img, x, y, cellW-glyphGridGap, cellH-glyphGridGap)
+		}
+	}
+
+	return img, cellW, cellH
+}
@@ -0,0 +1,79 @@
+package hsfont
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font"
+)
+
+// maxTableRune is the last character, which font table can store (characters are 16-bit)
+const maxTableRune = 0xffff
+
+// MarshalTable encodes font table. Unlike d2font.Font.Marshal, which writes one header
+// byte too many (so the table can't be loaded back), it writes the 12-byte header
+// expected by d2font.Load; glyphs are sorted by frame index.
+// Characters above maxTableRune are reported as an error
+func MarshalTable(table *d2font.Font) ([]byte, error) {
+	const maxCellSize = 0xff
+
+	sw := d2datautils.CreateStreamWriter()
+
+	chars := make([]rune, 0, len(table.Glyphs))
+	cellW, cellH := 0, 0
+
+	for c, glyph := range table.Glyphs {
+		if c < 0 || c > maxTableRune {
+			return nil, fmt.Errorf("character %q (U+%04X) can't be stored in font table", c, c)
+		}
+
+		chars = append(chars, c)
+
+		if glyph.Width() > cellW {
+			cellW = glyph.Width()
+		}
+
+		if glyph.Height() > cellH {
+			cellH = glyph.Height()
+		}
+	}
+
+	sort.Slice(chars, func(i, j int) bool {
+		fi, fj := table.Glyphs[chars[i]].FrameIndex(), table.Glyphs[chars[j]].FrameIndex()
+		if fi != fj {
+			return fi < fj
+		}
+
+		return chars[i] < chars[j]
+	})
+
+	if cellW > maxCellSize {
+		cellW = maxCellSize
+	}
+
+	if cellH > maxCellSize {
+		cellH = maxCellSize
+	}
+
+	// signature, version and unknown header bytes
+	sw.PushBytes([]byte("Woo!\x01")...)
+	sw.PushBytes(0, 1, 0, 0, 0)
+
+	// expected height and width of character cell (not used by decoder)
+	sw.PushBytes(byte(cellH), byte(cellW))
+
+	for _, c := range chars {
+		glyph := table.Glyphs[c]
+
+		sw.PushUint16(uint16(c))
+		sw.PushBytes(glyph.Unknown1()...)
+		sw.PushBytes(byte(glyph.Width()))
+		sw.PushBytes(byte(glyph.Height()))
+		sw.PushBytes(glyph.Unknown2()...)
+		sw.PushUint16(uint16(glyph.FrameIndex()))
+		sw.PushBytes(glyph.Unknown3()...)
+	}
+
+	return sw.GetBytes(), nil
+}
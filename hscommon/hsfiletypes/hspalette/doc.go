@@ -0,0 +1,4 @@
+// Package hspalette contains helpers for loading palettes and remapping indexed (palettized)
+// pixels from one palette to another: colors are matched by perceptual (CIE L*a*b*) distance
+// and indices in locked ranges (e.g. transparent index 0) are kept as they are.
+package hspalette
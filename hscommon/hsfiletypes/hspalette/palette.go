@@ -0,0 +1,59 @@
+package hspalette
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// Colors converts colors of palette to color.RGBA; it returns nil if palette is nil
+func Colors(palette *[256]d2interface.Color) *[256]color.RGBA {
+	if palette == nil {
+		return nil
+	}
+
+	var result [paletteSize]color.RGBA
+
+	for idx, c := range palette {
+		result[idx] = color.RGBA{R: c.R(), G: c.G(), B: c.B(), A: maxAlpha}
+	}
+
+	return &result
+}
+
+// LoadFile loads PL2 (or DAT) palette; text color shifts are returned only for PL2 palettes
+func LoadFile(path string) (colors [256]color.RGBA, textColors *[13]d2pl2.PL2PaletteTransform, err error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return colors, nil, fmt.Errorf("cannot read palette: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".dat") {
+		palette, err := d2dat.Load(data)
+		if err != nil {
+			return colors, nil, fmt.Errorf("cannot load palette: %w", err)
+		}
+
+		datColors := palette.GetColors()
+
+		return *Colors(&datColors), nil, nil
+	}
+
+	pl2, err := d2pl2.Load(data)
+	if err != nil {
+		return colors, nil, fmt.Errorf("cannot load palette: %w", err)
+	}
+
+	for idx := range colors {
+		c := pl2.BasePalette.Colors[idx]
+		colors[idx] = color.RGBA{R: c.R, G: c.G, B: c.B, A: maxAlpha}
+	}
+
+	return colors, &pl2.TextColorShifts, nil
+}
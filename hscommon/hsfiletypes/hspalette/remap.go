@@ -0,0 +1,156 @@
+package hspalette
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	paletteSize = 256
+	maxIndex    = paletteSize - 1
+	rangeParts  = 2
+	maxAlpha    = 0xff
+)
+
+// IndexRange is an inclusive range of palette indices
+type IndexRange struct {
+	First, Last int
+}
+
+// Contains returns true if index is in range
+func (r IndexRange) Contains(idx int) bool {
+	return idx >= r.First && idx <= r.Last
+}
+
+// ParseIndexRanges parses comma separated indices and ranges of indices, e.g. "0, 240-255"
+func ParseIndexRanges(text string) ([]IndexRange, error) {
+	result := make([]IndexRange, 0)
+
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", rangeParts)
+
+		first, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index range %q: %w", part, err)
+		}
+
+		last := first
+
+		if len(bounds) == rangeParts {
+			if last, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil {
+				return nil, fmt.Errorf("invalid index range %q: %w", part, err)
+			}
+		}
+
+		if first < 0 || last > maxIndex || first > last {
+			return nil, fmt.Errorf("invalid index range %q: indices should be between 0 and %d", part, maxIndex)
+		}
+
+		result = append(result, IndexRange{First: first, Last: last})
+	}
+
+	return result, nil
+}
+
+// lab is a color in CIE L*a*b* color space
+type lab struct {
+	l, a, b float64
+}
+
+// toLab converts sRGB color into CIE L*a*b* color (with D65 white point)
+// nolint:gomnd // color space constants
+func toLab(c color.RGBA) lab {
+	linear := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.04045 {
+			return f / 12.92
+		}
+
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+
+	x := (0.4124*r + 0.3576*g + 0.1805*b) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := (0.0193*r + 0.1192*g + 0.9505*b) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 0.008856 {
+			return math.Cbrt(t)
+		}
+
+		return 7.787*t + 16.0/116
+	}
+
+	fx, fy, fz := f(x), f(y), f(z)
+
+	return lab{l: 116*fy - 16, a: 500 * (fx - fy), b: 200 * (fy - fz)}
+}
+
+// distance returns squared CIE76 color difference
+func (c lab) distance(other lab) float64 {
+	dl, da, db := c.l-other.l, c.a-other.a, c.b-other.b
+
+	return dl*dl + da*da + db*db
+}
+
+func isLocked(idx int, locked []IndexRange) bool {
+	for _, r := range locked {
+		if r.Contains(idx) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemapTable returns a table, which maps indices of source palette to indices of target palette
+// with the perceptually nearest colors. Locked indices are kept as they are and other colors are
+// never mapped to them (so that e.g. no color becomes transparent)
+func RemapTable(source, target *[256]color.RGBA, locked []IndexRange) *[256]byte {
+	var result [paletteSize]byte
+
+	targetColors := make([]lab, paletteSize)
+	for idx := range target {
+		targetColors[idx] = toLab(target[idx])
+	}
+
+	for idx := range source {
+		result[idx] = byte(idx)
+
+		if isLocked(idx, locked) {
+			continue
+		}
+
+		c := toLab(source[idx])
+		best := math.Inf(1)
+
+		for candidate := range targetColors {
+			if isLocked(candidate, locked) {
+				continue
+			}
+
+			if d := c.distance(targetColors[candidate]); d < best {
+				result[idx], best = byte(candidate), d
+			}
+		}
+	}
+
+	return &result
+}
+
+// Remap replaces indices with the ones of table given
+func Remap(indices []byte, table *[256]byte) {
+	for idx, c := range indices {
+		indices[idx] = table[c]
+	}
+}
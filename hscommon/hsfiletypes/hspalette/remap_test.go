@@ -0,0 +1,60 @@
+package hspalette
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParseIndexRanges(t *testing.T) {
+	ranges, err := ParseIndexRanges(" 0, 240 - 255,7,")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []IndexRange{{0, 0}, {240, 255}, {7, 7}}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("expected %v, got %v", expected, ranges)
+	}
+
+	for _, text := range []string{"a", "5-2", "0-256", "-1"} {
+		if _, err := ParseIndexRanges(text); err == nil {
+			t.Fatalf("%q: expected an error", text)
+		}
+	}
+}
+
+func TestRemapTable(t *testing.T) {
+	var source, target [256]color.RGBA
+
+	source[0] = color.RGBA{R: 0xff, A: 0xff}
+	source[1] = color.RGBA{R: 0xf0, G: 0x10, A: 0xff}
+	source[2] = color.RGBA{G: 0xe0, B: 0x20, A: 0xff}
+	source[3] = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+
+	target[5] = color.RGBA{G: 0xff, A: 0xff}
+	target[9] = color.RGBA{R: 0xff, A: 0xff}
+	target[10] = color.RGBA{R: 0x70, G: 0x70, B: 0x70, A: 0xff}
+
+	for idx := 11; idx < len(target); idx++ {
+		target[idx] = color.RGBA{B: 0xff, A: 0xff}
+	}
+
+	table := RemapTable(&source, &target, []IndexRange{{0, 0}})
+
+	// black (index 0) of target palette is locked, so dark colors aren't mapped to it
+	expected := map[int]byte{0: 0, 1: 9, 2: 5, 3: 10, 4: 1}
+
+	for idx, want := range expected {
+		if table[idx] != want {
+			t.Fatalf("index %d: expected %d, got %d", idx, want, table[idx])
+		}
+	}
+
+	indices := []byte{0, 1, 2, 3}
+	Remap(indices, table)
+
+	if !reflect.DeepEqual(indices, []byte{0, 9, 5, 10}) {
+		t.Fatalf("unexpected remapped indices %v", indices)
+	}
+}
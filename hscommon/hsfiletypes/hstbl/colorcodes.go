@@ -0,0 +1,151 @@
+package hstbl
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// colorCodeLatin1 is a color code prefix in latin1-encoded tables (ÿc stored as 0xFF 'c')
+	colorCodeLatin1 = "\xffc"
+	// colorCodeUTF8 is a color code prefix in UTF-8 encoded tables
+	colorCodeUTF8 = "ÿc"
+	// colorCodeLength is a length of color code without the prefix
+	colorCodeLength = 1
+)
+
+// TextColor represents a color of text used by D2
+type TextColor byte
+
+// D2 text colors (ÿc0 - ÿc;)
+const (
+	TextColorWhite      TextColor = '0'
+	TextColorRed        TextColor = '1'
+	TextColorGreen      TextColor = '2'
+	TextColorBlue       TextColor = '3'
+	TextColorGold       TextColor = '4'
+	TextColorGray       TextColor = '5'
+	TextColorBlack      TextColor = '6'
+	TextColorTan        TextColor = '7'
+	TextColorOrange     TextColor = '8'
+	TextColorYellow     TextColor = '9'
+	TextColorDarkGreen  TextColor = ':'
+	TextColorPurple     TextColor = ';'
+	TextColorWhiteSlash TextColor = '/'
+)
+
+// RGBA returns RGBA color of D2 text color
+func (c TextColor) RGBA() (color.RGBA, bool) {
+	table := map[TextColor]color.RGBA{
+		TextColorWhite:      {R: 0xc4, G: 0xc4, B: 0xc4, A: 0xff},
+		TextColorRed:        {R: 0xd4, G: 0x40, B: 0x40, A: 0xff},
+		TextColorGreen:      {R: 0x18, G: 0xfc, B: 0x00, A: 0xff},
+		TextColorBlue:       {R: 0x68, G: 0x68, B: 0xfc, A: 0xff},
+		TextColorGold:       {R: 0xc7, G: 0xb3, B: 0x77, A: 0xff},
+		TextColorGray:       {R: 0x69, G: 0x69, B: 0x69, A: 0xff},
+		TextColorBlack:      {R: 0x00, G: 0x00, B: 0x00, A: 0xff},
+		TextColorTan:        {R: 0xd0, G: 0xc2, B: 0x7d, A: 0xff},
+		TextColorOrange:     {R: 0xff, G: 0xa8, B: 0x00, A: 0xff},
+		TextColorYellow:     {R: 0xff, G: 0xff, B: 0x64, A: 0xff},
+		TextColorDarkGreen:  {R: 0x00, G: 0x80, B: 0x00, A: 0xff},
+		TextColorPurple:     {R: 0xae, G: 0x00, B: 0xff, A: 0xff},
+		TextColorWhiteSlash: {R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+
+	rgba, found := table[c]
+
+	return rgba, found
+}
+
+// TextSpan is a part of text printed in a single color
+type TextSpan struct {
+	Color TextColor
+	Text  string
+}
+
+// TextLine is a single line of colored text
+type TextLine []TextSpan
+
+// String returns line's text without color codes
+func (l TextLine) String() string {
+	var sb strings.Builder
+
+	for _, span := range l {
+		sb.WriteString(span.Text)
+	}
+
+	return sb.String()
+}
+
+// ParseColorCodes splits string table's value into lines of colored UTF-8 spans (lines are returned
+// in the order they're stored; D2 prints multi-line item descriptions bottom-up).
+// Warnings describe malformed color codes, which the game would print as garbage.
+func ParseColorCodes(s string) (lines []TextLine, warnings []string) {
+	enc := EncodingUTF8
+	if !utf8.ValidString(s) {
+		enc = EncodingLatin1
+	}
+
+	current := TextColorWhite
+	line := make(TextLine, 0)
+
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			line = append(line, TextSpan{Color: current, Text: enc.decode(text.String())})
+			text.Reset()
+		}
+	}
+
+	for idx := 0; idx < len(s); idx++ {
+		prefixLen := 0
+
+		switch {
+		case strings.HasPrefix(s[idx:], colorCodeLatin1):
+			prefixLen = len(colorCodeLatin1)
+		case strings.HasPrefix(s[idx:], colorCodeUTF8):
+			prefixLen = len(colorCodeUTF8)
+		case s[idx] == '\xff' || strings.HasPrefix(s[idx:], "ÿ"):
+			warnings = append(warnings, fmt.Sprintf("offset %d: ÿ is not followed by 'c'", idx))
+		case s[idx] == '\n':
+			flush()
+
+			lines = append(lines, line)
+			line = make(TextLine, 0)
+
+			continue
+		}
+
+		if prefixLen == 0 {
+			text.WriteByte(s[idx])
+
+			continue
+		}
+
+		if idx+prefixLen+colorCodeLength > len(s) {
+			warnings = append(warnings, fmt.Sprintf("offset %d: color code at the end of text", idx))
+
+			break
+		}
+
+		code := TextColor(s[idx+prefixLen])
+		if _, known := code.RGBA(); !known {
+			warnings = append(warnings, fmt.Sprintf("offset %d: unknown color code ÿc%c", idx, code))
+		} else {
+			flush()
+
+			current = code
+		}
+
+		idx += prefixLen + colorCodeLength - 1
+	}
+
+	flush()
+
+	lines = append(lines, line)
+
+	return lines, warnings
+}
@@ -0,0 +1,106 @@
+package hstbl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+)
+
+const (
+	csvKeyColumn   = "key"
+	csvValueColumn = "value"
+)
+
+func exportCSV(dict d2tbl.TextDictionary, enc Encoding) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+
+	if err := w.Write([]string{csvKeyColumn, csvValueColumn}); err != nil {
+		return nil, fmt.Errorf("error writing csv header: %w", err)
+	}
+
+	for _, key := range sortedKeys(dict) {
+		// csv readers normalize line endings, so the value wouldn't be imported back unchanged
+		if strings.ContainsRune(dict[key], '\r') {
+			return nil, fmt.Errorf("value of %s contains carriage return, which can't be stored in csv; use json or po instead", key)
+		}
+
+		if err := w.Write([]string{enc.decode(key), enc.decode(dict[key])}); err != nil {
+			return nil, fmt.Errorf("error writing csv record: %w", err)
+		}
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("error writing csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func importCSV(data []byte, enc Encoding) (d2tbl.TextDictionary, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	keyIdx, valueIdx := -1, -1
+
+	// translators may add their own columns (e.g. notes), so columns are looked up by name
+	for idx := range header {
+		switch strings.ToLower(strings.TrimSpace(header[idx])) {
+		case csvKeyColumn:
+			keyIdx = idx
+		case csvValueColumn:
+			valueIdx = idx
+		}
+	}
+
+	if keyIdx < 0 || valueIdx < 0 {
+		return nil, errors.New("csv file should have \"key\" and \"value\" columns")
+	}
+
+	result := make(d2tbl.TextDictionary)
+	// lines are numbers of records, which keys were read from
+	lines := make(map[string]int)
+
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading csv record: %w", err)
+		}
+
+		if keyIdx >= len(record) || valueIdx >= len(record) {
+			return nil, fmt.Errorf("record %d: not enough columns", line)
+		}
+
+		if record[keyIdx] == "" {
+			return nil, fmt.Errorf("record %d: empty key", line)
+		}
+
+		key, value, err := enc.encodeEntry(record[keyIdx], record[valueIdx])
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+
+		if first, found := lines[key]; found {
+			return nil, fmt.Errorf("record %d: duplicate key %q (first in record %d)", line, record[keyIdx], first)
+		}
+
+		lines[key] = line
+		result[key] = value
+	}
+
+	return result, nil
+}
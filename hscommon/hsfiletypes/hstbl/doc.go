@@ -0,0 +1,8 @@
+// Package hstbl contains import/export of string tables (d2tbl.TextDictionary)
+// from/to formats used by translators: CSV, JSON and gettext PO.
+//
+// String table values are raw bytes; tables, which don't contain multi-byte UTF-8 characters
+// (e.g. these with ÿc color codes stored as a single 0xFF byte, like the game expects them)
+// are exported as ISO-8859-1 (latin1), so that exporting and importing back gives exactly
+// the same bytes.
+package hstbl
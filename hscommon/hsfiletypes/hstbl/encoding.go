@@ -0,0 +1,92 @@
+package hstbl
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+)
+
+// Encoding represents an encoding of string table's values
+type Encoding int
+
+// encodings
+const (
+	EncodingUTF8 Encoding = iota
+	EncodingLatin1
+)
+
+const maxLatin1Rune = 0xff
+
+// DetectEncoding returns encoding of string table given. The game expects single-byte (latin1) text,
+// so UTF-8 is detected only if the table already contains valid multi-byte UTF-8 characters
+func DetectEncoding(dict d2tbl.TextDictionary) Encoding {
+	multiByte := false
+
+	for key, value := range dict {
+		for _, s := range []string{key, value} {
+			if !utf8.ValidString(s) {
+				return EncodingLatin1
+			}
+
+			if utf8.RuneCountInString(s) != len(s) {
+				multiByte = true
+			}
+		}
+	}
+
+	if multiByte {
+		return EncodingUTF8
+	}
+
+	return EncodingLatin1
+}
+
+// decode converts table's value into UTF-8 text
+func (e Encoding) decode(s string) string {
+	if e == EncodingUTF8 {
+		return s
+	}
+
+	var sb strings.Builder
+
+	for idx := 0; idx < len(s); idx++ {
+		sb.WriteRune(rune(s[idx]))
+	}
+
+	return sb.String()
+}
+
+// encode converts UTF-8 text back into table's value. Characters, which
+// couldn't be encoded in latin1 are reported as an error
+func (e Encoding) encode(s string) (string, error) {
+	if e == EncodingUTF8 {
+		return s, nil
+	}
+
+	var sb strings.Builder
+
+	for _, r := range s {
+		if r > maxLatin1Rune {
+			return "", fmt.Errorf("character %q (U+%04X) couldn't be encoded in latin1", r, r)
+		}
+
+		sb.WriteByte(byte(r))
+	}
+
+	return sb.String(), nil
+}
+
+// encodeEntry encodes table's key and value; errors name the key
+func (e Encoding) encodeEntry(key, value string) (encodedKey, encodedValue string, err error) {
+	if encodedKey, err = e.encode(key); err != nil {
+		return "", "", fmt.Errorf("key %q: %w", key, err)
+	}
+
+	if encodedValue, err = e.encode(value); err != nil {
+		return "", "", fmt.Errorf("value of key %q: %w", key, err)
+	}
+
+	return encodedKey, encodedValue, nil
+}
@@ -0,0 +1,88 @@
+package hstbl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+)
+
+// Format represents a translators' file format
+type Format int
+
+// formats
+const (
+	FormatCSV Format = iota
+	FormatJSON
+	FormatPO
+)
+
+// FormatFromExtension returns format of file with extension given (e.g. ".po")
+func FormatFromExtension(ext string) (Format, error) {
+	for _, f := range []Format{FormatCSV, FormatJSON, FormatPO} {
+		if strings.EqualFold(f.Extension(), ext) {
+			return f, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unsupported file extension %q (supported are .csv, .json and .po)", ext)
+}
+
+// Extension returns format's file extension
+func (f Format) Extension() string {
+	table := map[Format]string{
+		FormatCSV:  ".csv",
+		FormatJSON: ".json",
+		FormatPO:   ".po",
+	}
+
+	return table[f]
+}
+
+// Export encodes string table into format given
+func Export(dict d2tbl.TextDictionary, f Format) ([]byte, error) {
+	enc := DetectEncoding(dict)
+
+	switch f {
+	case FormatCSV:
+		return exportCSV(dict, enc)
+	case FormatJSON:
+		return exportJSON(dict, enc)
+	case FormatPO:
+		return exportPO(dict, enc), nil
+	}
+
+	return nil, fmt.Errorf("unknown format %d", f)
+}
+
+// Import decodes string table from format given. Values are encoded using encoding given;
+// use DetectEncoding of the table, which is going to be merged with the imported one;
+// characters, which the encoding can't represent, are reported as an error.
+func Import(data []byte, f Format, enc Encoding) (d2tbl.TextDictionary, error) {
+	// some editors (e.g. spreadsheets) put UTF-8 byte order mark at the beginning of file
+	data = []byte(strings.TrimPrefix(string(data), "\ufeff"))
+
+	switch f {
+	case FormatCSV:
+		return importCSV(data, enc)
+	case FormatJSON:
+		return importJSON(data, enc)
+	case FormatPO:
+		return importPO(data, enc)
+	}
+
+	return nil, fmt.Errorf("unknown format %d", f)
+}
+
+func sortedKeys(dict d2tbl.TextDictionary) []string {
+	keys := make([]string, 0, len(dict))
+
+	for key := range dict {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
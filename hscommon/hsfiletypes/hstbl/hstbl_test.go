@@ -0,0 +1,128 @@
+package hstbl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+)
+
+func TestExportImport(t *testing.T) {
+	dict := d2tbl.TextDictionary{
+		"#0":        "",
+		"ShopTitle": "\xffc4Charsi\n\xffc0\"Repair\" \\n\tall",
+		"Sock":      "line 1\nline 2",
+	}
+
+	for _, f := range []Format{FormatCSV, FormatJSON, FormatPO} {
+		data, err := Export(dict, f)
+		if err != nil {
+			t.Fatalf("%s: %v", f.Extension(), err)
+		}
+
+		imported, err := Import(data, f, DetectEncoding(dict))
+		if err != nil {
+			t.Fatalf("%s: %v", f.Extension(), err)
+		}
+
+		if changes := Compare(dict, imported); !changes.Empty() {
+			t.Fatalf("%s: unexpected changes after import: %+v", f.Extension(), changes)
+		}
+	}
+}
+
+func TestExportCSVCarriageReturn(t *testing.T) {
+	if _, err := Export(d2tbl.TextDictionary{"key": "line 1\r\nline 2"}, FormatCSV); err == nil {
+		t.Fatal("expected error for value containing carriage return")
+	}
+}
+
+func TestImportPO(t *testing.T) {
+	po := `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+# translator's comment
+msgctxt "key1"
+msgid "Hello"
+msgstr "Hallo "
+"Welt"
+
+msgctxt "key2"
+msgid "untranslated"
+msgstr ""
+`
+
+	dict, err := Import([]byte(po), FormatPO, EncodingUTF8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dict["key1"] != "Hallo Welt" || dict["key2"] != "untranslated" {
+		t.Fatalf("unexpected result %v", dict)
+	}
+}
+
+func TestParseColorCodes(t *testing.T) {
+	lines, warnings := ParseColorCodes("\xffc4Unique\nÿc3Magic ÿcXbad\xffc")
+
+	if len(lines) != 2 {
+		t.Fatalf("unexpected number of lines %d", len(lines))
+	}
+
+	if lines[0][0].Color != TextColorGold || lines[0].String() != "Unique" {
+		t.Fatalf("unexpected first line %v", lines[0])
+	}
+
+	if lines[1][0].Color != TextColorBlue || lines[1].String() != "Magic bad" {
+		t.Fatalf("unexpected second line %v", lines[1])
+	}
+
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", warnings)
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		dict     d2tbl.TextDictionary
+		expected Encoding
+	}{
+		{d2tbl.TextDictionary{"key": "ascii"}, EncodingLatin1},
+		{d2tbl.TextDictionary{"key": "\xffc1red"}, EncodingLatin1},
+		{d2tbl.TextDictionary{"key": "Zażółć"}, EncodingUTF8},
+	}
+
+	for _, test := range tests {
+		if got := DetectEncoding(test.dict); got != test.expected {
+			t.Fatalf("%v: expected encoding %d, got %d", test.dict, test.expected, got)
+		}
+	}
+
+	// color codes imported into an ASCII table should be stored the way the game expects them
+	imported, err := Import([]byte("key,value\nname,ÿc1red\n"), FormatCSV, DetectEncoding(d2tbl.TextDictionary{"a": "b"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported["name"] != "\xffc1red" {
+		t.Fatalf("unexpected value %q", imported["name"])
+	}
+}
+
+func TestImportCSVDuplicateKey(t *testing.T) {
+	if _, err := Import([]byte("key,value\na,1\nb,2\na,3\n"), FormatCSV, EncodingLatin1); err == nil {
+		t.Fatal("expected duplicate key to be reported")
+	}
+}
+
+func TestImportLatin1InvalidCharacter(t *testing.T) {
+	_, err := Import([]byte("key,value\nname,Zażółć\n"), FormatCSV, EncodingLatin1)
+	if err == nil || !strings.Contains(err.Error(), `"name"`) || !strings.Contains(err.Error(), "U+017C") {
+		t.Fatalf("expected error naming key and character, got %v", err)
+	}
+
+	if _, err := Import([]byte("key,value\nname,Zażółć\n"), FormatCSV, EncodingUTF8); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,56 @@
+package hstbl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+)
+
+func exportJSON(dict d2tbl.TextDictionary, enc Encoding) ([]byte, error) {
+	decoded := make(map[string]string, len(dict))
+
+	for key, value := range dict {
+		decoded[enc.decode(key)] = enc.decode(value)
+	}
+
+	buf := &bytes.Buffer{}
+
+	// html escaping would make color codes and tags hard to read for translators
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(decoded); err != nil {
+		return nil, fmt.Errorf("error encoding json: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func importJSON(data []byte, enc Encoding) (d2tbl.TextDictionary, error) {
+	decoded := make(map[string]string)
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("error decoding json: %w", err)
+	}
+
+	result := make(d2tbl.TextDictionary, len(decoded))
+
+	for key, value := range decoded {
+		if key == "" {
+			return nil, errors.New("empty key")
+		}
+
+		encodedKey, encodedValue, err := enc.encodeEntry(key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		result[encodedKey] = encodedValue
+	}
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package hstbl
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+)
+
+// Changes represents differences between a string table and the imported one
+type Changes struct {
+	// Added are keys present only in imported table
+	Added []string
+	// Changed are keys, which values differ
+	Changed []string
+	// Removed are keys missing in imported table
+	Removed []string
+}
+
+// Compare returns keys added, changed and removed by importing table given
+func Compare(current, imported d2tbl.TextDictionary) *Changes {
+	result := &Changes{
+		Added:   make([]string, 0),
+		Changed: make([]string, 0),
+		Removed: make([]string, 0),
+	}
+
+	for _, key := range sortedKeys(imported) {
+		value, found := current[key]
+
+		switch {
+		case !found:
+			result.Added = append(result.Added, key)
+		case value != imported[key]:
+			result.Changed = append(result.Changed, key)
+		}
+	}
+
+	for _, key := range sortedKeys(current) {
+		if _, found := imported[key]; !found {
+			result.Removed = append(result.Removed, key)
+		}
+	}
+
+	return result
+}
+
+// Empty returns true if there is nothing to merge
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// Apply merges imported table into current one; keys missing in imported table are deleted only if remove is true
+func (c *Changes) Apply(current, imported d2tbl.TextDictionary, remove bool) {
+	for _, key := range c.Added {
+		current[key] = imported[key]
+	}
+
+	for _, key := range c.Changed {
+		current[key] = imported[key]
+	}
+
+	if !remove {
+		return
+	}
+
+	for _, key := range c.Removed {
+		delete(current, key)
+	}
+}
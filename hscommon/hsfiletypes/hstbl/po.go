@@ -0,0 +1,205 @@
+package hstbl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+)
+
+const (
+	poContext = "msgctxt"
+	poID      = "msgid"
+	poString  = "msgstr"
+	// a keyword line consists of keyword and quoted string
+	poKeywordParts = 2
+	poHeader       = `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+`
+)
+
+// poEntry represents a single PO entry; string table's key is stored in msgctxt,
+// the original value in msgid and the translation in msgstr
+type poEntry struct {
+	context, id, str      string
+	hasContext, hasString bool
+}
+
+// poQuote returns a quoted PO string
+func poQuote(s string) string {
+	var sb strings.Builder
+
+	sb.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < ' ' {
+				sb.WriteString(fmt.Sprintf(`\x%02x`, r))
+
+				continue
+			}
+
+			sb.WriteRune(r)
+		}
+	}
+
+	sb.WriteByte('"')
+
+	return sb.String()
+}
+
+func exportPO(dict d2tbl.TextDictionary, enc Encoding) []byte {
+	buf := &bytes.Buffer{}
+
+	buf.WriteString(poHeader)
+
+	for _, key := range sortedKeys(dict) {
+		value := poQuote(enc.decode(dict[key]))
+
+		fmt.Fprintf(buf, "\n%s %s\n%s %s\n%s %s\n", poContext, poQuote(enc.decode(key)), poID, value, poString, value)
+	}
+
+	return buf.Bytes()
+}
+
+func importPO(data []byte, enc Encoding) (d2tbl.TextDictionary, error) {
+	result := make(d2tbl.TextDictionary)
+
+	var (
+		entry   poEntry
+		field   *string
+		started bool
+	)
+
+	finish := func() error {
+		defer func() {
+			entry, field, started = poEntry{}, nil, false
+		}()
+
+		if !started {
+			return nil
+		}
+
+		if !entry.hasContext {
+			// PO header
+			if entry.id == "" {
+				return nil
+			}
+
+			return fmt.Errorf("entry %q has no msgctxt (string table key)", entry.id)
+		}
+
+		value := entry.str
+		if value == "" {
+			// not translated yet
+			value = entry.id
+		}
+
+		key, value, err := enc.encodeEntry(entry.context, value)
+		if err != nil {
+			return err
+		}
+
+		result[key] = value
+
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		var keyword, quoted string
+
+		switch {
+		case text == "":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+
+			continue
+		case text[0] == '#':
+			continue
+		case text[0] == '"':
+			if field == nil {
+				return nil, fmt.Errorf("line %d: unexpected string", line)
+			}
+
+			quoted = text
+		default:
+			parts := strings.SplitN(text, " ", poKeywordParts)
+			if len(parts) != poKeywordParts {
+				return nil, fmt.Errorf("line %d: unexpected %q", line, text)
+			}
+
+			keyword, quoted = parts[0], strings.TrimSpace(parts[1])
+		}
+
+		s, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid string %s: %w", line, quoted, err)
+		}
+
+		if keyword == "" {
+			*field += s
+
+			continue
+		}
+
+		// msgctxt or msgid after msgstr starts a new entry
+		if (keyword == poContext || keyword == poID) && entry.hasString {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+		}
+
+		started = true
+
+		switch keyword {
+		case poContext:
+			entry.hasContext = true
+			field = &entry.context
+		case poID:
+			field = &entry.id
+		case poString:
+			entry.hasString = true
+			field = &entry.str
+		default:
+			return nil, fmt.Errorf("line %d: unsupported keyword %s", line, keyword)
+		}
+
+		*field = s
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading po file: %w", err)
+	}
+
+	if err := finish(); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("po file contains no entries")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package hsproject
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2cof"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsanimdata"
+	"github.com/OpenDiablo2/HellSpawner/hsconfig"
+)
+
+// isAnimatedCOF returns true if path (relative to data root) is a COF of an animated unit
+// (character, monster, object, overlay, missile, ...) in data\global
+func isAnimatedCOF(path string) bool {
+	path = strings.ToLower(strings.ReplaceAll(path, `\`, "/"))
+
+	return filepath.Ext(path) == ".cof" && strings.Contains(path, "data/global/")
+}
+
+// cofInfo describes COF; if it can't be read or loaded, the error is stored in COFInfo,
+// so that COF is reported as unreadable
+func cofInfo(source string, data []byte, err error) hsanimdata.COFInfo {
+	base := filepath.Base(strings.ReplaceAll(source, `\`, "/"))
+
+	result := hsanimdata.COFInfo{
+		Name:   strings.ToUpper(strings.TrimSuffix(base, filepath.Ext(base))),
+		Source: source,
+	}
+
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot read: %v", err)
+
+		return result
+	}
+
+	cof, err := d2cof.Unmarshal(data)
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot load: %v", err)
+
+		return result
+	}
+
+	result.FramesPerDirection, result.Speed = cof.FramesPerDirection, cof.Speed
+
+	return result
+}
+
+// ScanCOFs returns COFs of animated units (all COFs of data\global) found in auxiliary MPQs
+// and in project. Project files override the MPQ ones.
+func (p *Project) ScanCOFs(config *hsconfig.Config) ([]hsanimdata.COFInfo, error) {
+	byName := make(map[string]hsanimdata.COFInfo)
+
+	for _, mpq := range p.mpqs {
+		if mpq == nil {
+			continue
+		}
+
+		files, err := mpq.Listfile()
+		if err != nil {
+			if files, err = p.searchForMpqFiles(mpq, config); err != nil {
+				return nil, fmt.Errorf("error listing files of %s: %w", mpq.Path(), err)
+			}
+		}
+
+		for _, file := range files {
+			if !isAnimatedCOF(file) {
+				continue
+			}
+
+			data, err := mpq.ReadFile(file)
+			info := cofInfo(file, data, err)
+			byName[info.Name] = info
+		}
+	}
+
+	err := filepath.Walk(p.GetProjectFileContentPath(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := p.DataPath(path)
+		if err != nil || !isAnimatedCOF(file) {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(filepath.Clean(path))
+		cof := cofInfo(file, data, err)
+		byName[cof.Name] = cof
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning COF files: %w", err)
+	}
+
+	result := make([]hsanimdata.COFInfo, 0, len(byName))
+	for _, cof := range byName {
+		result = append(result, cof)
+	}
+
+	return result, nil
+}
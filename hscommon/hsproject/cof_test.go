@@ -0,0 +1,23 @@
+package hsproject
+
+import "testing"
+
+func TestScanCOFs(t *testing.T) {
+	p := testProject(t, map[string][]byte{
+		"global/monsters/aa/cof/AAA1HS.cof": []byte("not a COF"),
+		"global/excel/armor.txt":            nil,
+	})
+
+	cofs, err := p.ScanCOFs(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cofs) != 1 || cofs[0].Name != "AAA1HS" || cofs[0].Source != `data\global\monsters\aa\cof\AAA1HS.cof` {
+		t.Fatalf("unexpected COFs %+v", cofs)
+	}
+
+	if cofs[0].Error == "" {
+		t.Fatal("expected COF to be reported as unreadable")
+	}
+}
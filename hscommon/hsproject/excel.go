@@ -0,0 +1,73 @@
+package hsproject
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsbin"
+)
+
+// CompileExcelTables compiles every txt table in project, which has a known record layout,
+// into a .bin file placed next to it. Returns paths of compiled files.
+func (p *Project) CompileExcelTables() (compiled []string, err error) {
+	err = filepath.Walk(p.GetProjectFileContentPath(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(path), hsfiletypes.FileTypeText.FileExtension()) {
+			return nil
+		}
+
+		layout, found := hsbin.LayoutFor(path)
+		if !found {
+			return nil
+		}
+
+		binPath := strings.TrimSuffix(path, filepath.Ext(path)) + hsfiletypes.FileTypeBIN.FileExtension()
+		if err := compileExcelTable(layout, path, binPath); err != nil {
+			return err
+		}
+
+		compiled = append(compiled, binPath)
+
+		return nil
+	})
+
+	if len(compiled) > 0 {
+		p.InvalidateFileStructure()
+	}
+
+	if err != nil {
+		return compiled, fmt.Errorf("error compiling excel tables: %w", err)
+	}
+
+	return compiled, nil
+}
+
+func compileExcelTable(layout *hsbin.Layout, txtPath, binPath string) error {
+	txt, err := ioutil.ReadFile(filepath.Clean(txtPath))
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", txtPath, err)
+	}
+
+	table, err := hsbin.LoadTXT(txt)
+	if err != nil {
+		return fmt.Errorf("cannot load %s: %w", txtPath, err)
+	}
+
+	data, err := layout.Encode(table)
+	if err != nil {
+		return fmt.Errorf("cannot compile %s: %w", txtPath, err)
+	}
+
+	if err := ioutil.WriteFile(binPath, data, os.FileMode(newFileMode)); err != nil {
+		return fmt.Errorf("cannot write %s: %w", binPath, err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -102,3 +103,26 @@ func (p *Project) searchForMpqFiles(mpq d2interface.Archive, config *hsconfig.Co
 
 	return files, nil
 }
+
+// ReadFile reads a file of path given (MPQ notation, e.g. data\global\excel\armor.txt)
+// from project or, if the project has no such file, from auxiliary MPQs
+func (p *Project) ReadFile(path string) ([]byte, error) {
+	if data, err := ioutil.ReadFile(filepath.Clean(p.ProjectPath(path))); err == nil {
+		return data, nil
+	}
+
+	for _, mpq := range p.mpqs {
+		if mpq == nil || !mpq.Contains(path) {
+			continue
+		}
+
+		data, err := mpq.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s from %s: %w", path, mpq.Path(), err)
+		}
+
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("file %s not found in project and auxiliary MPQs", path)
+}
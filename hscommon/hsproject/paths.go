@@ -0,0 +1,33 @@
+package hsproject
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// dataRoot is a root folder of MPQs' files; project's content folder is the data root,
+// so files copied from MPQs into project (see MPQ explorer) are stored without it
+const dataRoot = "data"
+
+// ProjectPath returns absolute path of project's file, which overrides MPQs' file given
+// (MPQ notation, e.g. data\global\excel\armor.txt)
+func (p *Project) ProjectPath(dataPath string) string {
+	path := strings.ReplaceAll(dataPath, `\`, "/")
+	if strings.HasPrefix(strings.ToLower(path), dataRoot+"/") {
+		path = path[len(dataRoot):]
+	}
+
+	return filepath.Join(p.GetProjectFileContentPath(), filepath.FromSlash(path))
+}
+
+// DataPath returns MPQ notation path (e.g. data\global\excel\armor.txt) of project's file
+// (absolute path); it's the inverse of ProjectPath
+func (p *Project) DataPath(path string) (string, error) {
+	rel, err := filepath.Rel(p.GetProjectFileContentPath(), path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s isn't a project's file", path)
+	}
+
+	return dataRoot + `\` + strings.ReplaceAll(filepath.ToSlash(rel), "/", `\`), nil
+}
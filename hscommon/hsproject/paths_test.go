@@ -0,0 +1,68 @@
+package hsproject
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testProject creates a project in temporary directory; files (paths relative to project's content,
+// slash separated) are written into it
+func testProject(t *testing.T, files map[string][]byte) *Project {
+	t.Helper()
+
+	p, err := CreateNew(filepath.Join(t.TempDir(), "test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range files {
+		path := filepath.Join(p.GetProjectFileContentPath(), filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), newDirMode); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, data, newFileMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return p
+}
+
+func TestReadFile(t *testing.T) {
+	p := testProject(t, map[string][]byte{"global/excel/armor.txt": []byte("armor")})
+
+	path := filepath.Join(p.GetProjectFileContentPath(), "global", "excel", "armor.txt")
+	if got := p.ProjectPath(`data\global\excel\armor.txt`); got != path {
+		t.Fatalf("expected project path %s, got %s", path, got)
+	}
+
+	dataPath, err := p.DataPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataPath != `data\global\excel\armor.txt` {
+		t.Fatalf("unexpected data path %s", dataPath)
+	}
+
+	if _, err := p.DataPath(filepath.Dir(p.GetProjectFileContentPath())); err == nil {
+		t.Fatal("expected an error for a file outside of project's content")
+	}
+
+	data, err := p.ReadFile(`data\global\excel\armor.txt`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "armor" {
+		t.Fatalf("unexpected data %q", data)
+	}
+
+	if _, err := p.ReadFile(`data\global\excel\weapons.txt`); err == nil {
+		t.Fatal("expected an error reading missing file")
+	}
+}
@@ -42,6 +42,7 @@ type Project struct {
 	filePath       string
 	pathEntryCache *hscommon.PathEntry
 	mpqs           []d2interface.Archive
+	isFileOpen     func(path string) bool
 }
 
 // CreateNew creates new project
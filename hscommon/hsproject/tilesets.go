@@ -0,0 +1,222 @@
+package hsproject
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
+	"github.com/OpenDiablo2/HellSpawner/hsconfig"
+)
+
+const tilesDir = "data/global/tiles/"
+
+// DT1File is a DT1 file found in project or in auxiliary MPQs
+type DT1File struct {
+	// Path is path of the file relative to data root (MPQ notation, e.g. data\global\tiles\act1\town\floor.dt1);
+	// use ReadFile to read it
+	Path string
+	// Entry is used to open the file in an editor
+	Entry *hscommon.PathEntry
+}
+
+// dataPaths converts absolute paths of project's files to MPQ notation paths (see DataPath)
+func (p *Project) dataPaths(paths []string) ([]string, error) {
+	result := make([]string, len(paths))
+
+	for idx, path := range paths {
+		dataPath, err := p.DataPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		result[idx] = dataPath
+	}
+
+	return result, nil
+}
+
+// UpdateDS1Files updates DT1 files listed in project's DS1 files after tiles were moved
+// between DT1 files (see hsds1.ReplaceFiles); paths are absolute paths of project's DT1 files.
+// DS1 files open in editors (see SetFileOpenChecker) aren't changed, so that editors don't
+// overwrite the update; DS1 files, which can't be updated, are reported in the error and
+// the others are updated anyway. Returns paths of updated DS1 files
+func (p *Project) UpdateDS1Files(oldPaths, newPaths []string) (updated []string, err error) {
+	oldFiles, err := p.dataPaths(oldPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	newFiles, err := p.dataPaths(newPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]string, 0)
+
+	err = filepath.Walk(p.GetProjectFileContentPath(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			problems = append(problems, err.Error())
+
+			return nil
+		}
+
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(path), hsfiletypes.FileTypeDS1.FileExtension()) {
+			return nil
+		}
+
+		changed, err := p.updateDS1File(path, oldFiles, newFiles)
+
+		switch {
+		case err != nil:
+			problems = append(problems, err.Error())
+		case changed:
+			updated = append(updated, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return updated, fmt.Errorf("error updating DS1 files: %s", strings.Join(problems, "; "))
+	}
+
+	return updated, nil
+}
+
+// updateDS1File replaces DT1 files listed in DS1 file; returns true if the file was changed
+func (p *Project) updateDS1File(path string, oldFiles, newFiles []string) (changed bool, err error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return false, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	ds1, err := d2ds1.Unmarshal(data)
+	if err != nil {
+		return false, fmt.Errorf("cannot load %s: %w", path, err)
+	}
+
+	if !hsds1.ReplaceFiles(ds1, oldFiles, newFiles) {
+		return false, nil
+	}
+
+	if p.isFileOpen != nil && p.isFileOpen(path) {
+		return false, fmt.Errorf("%s is open in an editor, so it wasn't updated", path)
+	}
+
+	if err := ioutil.WriteFile(path, hsds1.Marshal(ds1), os.FileMode(newFileMode)); err != nil {
+		return false, fmt.Errorf("cannot write %s: %w", path, err)
+	}
+
+	return true, nil
+}
+
+// SetFileOpenChecker sets a function, which returns true if project's file (absolute path)
+// is open in an editor
+func (p *Project) SetFileOpenChecker(isFileOpen func(path string) bool) {
+	p.isFileOpen = isFileOpen
+}
+
+// isTilesetFile returns true if path (relative to data root) is a DT1 file
+// in folder given (e.g. act1) of data\global\tiles; empty folder matches all of them
+func isTilesetFile(path, folder string) bool {
+	path = strings.ToLower(strings.ReplaceAll(path, `\`, "/"))
+
+	if filepath.Ext(path) != hsfiletypes.FileTypeDT1.FileExtension() {
+		return false
+	}
+
+	dir := tilesDir
+	if folder != "" {
+		dir += strings.ToLower(folder) + "/"
+	}
+
+	return strings.Contains(path, dir)
+}
+
+// ScanDT1s returns DT1 files of data\global\tiles (or of its folder given, e.g. act1) found in auxiliary MPQs
+// and in project sorted by path. Project files override the MPQ ones.
+func (p *Project) ScanDT1s(config *hsconfig.Config, folder string) ([]DT1File, error) {
+	byPath := make(map[string]DT1File)
+
+	for _, mpq := range p.mpqs {
+		if mpq == nil {
+			continue
+		}
+
+		files, err := mpq.Listfile()
+		if err != nil {
+			if files, err = p.searchForMpqFiles(mpq, config); err != nil {
+				return nil, fmt.Errorf("error listing files of %s: %w", mpq.Path(), err)
+			}
+		}
+
+		for _, file := range files {
+			if !isTilesetFile(file, folder) {
+				continue
+			}
+
+			byPath[strings.ToLower(file)] = DT1File{
+				Path: file,
+				Entry: &hscommon.PathEntry{
+					Name:     filepath.Base(strings.ReplaceAll(file, `\`, "/")),
+					FullPath: file,
+					Source:   hscommon.PathEntrySourceMPQ,
+					MPQFile:  mpq.Path(),
+				},
+			}
+		}
+	}
+
+	root := p.GetProjectFileContentPath()
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := p.DataPath(path)
+		if err != nil || !isTilesetFile(file, folder) {
+			return nil
+		}
+
+		byPath[strings.ToLower(file)] = DT1File{
+			Path: file,
+			Entry: &hscommon.PathEntry{
+				Name:     info.Name(),
+				FullPath: path,
+				Source:   hscommon.PathEntrySourceProject,
+			},
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning DT1 files: %w", err)
+	}
+
+	result := make([]DT1File, 0, len(byPath))
+	for _, file := range byPath {
+		result = append(result, file)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Path) < strings.ToLower(result[j].Path)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package hsproject
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
+)
+
+// testDS1 returns an encoded version 18 map of a single tile, which lists files given
+func testDS1(t *testing.T, files []string) []byte {
+	t.Helper()
+
+	const (
+		version        = 18
+		numLayerStream = 4 // wall, orientation, floor and shadow
+	)
+
+	sw := d2datautils.CreateStreamWriter()
+	sw.PushInt32(version)
+	sw.PushInt32(0) // width - 1
+	sw.PushInt32(0) // height - 1
+	sw.PushInt32(0) // act 1
+	sw.PushInt32(0) // substitution type
+	sw.PushInt32(0) // files
+	sw.PushInt32(1) // walls
+	sw.PushInt32(1) // floors
+
+	for stream := 0; stream < numLayerStream; stream++ {
+		sw.PushUint32(0)
+	}
+
+	sw.PushInt32(0) // objects
+	sw.PushInt32(0) // npcs
+
+	ds1, err := d2ds1.Unmarshal(sw.GetBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds1.Files = files
+
+	return hsds1.Marshal(ds1)
+}
+
+func TestUpdateDS1Files(t *testing.T) {
+	p := testProject(t, map[string][]byte{
+		"tiles/act1/town/townn1.ds1": testDS1(t, []string{
+			`\d2\data\global\tiles\act1\town\floor.tg1`,
+			`\d2\data\global\tiles\act1\town\trees.tg1`,
+		}),
+		"tiles/act1/town/towns1.ds1": testDS1(t, []string{`\d2\data\global\tiles\act1\town\floor.tg1`}),
+	})
+
+	content := p.GetProjectFileContentPath()
+	dt1 := func(name string) string {
+		return filepath.Join(content, "global", "tiles", "act1", "town", name)
+	}
+
+	// trees are merged into fence
+	updated, err := p.UpdateDS1Files([]string{dt1("trees.dt1")}, []string{dt1("fence.dt1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(content, "tiles", "act1", "town", "townn1.ds1")
+	if !reflect.DeepEqual(updated, []string{path}) {
+		t.Fatalf("unexpected updated files %v", updated)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds1, err := d2ds1.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{`\d2\data\global\tiles\act1\town\floor.tg1`, `\d2\data\global\tiles\act1\town\fence.tg1`}
+	if !reflect.DeepEqual(ds1.Files, expected) {
+		t.Fatalf("unexpected files %v", ds1.Files)
+	}
+}
+
+func TestScanDT1s(t *testing.T) {
+	p := testProject(t, map[string][]byte{
+		"global/tiles/act1/town/floor.dt1":  nil,
+		"global/tiles/act2/sewer/floor.dt1": nil,
+		"global/excel/armor.txt":            nil,
+	})
+
+	files, err := p.ScanDT1s(nil, "act1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 || files[0].Path != `data\global\tiles\act1\town\floor.dt1` {
+		t.Fatalf("unexpected files %v", files)
+	}
+
+	data, err := p.ReadFile(files[0].Path)
+	if err != nil || len(data) != 0 {
+		t.Fatalf("unexpected project file: %v", err)
+	}
+}
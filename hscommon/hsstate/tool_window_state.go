@@ -8,6 +8,7 @@ const (
 	ToolWindowTypeMPQExplorer     = ToolWindowType("MPQ Explorer")
 	ToolWindowTypeProjectExplorer = ToolWindowType("Project Explorer")
 	ToolWindowTypeConsole         = ToolWindowType("Console")
+	ToolWindowTypeTilesetBrowser  = ToolWindowType("Tileset Browser")
 )
 
 // ToolWindowState holds information about tool windows (e.g. MPQ Explorer)
@@ -3,28 +3,35 @@ package hsutil
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-// SplitIntoLinesWithMaxWidth splits the given string into lines considering the given maxChars
+// SplitIntoLinesWithMaxWidth splits the given string into lines considering the given maxChars;
+// D2 color codes (e.g. ÿc1) aren't printed, so they don't count into line's width
 func SplitIntoLinesWithMaxWidth(fullSentence string, maxChars int) []string {
 	lines := make([]string, 0)
 	line := ""
 	totalLength := 0
 	words := strings.Split(fullSentence, " ")
 
-	if len(words[0]) > maxChars {
+	if textWidth(words[0]) > maxChars {
 		// mostly happened within CJK characters (no whitespace)
 		return splitCjkIntoChunks(fullSentence, maxChars)
 	}
 
-	for _, word := range words {
-		totalLength += 1 + len(word)
-		if totalLength > maxChars {
-			totalLength = len(word)
+	for idx, word := range words {
+		wordLength := textWidth(word)
+
+		switch {
+		case idx == 0:
+			totalLength = wordLength
+		case totalLength+1+wordLength > maxChars:
+			totalLength = wordLength
 
 			lines = append(lines, line)
 			line = ""
-		} else {
+		default:
+			totalLength += 1 + wordLength
 			line += " "
 		}
 
@@ -42,8 +49,16 @@ func splitCjkIntoChunks(str string, chars int) []string {
 	chunks := make([]string, 0)
 	i, count := 0, 0
 
-	for j, ch := range str {
-		if ch < unicode.MaxLatin1 {
+	for j := 0; j < len(str); {
+		if n := colorCodeLength(str[j:]); n > 0 {
+			j += n
+
+			continue
+		}
+
+		ch, size := utf8.DecodeRuneInString(str[j:])
+		if ch < unicode.MaxLatin1 || size == 1 {
+			// size is 1 also for latin1-encoded characters
 			count++
 		} else {
 			// assume we're truncating CJK characters
@@ -54,7 +69,40 @@ func splitCjkIntoChunks(str string, chars int) []string {
 			chunks = append(chunks, str[i:j])
 			i, count = j, 0
 		}
+
+		j += size
 	}
 
 	return append(chunks, str[i:])
 }
+
+// colorCodeLength returns length (in bytes) of D2 color code (ÿc followed by color character)
+// at the beginning of str or 0; ÿ is a single byte in latin1-encoded string tables
+func colorCodeLength(str string) int {
+	for _, prefix := range []string{"\xffc", "ÿc"} {
+		if strings.HasPrefix(str, prefix) && len(str) > len(prefix) {
+			return len(prefix) + 1
+		}
+	}
+
+	return 0
+}
+
+// textWidth returns number of printed characters of str (color codes are skipped)
+func textWidth(str string) int {
+	width := 0
+
+	for idx := 0; idx < len(str); {
+		if n := colorCodeLength(str[idx:]); n > 0 {
+			idx += n
+
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(str[idx:])
+		idx += size
+		width++
+	}
+
+	return width
+}
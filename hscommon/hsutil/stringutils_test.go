@@ -0,0 +1,27 @@
+package hsutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitIntoLinesWithMaxWidth(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxChars int
+		expected []string
+	}{
+		{"Short", 10, []string{"Short"}},
+		{"Keep Whole Words", 10, []string{"Keep Whole", "Words"}},
+		{"ÿc4Gold ÿc3Blue words", 9, []string{"ÿc4Gold ÿc3Blue", "words"}},
+		{"\xffc1Ab \xffc2Cd Ef", 5, []string{"\xffc1Ab \xffc2Cd", "Ef"}},
+		{"ÿc1Éléphantÿc2ine", 6, []string{"ÿc1Éléph", "antÿc2ine"}},
+	}
+
+	for _, test := range tests {
+		got := SplitIntoLinesWithMaxWidth(test.text, test.maxChars)
+		if strings.Join(got, "|") != strings.Join(test.expected, "|") {
+			t.Fatalf("%q, %d: expected %q, got %q", test.text, test.maxChars, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package animationplayer provides an animation player shared by sprite widgets (DC6, DCC):
+// playback in several modes, frame stepping and scrubbing, onion skinning of neighbouring
+// frames and overlays of frame bounds and the anchor point.
+package animationplayer
@@ -0,0 +1,269 @@
+package animationplayer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
+	"github.com/OpenDiablo2/HellSpawner/hswidget"
+)
+
+const (
+	comboW              = 100
+	inputIntW           = 30
+	sliderW             = 200
+	playPauseButtonSize = 15
+	anchorSize          = 4
+	maxAlpha            = 255
+)
+
+// Frame is a frame of animation. Rect is where the texture is drawn and Bounds is a bounding box
+// of frame (e.g. DCC frames are drawn in direction's box); both are relative to the anchor point
+type Frame struct {
+	Texture *giu.Texture
+	Rect    image.Rectangle
+	Bounds  image.Rectangle
+}
+
+// Player is a widget, which plays frames of a direction of animation
+type Player struct {
+	id            string
+	state         *State
+	frames        []Frame
+	textureLoader hscommon.TextureLoader
+
+	onAnchorDragged func(delta image.Point)
+}
+
+// Create creates a new player of frames given; state is owned by the caller
+func Create(tl hscommon.TextureLoader, id string, state *State, frames []Frame) *Player {
+	return &Player{
+		id:            id,
+		state:         state,
+		frames:        frames,
+		textureLoader: tl,
+	}
+}
+
+// OnAnchorDragged makes the anchor point draggable; cb receives movement of the anchor
+// (in sprite's pixels) relatively to the current frame
+func (p *Player) OnAnchorDragged(cb func(delta image.Point)) *Player {
+	p.onAnchorDragged = cb
+
+	return p
+}
+
+func previousFrameTint() color.RGBA {
+	return color.RGBA{R: 0xff, G: 0x80, B: 0x80}
+}
+
+func nextFrameTint() color.RGBA {
+	return color.RGBA{R: 0x80, G: 0xff, B: 0x80}
+}
+
+func boundsColor() color.RGBA {
+	return color.RGBA{R: 0xff, G: 0xff, B: 0x00, A: maxAlpha}
+}
+
+func anchorColor() color.RGBA {
+	return color.RGBA{R: 0xff, G: 0x30, B: 0x30, A: maxAlpha}
+}
+
+// Build builds a player
+func (p *Player) Build() {
+	s := p.state
+	numFrames := len(p.frames)
+
+	s.update(numFrames)
+
+	if s.Scale < minScale {
+		s.Scale = minScale
+	}
+
+	giu.Layout{
+		p.makeControlsLayout(),
+		giu.Separator(),
+		giu.Custom(p.buildView),
+		giu.Custom(func() {
+			if numFrames == 0 {
+				return
+			}
+
+			f := p.frames[s.Frame]
+			b := f.bounds()
+
+			giu.Label(fmt.Sprintf("Frame %d/%d: %dx%d at (%d, %d) from anchor",
+				s.Frame, numFrames-1, b.Dx(), b.Dy(), b.Min.X, b.Min.Y)).Build()
+		}),
+	}.Build()
+}
+
+func (p *Player) makeControlsLayout() giu.Layout {
+	s := p.state
+	numFrames := len(p.frames)
+
+	playModeList := make([]string, 0)
+	for i := PlayModeForward; i <= PlayModePingPong; i++ {
+		playModeList = append(playModeList, i.String())
+	}
+
+	pm := int32(s.PlayMode)
+
+	return giu.Layout{
+		giu.Row(
+			giu.ArrowButton("##"+p.id+"stepBack", giu.DirectionLeft).OnClick(func() {
+				s.Step(-1, numFrames)
+			}),
+			hswidget.PlayPauseButton("##"+p.id+"PlayPauseAnimation", &s.IsPlaying, p.textureLoader).
+				Size(playPauseButtonSize, playPauseButtonSize),
+			giu.ArrowButton("##"+p.id+"stepForward", giu.DirectionRight).OnClick(func() {
+				s.Step(1, numFrames)
+			}),
+			giu.Checkbox("Loop##"+p.id+"PlayRepeat", &s.Repeat),
+			giu.Combo("##"+p.id+"PlayModeList", playModeList[s.PlayMode], playModeList, &pm).OnChange(func() {
+				s.PlayMode = PlayMode(pm)
+			}).Size(comboW),
+			giu.InputInt("FPS##"+p.id+"PlayFPS", &s.FPS).Size(inputIntW).OnChange(func() {
+				s.FPS = s.fps()
+			}),
+		),
+		giu.Custom(func() {
+			if numFrames > 1 {
+				giu.SliderInt("Frame##"+p.id+"scrub", &s.Frame, 0, int32(numFrames-1)).Size(sliderW).OnChange(func() {
+					s.IsPlaying = false
+				}).Build()
+			}
+		}),
+		giu.SliderInt("Scale##"+p.id+"scale", &s.Scale, minScale, maxScale).Size(sliderW),
+		giu.Row(
+			giu.Label("Onion skin: previous"),
+			giu.SliderInt("##"+p.id+"onionPrevious", &s.OnionSkin.Previous, 0, maxOnionFrames).Size(inputIntW*2),
+			giu.Label("next"),
+			giu.SliderInt("##"+p.id+"onionNext", &s.OnionSkin.Next, 0, maxOnionFrames).Size(inputIntW*2),
+			giu.Label("opacity %"),
+			giu.SliderInt("##"+p.id+"onionOpacity", &s.OnionSkin.Opacity, 0, maxPercent).Size(inputIntW*2),
+		),
+		giu.Row(
+			giu.Checkbox("Show bounds##"+p.id+"showBounds", &s.ShowBounds),
+			giu.Checkbox("Show anchor##"+p.id+"showAnchor", &s.ShowAnchor),
+		),
+	}
+}
+
+func (f *Frame) bounds() image.Rectangle {
+	if f.Bounds.Empty() {
+		return f.Rect
+	}
+
+	return f.Bounds
+}
+
+// viewRect returns a rectangle containing all frames and the anchor point
+func (p *Player) viewRect() image.Rectangle {
+	result := image.Rect(0, 0, 1, 1)
+
+	for idx := range p.frames {
+		result = result.Union(p.frames[idx].Rect).Union(p.frames[idx].bounds())
+	}
+
+	return result
+}
+
+func (p *Player) buildView() {
+	s := p.state
+	numFrames := len(p.frames)
+
+	view := p.viewRect()
+	scale := int(s.Scale)
+	pos := giu.GetCursorScreenPos()
+	canvas := giu.GetCanvas()
+
+	// toScreen converts point relative to anchor into screen coordinates
+	toScreen := func(pt image.Point) image.Point {
+		return pos.Add(pt.Sub(view.Min).Mul(scale))
+	}
+
+	drawFrame := func(idx int, tint color.RGBA) {
+		f := &p.frames[idx]
+		if f.Texture == nil || f.Rect.Empty() {
+			return
+		}
+
+		canvas.AddImageV(f.Texture, toScreen(f.Rect.Min), toScreen(f.Rect.Max), image.Pt(0, 0), image.Pt(1, 1), tint)
+	}
+
+	if numFrames > 0 {
+		opacity := float64(s.OnionSkin.Opacity) / maxPercent
+
+		for i := int(s.OnionSkin.Previous); i > 0; i-- {
+			tint := previousFrameTint()
+			tint.A = uint8(maxAlpha * opacity / float64(i))
+			drawFrame(hsutil.Wrap(int(s.Frame)-i, numFrames), tint)
+		}
+
+		for i := int(s.OnionSkin.Next); i > 0; i-- {
+			tint := nextFrameTint()
+			tint.A = uint8(maxAlpha * opacity / float64(i))
+			drawFrame(hsutil.Wrap(int(s.Frame)+i, numFrames), tint)
+		}
+
+		drawFrame(int(s.Frame), color.RGBA{R: maxAlpha, G: maxAlpha, B: maxAlpha, A: maxAlpha})
+
+		if s.ShowBounds {
+			b := p.frames[s.Frame].bounds()
+			canvas.AddRect(toScreen(b.Min), toScreen(b.Max), boundsColor(), 0, 0, 1)
+		}
+	}
+
+	anchor := toScreen(image.Point{})
+
+	if s.ShowAnchor || p.onAnchorDragged != nil {
+		canvas.AddLine(anchor.Sub(image.Pt(anchorSize, 0)), anchor.Add(image.Pt(anchorSize, 0)), anchorColor(), 1)
+		canvas.AddLine(anchor.Sub(image.Pt(0, anchorSize)), anchor.Add(image.Pt(0, anchorSize)), anchorColor(), 1)
+	}
+
+	if p.onAnchorDragged != nil && numFrames > 0 {
+		p.buildAnchorHandle(anchor)
+		imgui.SetCursorScreenPos(imgui.Vec2{X: float32(pos.X), Y: float32(pos.Y)})
+	}
+
+	giu.Dummy(float32(view.Dx()*scale), float32(view.Dy()*scale)).Build()
+}
+
+// buildAnchorHandle builds an invisible button over the anchor, which can be dragged
+func (p *Player) buildAnchorHandle(anchor image.Point) {
+	s := p.state
+	scale := float32(s.Scale)
+
+	imgui.SetCursorScreenPos(imgui.Vec2{X: float32(anchor.X - anchorSize), Y: float32(anchor.Y - anchorSize)})
+	imgui.InvisibleButton("##"+p.id+"anchorHandle", imgui.Vec2{X: anchorSize * 2, Y: anchorSize * 2})
+
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Drag to move the frame's anchor point")
+	}
+
+	if !imgui.IsItemActive() {
+		s.dragX, s.dragY = 0, 0
+
+		return
+	}
+
+	mouseDelta := imgui.CurrentIO().MouseDelta()
+	s.dragX += mouseDelta.X
+	s.dragY += mouseDelta.Y
+
+	delta := image.Pt(int(s.dragX/scale), int(s.dragY/scale))
+	if delta == (image.Point{}) {
+		return
+	}
+
+	s.dragX -= float32(delta.X) * scale
+	s.dragY -= float32(delta.Y) * scale
+
+	p.onAnchorDragged(delta)
+}
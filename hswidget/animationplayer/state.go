@@ -0,0 +1,172 @@
+package animationplayer
+
+import (
+	"time"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
+)
+
+const (
+	defaultFPS     = 10
+	defaultOpacity = 40
+	maxFPS         = 60
+	maxOnionFrames = 5
+	maxPercent     = 100
+	minScale       = 1
+	maxScale       = 8
+	milliseconds   = 1000
+)
+
+// PlayMode is a mode of playing animation
+type PlayMode byte
+
+// play modes
+const (
+	PlayModeForward PlayMode = iota
+	PlayModeBackward
+	PlayModePingPong
+)
+
+func (a PlayMode) String() string {
+	s := map[PlayMode]string{
+		PlayModeForward:  "Forwards",
+		PlayModeBackward: "Backwards",
+		PlayModePingPong: "Ping-Pong",
+	}
+
+	k, ok := s[a]
+	if !ok {
+		return "Unknown"
+	}
+
+	return k
+}
+
+// OnionSkin describes which neighbouring frames are drawn under the current one
+type OnionSkin struct {
+	Previous int32
+	Next     int32
+	// Opacity of the nearest frames (in percents)
+	Opacity int32
+}
+
+// State is a state of player; widgets embed it into their (saved) states
+type State struct {
+	Frame     int32
+	IsPlaying bool
+	Repeat    bool
+	PlayMode  PlayMode
+	FPS       int32
+	Scale     int32
+	OnionSkin OnionSkin
+
+	ShowBounds bool
+	ShowAnchor bool
+
+	isForward bool
+	lastTick  time.Time
+	// dragX, dragY are parts of anchor's movement smaller than a pixel (of scaled sprite)
+	dragX, dragY float32
+}
+
+// NewState creates a new player state
+func NewState() State {
+	return State{
+		PlayMode:  PlayModeForward,
+		FPS:       defaultFPS,
+		Scale:     minScale,
+		OnionSkin: OnionSkin{Opacity: defaultOpacity},
+	}
+}
+
+// TickTime returns duration of a frame in milliseconds
+func (s *State) TickTime() int32 {
+	return milliseconds / s.fps()
+}
+
+func (s *State) fps() int32 {
+	switch {
+	case s.FPS < 1:
+		return 1
+	case s.FPS > maxFPS:
+		return maxFPS
+	}
+
+	return s.FPS
+}
+
+// Step moves current frame by delta (wrapping around) and stops playing
+func (s *State) Step(delta, numFrames int) {
+	s.IsPlaying = false
+
+	if numFrames > 0 {
+		s.Frame = int32(hsutil.Wrap(int(s.Frame)+delta, numFrames))
+	}
+}
+
+// update moves the current frame according to time elapsed since the last update
+func (s *State) update(numFrames int) {
+	if numFrames <= 0 {
+		s.Frame = 0
+
+		return
+	}
+
+	if int(s.Frame) >= numFrames || s.Frame < 0 {
+		s.Frame = 0
+	}
+
+	if !s.IsPlaying {
+		s.lastTick = time.Now()
+
+		return
+	}
+
+	frameDuration := time.Second / time.Duration(s.fps())
+
+	// lastTick isn't encoded, so it is zero after the state is restored; the player also
+	// mustn't catch up on frames missed while the widget wasn't built
+	if s.lastTick.IsZero() || time.Since(s.lastTick) > time.Duration(numFrames)*frameDuration {
+		s.lastTick = time.Now()
+
+		return
+	}
+
+	for s.IsPlaying && time.Since(s.lastTick) >= frameDuration {
+		s.lastTick = s.lastTick.Add(frameDuration)
+		s.nextFrame(numFrames)
+	}
+}
+
+func (s *State) nextFrame(numFrames int) {
+	lastFrame := int32(numFrames - 1)
+	isLastFrame := s.Frame == lastFrame
+
+	// update play direction
+	switch s.PlayMode {
+	case PlayModeForward:
+		s.isForward = true
+	case PlayModeBackward:
+		s.isForward = false
+	case PlayModePingPong:
+		if isLastFrame || s.Frame == 0 {
+			s.isForward = !s.isForward
+		}
+	}
+
+	// now update the frame number
+	if s.isForward {
+		s.Frame++
+	} else {
+		s.Frame--
+	}
+
+	s.Frame = int32(hsutil.Wrap(int(s.Frame), numFrames))
+
+	// next, check for stopping/repeat
+	isStoppingFrame := (s.Frame == 0) || (s.Frame == lastFrame)
+
+	if isStoppingFrame && !s.Repeat {
+		s.IsPlaying = false
+	}
+}
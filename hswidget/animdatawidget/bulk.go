@@ -0,0 +1,51 @@
+package animdatawidget
+
+import (
+	"fmt"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsanimdata"
+)
+
+const (
+	// speedBaseFPS and speedDivisor are used to calculate FPS of animation (speedBaseFPS * speed / speedDivisor)
+	speedBaseFPS = 25
+	speedDivisor = 256
+	maxSpeed     = 0xffff
+)
+
+func (p *widget) buildBulkSpeedLayout() {
+	state := p.getState()
+
+	giu.Layout{
+		giu.Label("Names pattern (e.g. AA*, ??A1HS):"),
+		giu.InputText("##"+p.id+"bulkPattern", &state.Pattern).Size(listW),
+		giu.Row(
+			giu.Label("Speed: "),
+			giu.InputInt("##"+p.id+"bulkSpeed", &state.Speed).Size(inputIntW),
+			giu.Label(fmt.Sprintf("(%v FPS)", float64(speedBaseFPS*state.Speed)/speedDivisor)),
+		),
+		giu.Label(state.bulkMessage),
+		giu.Separator(),
+		giu.Button("Apply##"+p.id+"bulkApply").Size(actionBtnW, actionBtnH).OnClick(func() {
+			if state.Speed < 0 || state.Speed > maxSpeed {
+				state.bulkMessage = fmt.Sprintf("speed should be between 0 and %d", maxSpeed)
+
+				return
+			}
+
+			count, err := hsanimdata.SetSpeed(p.d2, state.Pattern, uint16(state.Speed))
+			if err != nil {
+				state.bulkMessage = err.Error()
+
+				return
+			}
+
+			state.bulkMessage = fmt.Sprintf("speed of %d record(s) changed", count)
+		}),
+		giu.Button("Back##"+p.id+"bulkBack").Size(actionBtnW, actionBtnH).OnClick(func() {
+			state.Mode = widgetModeList
+		}),
+	}.Build()
+}
@@ -3,10 +3,13 @@ package animdatawidget
 import (
 	"fmt"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/ianling/giu"
 
 	"github.com/OpenDiablo2/HellSpawner/hsassets"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hscof"
 )
 
 type widgetMode int32
@@ -14,6 +17,7 @@ type widgetMode int32
 const (
 	widgetModeList widgetMode = iota
 	widgetModeViewRecord
+	widgetModeBulkSpeed
 )
 
 type widgetState struct {
@@ -23,6 +27,8 @@ type widgetState struct {
 	RecordIdx  int32
 	deleteIcon *giu.Texture
 	addEntryState
+	bulkSpeedState
+	timelineState
 }
 
 // Dispose clears widget's state
@@ -32,6 +38,8 @@ func (s *widgetState) Dispose() {
 	s.MapIndex = 0
 	s.RecordIdx = 0
 	s.addEntryState.Dispose()
+	s.bulkSpeedState.Dispose()
+	s.timelineState.Dispose()
 	s.deleteIcon = nil
 }
 
@@ -43,6 +51,48 @@ func (s *addEntryState) Dispose() {
 	s.Name = ""
 }
 
+type bulkSpeedState struct {
+	Pattern     string
+	Speed       int32
+	bulkMessage string
+}
+
+func (s *bulkSpeedState) Dispose() {
+	s.Pattern = ""
+	s.Speed = 0
+	s.bulkMessage = ""
+}
+
+type timelineState struct {
+	Direction int32
+	IsPlaying bool
+	frame     int32
+	lastTick  time.Time
+
+	// composite is loaded and its textures are created in background,
+	// so fields below are guarded by previewMutex
+	previewMutex sync.Mutex
+	// previewKey identifies the loaded composite (name and direction)
+	previewKey string
+	preview    *hscof.Composite
+	previewErr string
+	loading    bool
+	textures   []*giu.Texture
+}
+
+func (s *timelineState) Dispose() {
+	s.IsPlaying = false
+	s.frame = 0
+
+	s.previewMutex.Lock()
+	defer s.previewMutex.Unlock()
+
+	s.previewKey = ""
+	s.preview = nil
+	s.previewErr = ""
+	s.textures = nil
+}
+
 func (p *widget) getStateID() string {
 	return fmt.Sprintf("widget_%s", p.id)
 }
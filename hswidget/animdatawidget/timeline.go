@@ -0,0 +1,326 @@
+package animdatawidget
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2animdata"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsanimdata"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hscof"
+	"github.com/OpenDiablo2/HellSpawner/hswidget"
+)
+
+const (
+	frameCellW, frameCellH = 28, 36
+	eventButtonW           = 70
+	timelineH              = 60
+	previewScale           = 2
+	maxDirection           = 31
+	// eventPayload is a type of drag and drop payload: event and frame it is dragged from
+	eventPayload = "ANIMDATA_EVENT"
+	// noFrame means, that event is dragged from events palette
+	noFrame          = 0xff
+	rightMouseButton = 1
+)
+
+// CompositeLoader composes a direction of animation (COF and its layers) of name given
+type CompositeLoader func(name string, direction int) (*hscof.Composite, error)
+
+func eventColors() map[d2animdata.AnimationEvent]color.RGBA {
+	return map[d2animdata.AnimationEvent]color.RGBA{
+		d2animdata.AnimationEventAttack:  {R: 0xb0, G: 0x30, B: 0x30, A: 0xff},
+		d2animdata.AnimationEventMissile: {R: 0x30, G: 0x80, B: 0xb0, A: 0xff},
+		d2animdata.AnimationEventSound:   {R: 0x30, G: 0xa0, B: 0x50, A: 0xff},
+		d2animdata.AnimationEventSkill:   {R: 0xa0, G: 0x40, B: 0xb0, A: 0xff},
+	}
+}
+
+func eventShortNames() map[d2animdata.AnimationEvent]string {
+	return map[d2animdata.AnimationEvent]string{
+		d2animdata.AnimationEventNone:    "",
+		d2animdata.AnimationEventAttack:  "ATK",
+		d2animdata.AnimationEventMissile: "MIS",
+		d2animdata.AnimationEventSound:   "SND",
+		d2animdata.AnimationEventSkill:   "SKL",
+	}
+}
+
+func currentFrameColor() color.RGBA {
+	return color.RGBA{R: 0xd0, G: 0xa0, B: 0x20, A: 0xff}
+}
+
+func toVec4(c color.RGBA) imgui.Vec4 {
+	const maxColor = 255
+
+	return imgui.Vec4{X: float32(c.R) / maxColor, Y: float32(c.G) / maxColor, Z: float32(c.B) / maxColor, W: float32(c.A) / maxColor}
+}
+
+// frameCount returns number of frames shown on timeline
+func frameCount(record *d2animdata.AnimationDataRecord) int {
+	fpd := record.FramesPerDirection()
+	if fpd > hsanimdata.NumEvents {
+		fpd = hsanimdata.NumEvents
+	}
+
+	return fpd
+}
+
+func (p *widget) setEvent(name string, recordIdx, frame int, event d2animdata.AnimationEvent) {
+	if err := hsanimdata.SetEvent(p.d2, name, recordIdx, frame, event); err != nil {
+		log.Print(err)
+	}
+}
+
+// updatePlayer moves the current frame according to record's speed
+func (p *widget) updatePlayer(record *d2animdata.AnimationDataRecord) {
+	state := p.getState()
+	ts := &state.timelineState
+	fpd := frameCount(record)
+
+	if !ts.IsPlaying || fpd == 0 || record.FPS() <= 0 {
+		ts.lastTick = time.Now()
+
+		return
+	}
+
+	frameDuration := time.Duration(record.FrameDurationMS() * float64(time.Millisecond))
+
+	for time.Since(ts.lastTick) >= frameDuration {
+		ts.lastTick = ts.lastTick.Add(frameDuration)
+		ts.frame = (ts.frame + 1) % int32(fpd)
+	}
+}
+
+// updatePreview (re)loads composite animation of record, if it isn't loaded yet
+func (p *widget) updatePreview(name string) {
+	ts := &p.getState().timelineState
+
+	ts.previewMutex.Lock()
+	defer ts.previewMutex.Unlock()
+
+	key := fmt.Sprintf("%s_%d", name, ts.Direction)
+	if p.loadComposite == nil || ts.previewKey == key || ts.loading {
+		return
+	}
+
+	ts.previewKey = key
+	ts.loading = true
+	ts.preview, ts.textures, ts.previewErr = nil, nil, ""
+
+	go p.loadPreview(ts, name, int(ts.Direction), key)
+}
+
+// loadPreview loads composite animation in background; it is dropped, if another one
+// was requested (or the state was disposed) in the meantime
+func (p *widget) loadPreview(ts *timelineState, name string, direction int, key string) {
+	composite, err := p.loadComposite(name, direction)
+
+	ts.previewMutex.Lock()
+	defer ts.previewMutex.Unlock()
+
+	if ts.previewKey != key {
+		return
+	}
+
+	ts.loading = false
+
+	if err != nil {
+		ts.previewErr = err.Error()
+
+		return
+	}
+
+	textures := make([]*giu.Texture, len(composite.Frames))
+
+	for idx := range composite.Frames {
+		idx := idx
+		p.textureLoader.CreateTextureFromARGB(composite.Frames[idx], func(t *giu.Texture) {
+			ts.previewMutex.Lock()
+			textures[idx] = t
+			ts.previewMutex.Unlock()
+		})
+	}
+
+	ts.preview, ts.textures = composite, textures
+}
+
+func (p *widget) makePreviewLayout() giu.Layout {
+	ts := &p.getState().timelineState
+
+	ts.previewMutex.Lock()
+	defer ts.previewMutex.Unlock()
+
+	switch {
+	case p.loadComposite == nil:
+		return giu.Layout{}
+	case ts.loading:
+		return giu.Layout{giu.Label("Loading animation...")}
+	case ts.previewErr != "":
+		return giu.Layout{giu.Label("No preview: " + ts.previewErr)}
+	case ts.preview == nil || len(ts.textures) == 0:
+		return giu.Layout{}
+	}
+
+	frame := int(ts.frame) % len(ts.textures)
+	img := ts.preview.Frames[frame]
+	texture := ts.textures[frame]
+
+	w, h := float32(img.Bounds().Dx()*previewScale), float32(img.Bounds().Dy()*previewScale)
+
+	layout := giu.Layout{
+		giu.Row(
+			giu.Label("Direction: "),
+			giu.SliderInt("##"+p.id+"previewDirection", &ts.Direction, 0, maxDirection).Size(actionBtnW),
+		),
+		giu.Image(texture).Size(w, h),
+	}
+
+	if len(ts.preview.Missing) > 0 {
+		layout = append(layout, giu.Label(fmt.Sprintf("Layers without graphics: %v", ts.preview.Missing)))
+	}
+
+	if len(ts.textures) != int(ts.preview.COF.FramesPerDirection) || frame != int(ts.frame) {
+		layout = append(layout, giu.Label(fmt.Sprintf("COF has %d frames per direction", ts.preview.COF.FramesPerDirection)))
+	}
+
+	return layout
+}
+
+func (p *widget) makeEventPaletteLayout() giu.Layout {
+	events := []d2animdata.AnimationEvent{
+		d2animdata.AnimationEventAttack,
+		d2animdata.AnimationEventMissile,
+		d2animdata.AnimationEventSound,
+		d2animdata.AnimationEventSkill,
+		d2animdata.AnimationEventNone,
+	}
+
+	return giu.Layout{
+		giu.Custom(func() {
+			colors := eventColors()
+
+			for idx, event := range events {
+				if idx > 0 {
+					imgui.SameLine()
+				}
+
+				label := hsanimdata.EventName(event)
+				if event == d2animdata.AnimationEventNone {
+					label = "clear"
+				}
+
+				c, found := colors[event]
+				if found {
+					imgui.PushStyleColor(imgui.StyleColorButton, toVec4(c))
+				}
+
+				imgui.ButtonV(label+"##"+p.id+"eventSource"+label, imgui.Vec2{X: eventButtonW})
+
+				if found {
+					imgui.PopStyleColor()
+				}
+
+				if imgui.BeginDragDropSource(imgui.DragDropFlagsNone) {
+					imgui.SetDragDropPayload(eventPayload, []byte{byte(event), noFrame}, imgui.ConditionNone)
+					imgui.Text(label)
+					imgui.EndDragDropSource()
+				}
+			}
+		}),
+	}
+}
+
+func (p *widget) makeTimelineLayout(name string, recordIdx int, record *d2animdata.AnimationDataRecord) giu.Layout {
+	ts := &p.getState().timelineState
+
+	p.updatePlayer(record)
+	p.updatePreview(name)
+
+	fpd := frameCount(record)
+	if fpd == 0 {
+		return giu.Layout{giu.Label("Record has no frames")}
+	}
+
+	if int(ts.frame) >= fpd {
+		ts.frame = 0
+	}
+
+	timeline := giu.Custom(func() {
+		colors := eventColors()
+		shortNames := eventShortNames()
+
+		for frame := 0; frame < fpd; frame++ {
+			if frame > 0 {
+				imgui.SameLine()
+			}
+
+			event := record.Event(frame)
+
+			pushed := 0
+
+			if c, found := colors[event]; found {
+				imgui.PushStyleColor(imgui.StyleColorButton, toVec4(c))
+				pushed++
+			}
+
+			if frame == int(ts.frame) {
+				imgui.PushStyleColor(imgui.StyleColorBorder, toVec4(currentFrameColor()))
+				imgui.PushStyleColor(imgui.StyleColorText, toVec4(currentFrameColor()))
+				pushed += 2
+			}
+
+			label := fmt.Sprintf("%d\n%s##%sframe%d", frame, shortNames[event], p.id, frame)
+			if imgui.ButtonV(label, imgui.Vec2{X: frameCellW, Y: frameCellH}) {
+				ts.frame = int32(frame)
+				ts.IsPlaying = false
+			}
+
+			imgui.PopStyleColorV(pushed)
+
+			if imgui.IsItemHovered() && imgui.IsMouseClicked(rightMouseButton) {
+				p.setEvent(name, recordIdx, frame, d2animdata.AnimationEventNone)
+			}
+
+			if imgui.IsItemHovered() && event != d2animdata.AnimationEventNone {
+				imgui.SetTooltip(fmt.Sprintf("%s on frame %d (right click to remove)", hsanimdata.EventName(event), frame))
+			}
+
+			if event != d2animdata.AnimationEventNone && imgui.BeginDragDropSource(imgui.DragDropFlagsNone) {
+				imgui.SetDragDropPayload(eventPayload, []byte{byte(event), byte(frame)}, imgui.ConditionNone)
+				imgui.Text(hsanimdata.EventName(event))
+				imgui.EndDragDropSource()
+			}
+
+			if imgui.BeginDragDropTarget() {
+				if payload := imgui.AcceptDragDropPayload(eventPayload, imgui.DragDropFlagsNone); len(payload) == 2 {
+					if payload[1] != noFrame && int(payload[1]) != frame {
+						p.setEvent(name, recordIdx, int(payload[1]), d2animdata.AnimationEventNone)
+					}
+
+					p.setEvent(name, recordIdx, frame, d2animdata.AnimationEvent(payload[0]))
+				}
+
+				imgui.EndDragDropTarget()
+			}
+		}
+	})
+
+	return giu.Layout{
+		giu.Label("Events (drag onto a frame; drag a frame's event to move it, right click to remove it):"),
+		p.makeEventPaletteLayout(),
+		giu.Child("##"+p.id+"timeline").Border(true).Size(listW*2, timelineH).
+			Flags(giu.WindowFlagsHorizontalScrollbar).Layout(giu.Layout{timeline}),
+		giu.Row(
+			hswidget.PlayPauseButton("##"+p.id+"timelinePlayPause", &ts.IsPlaying, p.textureLoader).
+				Size(playPauseButtonSize, playPauseButtonSize),
+			giu.Label(fmt.Sprintf("Frame %d / %d", ts.frame, fpd-1)),
+		),
+		p.makePreviewLayout(),
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/OpenDiablo2/dialog"
 	"github.com/ianling/giu"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2animdata"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsanimdata"
 	"github.com/OpenDiablo2/HellSpawner/hswidget"
 )
 
@@ -20,20 +22,24 @@ const (
 	inputIntW                            = 30
 	actionBtnW, actionBtnH               = 200, 30
 	saveCancelButtonW, saveCancelButtonH = 50, 30
+	playPauseButtonSize                  = 15
 )
 
 type widget struct {
 	id            string
 	d2            *d2animdata.AnimationData
 	textureLoader hscommon.TextureLoader
+	loadComposite CompositeLoader
 }
 
-// Create creates a new widget
-func Create(textureLoader hscommon.TextureLoader, state []byte, id string, d2 *d2animdata.AnimationData) giu.Widget {
+// Create creates a new widget; loadComposite (optional) is used to preview animations of records
+func Create(textureLoader hscommon.TextureLoader, state []byte, id string, d2 *d2animdata.AnimationData,
+	loadComposite CompositeLoader) giu.Widget {
 	result := &widget{
 		id:            id,
 		d2:            d2,
 		textureLoader: textureLoader,
+		loadComposite: loadComposite,
 	}
 
 	if state != nil && giu.Context.GetState(result.getStateID()) == nil {
@@ -57,12 +63,19 @@ func (p *widget) Build() {
 		p.buildAnimationsList()
 	case widgetModeViewRecord:
 		p.buildViewRecordLayout()
+	case widgetModeBulkSpeed:
+		p.buildBulkSpeedLayout()
 	}
 }
 
 func (p *widget) buildAnimationsList() {
 	state := p.getState()
 
+	// entries could be added by importing a file
+	if len(state.mapKeys) != p.d2.GetRecordsCount() {
+		p.reloadMapKeys()
+	}
+
 	keys := make([]string, 0)
 
 	if state.Name != "" {
@@ -113,6 +126,11 @@ func (p *widget) buildAnimationsList() {
 					giu.Label("Nothing matches...").Build()
 				}),
 			}),
+		giu.Separator(),
+		giu.Button("Set speed of matching records...##"+p.id+"bulkSpeed").Size(actionBtnW, actionBtnH).OnClick(func() {
+			state.bulkMessage = ""
+			state.Mode = widgetModeBulkSpeed
+		}),
 	}.Build()
 }
 
@@ -170,10 +188,18 @@ func (p *widget) buildViewRecordLayout() {
 		giu.Label(fmt.Sprintf("FPS: %v", record.FPS())),
 		giu.Label(fmt.Sprintf("Frame duration: %v (miliseconds)", record.FrameDurationMS())),
 		giu.Separator(),
+		p.makeTimelineLayout(name, int(state.RecordIdx), record),
+		giu.Separator(),
 		giu.Button("Back to entry preview##"+p.id+"backToRecordSelection").Size(actionBtnW, actionBtnH).OnClick(func() {
 			state.Mode = widgetModeList
 		}),
 		giu.Button("Add record##"+p.id+"addRecordBtn").Size(actionBtnW, actionBtnH).OnClick(func() {
+			if err := hsanimdata.CanAddRecord(p.d2, name); err != nil {
+				dialog.Message("%v", err).Error()
+
+				return
+			}
+
 			p.d2.PushRecord(name)
 
 			// no -1, because current records hasn't new field yet
@@ -231,9 +257,16 @@ func (p *widget) makeSearchLayout() giu.Layout {
 
 			giu.Row(
 				giu.Button("Add##"+p.id+"addEntry").Size(saveCancelButtonW, saveCancelButtonH).OnClick(func() {
-					err := p.d2.AddEntry(state.Name)
-					if err != nil {
-						log.Print(err)
+					if err := hsanimdata.CanAddRecord(p.d2, state.Name); err != nil {
+						dialog.Message("%v", err).Error()
+
+						return
+					}
+
+					if err := p.d2.AddEntry(state.Name); err != nil {
+						dialog.Message("%v", err).Error()
+
+						return
 					}
 
 					p.d2.PushRecord(state.Name)
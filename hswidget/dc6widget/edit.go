@@ -0,0 +1,171 @@
+package dc6widget
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/png" // register png decoder
+	"os"
+	"path/filepath"
+
+	"github.com/OpenDiablo2/dialog"
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdc6"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+)
+
+const (
+	editButtonW = 90
+	offsetW     = 60
+)
+
+// edit applies an edit to the sprite and reloads its frames
+func (p *widget) edit(state *widgetState, fn func() error) {
+	if err := fn(); err != nil {
+		state.editMessage = err.Error()
+
+		return
+	}
+
+	state.editMessage = ""
+
+	if d := int32(p.dc6.Directions) - 1; state.Controls.Direction > d {
+		state.Controls.Direction = d
+	}
+
+	if f := int32(p.dc6.FramesPerDirection) - 1; state.Player.Frame > f {
+		state.Player.Frame = f
+	}
+
+	state.Width, state.Height = int32(p.dc6.FramesPerDirection), 1
+
+	p.loadFrames(state)
+}
+
+// currentFrame returns index of the current frame (in sprite's frames list)
+func (p *widget) currentFrame(state *widgetState) int {
+	return int(state.Controls.Direction)*int(p.dc6.FramesPerDirection) + int(state.Player.Frame)
+}
+
+// moveAnchor moves anchor point of the current frame; frame's offset changes in the opposite direction
+func (p *widget) moveAnchor(delta image.Point) {
+	state := p.getState()
+	frame := p.dc6.Frames[p.currentFrame(state)]
+
+	frame.OffsetX -= int32(delta.X)
+	frame.OffsetY -= int32(delta.Y)
+}
+
+func (p *widget) makeEditLayout(state *widgetState) giu.Layout {
+	dir := int(state.Controls.Direction)
+	frameIdx := int(state.Player.Frame)
+	frame := p.dc6.Frames[p.currentFrame(state)]
+
+	offsetX, offsetY := frame.OffsetX, frame.OffsetY
+
+	button := func(label, id string, fn func() error) giu.Widget {
+		return giu.Button(label+"##"+p.id+id).Size(editButtonW, 0).OnClick(func() {
+			p.edit(state, fn)
+		})
+	}
+
+	return giu.Layout{
+		giu.Label(fmt.Sprintf("Frame %d (in all directions):", frameIdx)),
+		giu.Row(
+			button("Insert before", "insertFrame", func() error { return hsdc6.InsertFrame(p.dc6, frameIdx) }),
+			button("Duplicate", "duplicateFrame", func() error { return hsdc6.DuplicateFrame(p.dc6, frameIdx) }),
+			button("Delete", "deleteFrame", func() error { return hsdc6.DeleteFrame(p.dc6, frameIdx) }),
+			button("Move left", "moveFrameLeft", func() error {
+				if err := hsdc6.MoveFrame(p.dc6, frameIdx, frameIdx-1); err != nil {
+					return err
+				}
+
+				state.Player.Frame--
+
+				return nil
+			}),
+			button("Move right", "moveFrameRight", func() error {
+				if err := hsdc6.MoveFrame(p.dc6, frameIdx, frameIdx+1); err != nil {
+					return err
+				}
+
+				state.Player.Frame++
+
+				return nil
+			}),
+		),
+		giu.Row(
+			giu.Label(fmt.Sprintf("Frame %d of direction %d: offset X", frameIdx, dir)),
+			giu.InputInt("##"+p.id+"offsetX", &offsetX).Size(offsetW).OnChange(func() {
+				frame.OffsetX = offsetX
+			}),
+			giu.Label("Y"),
+			giu.InputInt("##"+p.id+"offsetY", &offsetY).Size(offsetW).OnChange(func() {
+				frame.OffsetY = offsetY
+			}),
+			giu.Button("Replace from PNG...##"+p.id+"replaceFrame").OnClick(func() {
+				p.edit(state, func() error { return p.replaceFrame(dir, frameIdx) })
+			}),
+		),
+		giu.Label(fmt.Sprintf("Direction %d:", dir)),
+		giu.Row(
+			button("Insert before", "insertDirection", func() error { return hsdc6.InsertDirection(p.dc6, dir) }),
+			button("Duplicate", "duplicateDirection", func() error { return hsdc6.DuplicateDirection(p.dc6, dir) }),
+			button("Delete", "deleteDirection", func() error { return hsdc6.DeleteDirection(p.dc6, dir) }),
+			button("Move up", "moveDirectionUp", func() error {
+				if err := hsdc6.MoveDirection(p.dc6, dir, dir-1); err != nil {
+					return err
+				}
+
+				state.Controls.Direction--
+
+				return nil
+			}),
+			button("Move down", "moveDirectionDown", func() error {
+				if err := hsdc6.MoveDirection(p.dc6, dir, dir+1); err != nil {
+					return err
+				}
+
+				state.Controls.Direction++
+
+				return nil
+			}),
+		),
+		giu.Label(state.editMessage),
+	}
+}
+
+// replaceFrame replaces frame with a png image (colors are mapped to the nearest colors of palette)
+func (p *widget) replaceFrame(dir, frameIdx int) error {
+	if p.palette == nil {
+		return errors.New("select a palette first (DC6 Editor > Change Palette)")
+	}
+
+	filePath, err := dialog.File().Title("Select a frame image").Filter("PNG image", "png").Load()
+	if err != nil || filePath == "" {
+		return nil
+	}
+
+	f, err := os.Open(filepath.Clean(filePath))
+	if err != nil {
+		return fmt.Errorf("error opening image: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("error decoding image: %w", err)
+	}
+
+	q := hsdc6.NewQuantizer(hspalette.Colors(p.palette))
+
+	if err := hsdc6.ReplaceFrame(p.dc6, dir, frameIdx, hsdc6.FrameFromImage(img, q)); err != nil {
+		return fmt.Errorf("error replacing frame: %w", err)
+	}
+
+	return nil
+}
@@ -5,42 +5,15 @@ import (
 	"image"
 	"image/color"
 	"log"
-	"time"
 
 	"github.com/ianling/giu"
 	gim "github.com/ozankasikci/go-image-merge"
 
-	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
-)
-
-const (
-	miliseconds     = 1000
-	defaultTickTime = 100
-)
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
-type animationPlayMode byte
-
-const (
-	playModeForward animationPlayMode = iota
-	playModeBackword
-	playModePingPong
+	"github.com/OpenDiablo2/HellSpawner/hswidget/animationplayer"
 )
 
-func (a animationPlayMode) String() string {
-	s := map[animationPlayMode]string{
-		playModeForward:  "Forwards",
-		playModeBackword: "Backwords",
-		playModePingPong: "Ping-Pong",
-	}
-
-	k, ok := s[a]
-	if !ok {
-		return "Unknown"
-	}
-
-	return k
-}
-
 type widgetMode int32
 
 const (
@@ -54,17 +27,16 @@ type widgetState struct {
 	tiledState
 	Mode widgetMode
 
-	IsPlaying bool
-	Repeat    bool
-	TickTime  int32
-	PlayMode  animationPlayMode
+	Player animationplayer.State
 
 	// cache - will not be saved
 	rgb      []*image.RGBA
 	textures []*giu.Texture
-
-	IsForward bool
-	ticker    *time.Ticker
+	// framesVersion is increased each time frames are reloaded (after editing)
+	framesVersion int
+	// palette, which textures were created with
+	palette     *[256]d2interface.Color
+	editMessage string
 }
 
 func (w *widgetState) Dispose() {
@@ -76,8 +48,6 @@ func (w *widgetState) Dispose() {
 type viewerState struct {
 	Controls struct {
 		Direction int32
-		Frame     int32
-		Scale     int32
 	}
 
 	lastFrame          int32
@@ -136,18 +106,22 @@ func (p *widget) initState() {
 			Height: 1,
 		},
 
-		IsPlaying: false,
-		Repeat:    false,
-		TickTime:  defaultTickTime,
-		PlayMode:  playModeForward,
+		Player: animationplayer.NewState(),
 	}
 
-	newState.ticker = time.NewTicker(time.Second * time.Duration(newState.TickTime) / miliseconds)
+	p.setState(newState)
 
-	go p.runPlayer(newState)
+	p.loadFrames(newState)
+}
 
+// loadFrames decodes frames of sprite and creates their textures
+func (p *widget) loadFrames(newState *widgetState) {
 	totalFrames := int(p.dc6.Directions * p.dc6.FramesPerDirection)
 	newState.rgb = make([]*image.RGBA, totalFrames)
+	newState.textures = nil
+	newState.framesVersion++
+	newState.palette = p.palette
+	version := newState.framesVersion
 
 	for frameIndex := 0; frameIndex < int(p.dc6.Directions*p.dc6.FramesPerDirection); frameIndex++ {
 		newState.rgb[frameIndex] = image.NewRGBA(image.Rect(0, 0, int(p.dc6.Frames[frameIndex].Width), int(p.dc6.Frames[frameIndex].Height)))
@@ -186,8 +160,6 @@ func (p *widget) initState() {
 		}
 	}
 
-	p.setState(newState)
-
 	go func() {
 		textures := make([]*giu.Texture, totalFrames)
 
@@ -198,9 +170,10 @@ func (p *widget) initState() {
 			})
 		}
 
-		s := p.getState()
-		s.textures = textures
-		p.setState(s)
+		// frames could be edited (and reloaded) in the meantime
+		if newState.framesVersion == version {
+			newState.textures = textures
+		}
 	}()
 }
 
@@ -208,45 +181,6 @@ func (p *widget) setState(s giu.Disposable) {
 	giu.Context.SetState(p.getStateID(), s)
 }
 
-func (p *widget) runPlayer(state *widgetState) {
-	for range state.ticker.C {
-		if !state.IsPlaying {
-			continue
-		}
-
-		numFrames := int32(p.dc6.FramesPerDirection - 1)
-		isLastFrame := state.Controls.Frame == numFrames
-
-		// update play direction
-		switch state.PlayMode {
-		case playModeForward:
-			state.IsForward = true
-		case playModeBackword:
-			state.IsForward = false
-		case playModePingPong:
-			if isLastFrame || state.Controls.Frame == 0 {
-				state.IsForward = !state.IsForward
-			}
-		}
-
-		// now update the frame number
-		if state.IsForward {
-			state.Controls.Frame++
-		} else {
-			state.Controls.Frame--
-		}
-
-		state.Controls.Frame = int32(hsutil.Wrap(int(state.Controls.Frame), int(p.dc6.FramesPerDirection)))
-
-		// next, check for stopping/repeat
-		isStoppingFrame := (state.Controls.Frame == 0) || (state.Controls.Frame == numFrames)
-
-		if isStoppingFrame && !state.Repeat {
-			state.IsPlaying = false
-		}
-	}
-}
-
 func (p *widget) recalculateTiledViewWidth(state *widgetState) {
 	// the area of our rectangle must be less or equal than FramesPerDirection
 	state.Width = int32(p.dc6.FramesPerDirection) / state.Height
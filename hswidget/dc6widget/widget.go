@@ -3,8 +3,8 @@ package dc6widget
 import (
 	"encoding/json"
 	"fmt"
+	"image"
 	"log"
-	"time"
 
 	"github.com/OpenDiablo2/dialog"
 	"github.com/ianling/giu"
@@ -15,14 +15,12 @@ import (
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
-	"github.com/OpenDiablo2/HellSpawner/hswidget"
+	"github.com/OpenDiablo2/HellSpawner/hswidget/animationplayer"
 )
 
 const (
-	comboW              = 125
-	inputIntW           = 30
-	playPauseButtonSize = 15
-	buttonW, buttonH    = 200, 30
+	inputIntW        = 30
+	buttonW, buttonH = 200, 30
 )
 
 const (
@@ -53,8 +51,6 @@ func Create(state []byte, palette *[256]d2interface.Color, textureLoader hscommo
 			log.Printf("error decoding dc6 widget state: %v", err)
 		}
 
-		s.ticker.Reset(time.Second * time.Duration(s.TickTime) / miliseconds)
-
 		if s.Mode == dc6WidgetTiledView {
 			result.createImage(s)
 		}
@@ -69,6 +65,11 @@ func Create(state []byte, palette *[256]d2interface.Color, textureLoader hscommo
 func (p *widget) Build() {
 	state := p.getState()
 
+	// palette changed (e.g. sprite was remapped to another one)
+	if state.palette != p.palette {
+		p.loadFrames(state)
+	}
+
 	switch state.Mode {
 	case dc6WidgetViewer:
 		p.makeViewerLayout().Build()
@@ -80,32 +81,11 @@ func (p *widget) Build() {
 func (p *widget) makeViewerLayout() giu.Layout {
 	viewerState := p.getState()
 
-	imageScale := uint32(viewerState.Controls.Scale)
-	curFrameIndex := int(viewerState.Controls.Frame) + (int(viewerState.Controls.Direction) * int(p.dc6.FramesPerDirection))
-	dirIdx := int(viewerState.Controls.Direction)
-
-	textureIdx := dirIdx*int(p.dc6.FramesPerDirection) + int(viewerState.Controls.Frame)
-
-	if imageScale < 1 {
-		imageScale = 1
-	}
-
 	err := giu.Context.GetRenderer().SetTextureMagFilter(giu.TextureFilterNearest)
 	if err != nil {
 		log.Print(err)
 	}
 
-	w := float32(p.dc6.Frames[curFrameIndex].Width * imageScale)
-	h := float32(p.dc6.Frames[curFrameIndex].Height * imageScale)
-
-	var widget *giu.ImageWidget
-	if viewerState.textures == nil || len(viewerState.textures) <= int(viewerState.Controls.Frame) ||
-		viewerState.textures[curFrameIndex] == nil {
-		widget = giu.Image(nil).Size(w, h)
-	} else {
-		widget = giu.Image(viewerState.textures[textureIdx]).Size(w, h)
-	}
-
 	return giu.Layout{
 		giu.Label(fmt.Sprintf(
 			"Version: %v\t Flags: %b\t Encoding: %v\t",
@@ -115,25 +95,21 @@ func (p *widget) makeViewerLayout() giu.Layout {
 		)),
 		giu.Label(fmt.Sprintf("Directions: %v\tFrames per Direction: %v", p.dc6.Directions, p.dc6.FramesPerDirection)),
 		giu.Custom(func() {
-			imgui.BeginGroup()
 			if p.dc6.Directions > 1 {
 				imgui.SliderInt("Direction", &viewerState.Controls.Direction, 0, int32(p.dc6.Directions-1))
 			}
-
-			if p.dc6.FramesPerDirection > 1 {
-				imgui.SliderInt("Frames", &viewerState.Controls.Frame, 0, int32(p.dc6.FramesPerDirection-1))
-			}
-
-			const minScale, maxScale = 1, 8
-
-			imgui.SliderInt("Scale", &viewerState.Controls.Scale, minScale, maxScale)
-
-			imgui.EndGroup()
 		}),
 		giu.Separator(),
-		p.makePlayerLayout(viewerState),
+		animationplayer.Create(p.textureLoader, p.id+"player", &viewerState.Player, p.playerFrames(viewerState)).
+			OnAnchorDragged(p.moveAnchor),
+		giu.Button("Export GIF##" + p.id + "exportGif").OnClick(func() {
+			err := p.exportGif(viewerState)
+			if err != nil {
+				dialog.Message(err.Error()).Error()
+			}
+		}),
 		giu.Separator(),
-		widget,
+		p.makeEditLayout(viewerState),
 		giu.Separator(),
 		giu.Button("Tiled View##"+p.id+"tiledViewButton").Size(buttonW, buttonH).OnClick(func() {
 			viewerState.Mode = dc6WidgetTiledView
@@ -142,33 +118,24 @@ func (p *widget) makeViewerLayout() giu.Layout {
 	}
 }
 
-func (p *widget) makePlayerLayout(state *widgetState) giu.Layout {
-	playModeList := make([]string, 0)
-	for i := playModeForward; i <= playModePingPong; i++ {
-		playModeList = append(playModeList, i.String())
-	}
+// playerFrames returns frames of the current direction; DC6 frame's offset is its bottom left corner
+func (p *widget) playerFrames(state *widgetState) []animationplayer.Frame {
+	fpd := int(p.dc6.FramesPerDirection)
+	firstFrame := int(state.Controls.Direction) * fpd
+	result := make([]animationplayer.Frame, fpd)
 
-	pm := int32(state.PlayMode)
+	for idx := range result {
+		frame := p.dc6.Frames[firstFrame+idx]
+		left, bottom := int(frame.OffsetX), int(frame.OffsetY)
 
-	return giu.Layout{
-		giu.Row(
-			giu.Checkbox("Loop##"+p.id+"PlayRepeat", &state.Repeat),
-			giu.Combo("##"+p.id+"PlayModeList", playModeList[state.PlayMode], playModeList, &pm).OnChange(func() {
-				state.PlayMode = animationPlayMode(pm)
-			}).Size(comboW),
-			giu.InputInt("Tick time##"+p.id+"PlayTickTime", &state.TickTime).Size(inputIntW).OnChange(func() {
-				state.ticker.Reset(time.Second * time.Duration(state.TickTime) / miliseconds)
-			}),
-			hswidget.PlayPauseButton("##"+p.id+"PlayPauseAnimation", &state.IsPlaying, p.textureLoader).
-				Size(playPauseButtonSize, playPauseButtonSize),
-			giu.Button("Export GIF##"+p.id+"exportGif").OnClick(func() {
-				err := p.exportGif(state)
-				if err != nil {
-					dialog.Message(err.Error()).Error()
-				}
-			}),
-		),
+		result[idx].Rect = image.Rect(left, bottom-int(frame.Height), left+int(frame.Width), bottom)
+
+		if firstFrame+idx < len(state.textures) {
+			result[idx].Texture = state.textures[firstFrame+idx]
+		}
 	}
+
+	return result
 }
 
 func (p *widget) makeTiledViewLayout(state *widgetState) giu.Layout {
@@ -194,7 +161,7 @@ func (p *widget) exportGif(state *widgetState) error {
 	firstFrame := state.Controls.Direction * fpd
 	images := state.rgb[firstFrame : firstFrame+fpd]
 
-	err := hsutil.ExportToGif(images, state.TickTime)
+	err := hsutil.ExportToGif(images, state.Player.TickTime())
 	if err != nil {
 		return fmt.Errorf("error creating gif file: %w", err)
 	}
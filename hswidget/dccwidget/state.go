@@ -4,58 +4,28 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"time"
 
 	"github.com/ianling/giu"
 
-	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
-)
-
-const miliseconds = 1000
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
-type animationPlayMode byte
-
-const (
-	playModeForward animationPlayMode = iota
-	playModeBackword
-	playModePingPong
+	"github.com/OpenDiablo2/HellSpawner/hswidget/animationplayer"
 )
 
-func (a animationPlayMode) String() string {
-	s := map[animationPlayMode]string{
-		playModeForward:  "Forwards",
-		playModeBackword: "Backwords",
-		playModePingPong: "Ping-Pong",
-	}
-
-	k, ok := s[a]
-	if !ok {
-		return "Unknown"
-	}
-
-	return k
-}
-
-const defaultTickTime = 100
-
 type widgetState struct {
 	Controls struct {
 		Direction int32
-		Frame     int32
-		Scale     int32
 	}
 
-	IsPlaying bool
-	Repeat    bool
-	TickTime  int32
-	PlayMode  animationPlayMode
+	Player animationplayer.State
 
 	// cache - will not be saved
 	images   []*image.RGBA
 	textures []*giu.Texture
-
-	isForward bool // determines a direction of animation
-	ticker    *time.Ticker
+	// framesVersion is increased each time frames are reloaded
+	framesVersion int
+	// palette, which textures were created with
+	palette *[256]d2interface.Color
 }
 
 // Dispose cleans viewers state
@@ -85,20 +55,22 @@ func (p *widget) getState() *widgetState {
 func (p *widget) initState() {
 	// Prevent multiple invocation to LoadImage.
 	state := &widgetState{
-		IsPlaying: false,
-		Repeat:    false,
-		TickTime:  defaultTickTime,
-		PlayMode:  playModeForward,
+		Player: animationplayer.NewState(),
 	}
 
-	state.ticker = time.NewTicker(time.Second * time.Duration(state.TickTime) / miliseconds)
-
 	p.setState(state)
 
-	go p.runPlayer(state)
+	p.loadFrames(state)
+}
 
+// loadFrames creates images and textures of all frames
+func (p *widget) loadFrames(state *widgetState) {
 	totalFrames := p.dcc.NumberOfDirections * p.dcc.FramesPerDirection
 	state.images = make([]*image.RGBA, totalFrames)
+	state.textures = nil
+	state.framesVersion++
+	state.palette = p.palette
+	version := state.framesVersion
 
 	for dirIdx := range p.dcc.Directions {
 		fw := p.dcc.Directions[dirIdx].Box.Width
@@ -138,9 +110,10 @@ func (p *widget) initState() {
 			})
 		}
 
-		s := p.getState()
-		s.textures = textures
-		p.setState(s)
+		// frames could be reloaded in the meantime
+		if state.framesVersion == version {
+			state.textures = textures
+		}
 	}()
 }
 
@@ -173,42 +146,3 @@ func (p *widget) makeImagePixel(val byte) color.RGBA {
 
 	return RGBAColor
 }
-
-func (p *widget) runPlayer(state *widgetState) {
-	for range state.ticker.C {
-		if !state.IsPlaying {
-			continue
-		}
-
-		numFrames := int32(p.dcc.FramesPerDirection - 1)
-		isLastFrame := state.Controls.Frame == numFrames
-
-		// update play direction
-		switch state.PlayMode {
-		case playModeForward:
-			state.isForward = true
-		case playModeBackword:
-			state.isForward = false
-		case playModePingPong:
-			if isLastFrame || state.Controls.Frame == 0 {
-				state.isForward = !state.isForward
-			}
-		}
-
-		// now update the frame number
-		if state.isForward {
-			state.Controls.Frame++
-		} else {
-			state.Controls.Frame--
-		}
-
-		state.Controls.Frame = int32(hsutil.Wrap(int(state.Controls.Frame), p.dcc.FramesPerDirection))
-
-		// next, check for stopping/repeat
-		isStoppingFrame := (state.Controls.Frame == 0) || (state.Controls.Frame == numFrames)
-
-		if isStoppingFrame && !state.Repeat {
-			state.IsPlaying = false
-		}
-	}
-}
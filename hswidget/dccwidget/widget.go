@@ -3,8 +3,8 @@ package dccwidget
 import (
 	"encoding/json"
 	"fmt"
+	"image"
 	"log"
-	"time"
 
 	"github.com/OpenDiablo2/dialog"
 	"github.com/ianling/giu"
@@ -15,23 +15,13 @@ import (
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
-	"github.com/OpenDiablo2/HellSpawner/hswidget"
-)
-
-const (
-	inputIntW           = 30
-	playPauseButtonSize = 15
-	comboW              = 125
+	"github.com/OpenDiablo2/HellSpawner/hswidget/animationplayer"
 )
 
 const (
 	maxAlpha = uint8(255)
 )
 
-const (
-	imageW, imageH = 32, 32
-)
-
 type widget struct {
 	id            string
 	dcc           *d2dcc.DCC
@@ -53,9 +43,6 @@ func Create(tl hscommon.TextureLoader, state []byte, palette *[256]d2interface.C
 		if err := json.Unmarshal(state, s); err != nil {
 			log.Printf("error decoding dcc widget state: %v", err)
 		}
-
-		// update ticker
-		s.ticker.Reset(time.Second * time.Duration(s.TickTime) / miliseconds)
 		result.setState(s)
 	}
 
@@ -66,14 +53,9 @@ func Create(tl hscommon.TextureLoader, state []byte, palette *[256]d2interface.C
 func (p *widget) Build() {
 	viewerState := p.getState()
 
-	imageScale := uint32(viewerState.Controls.Scale)
-	dirIdx := int(viewerState.Controls.Direction)
-	frameIdx := viewerState.Controls.Frame
-
-	textureIdx := dirIdx*len(p.dcc.Directions[dirIdx].Frames) + int(frameIdx)
-
-	if imageScale < 1 {
-		imageScale = 1
+	// palette changed (e.g. animation was remapped to another one)
+	if viewerState.palette != p.palette {
+		p.loadFrames(viewerState)
 	}
 
 	err := giu.Context.GetRenderer().SetTextureMagFilter(giu.TextureFilterNearest)
@@ -81,17 +63,6 @@ func (p *widget) Build() {
 		log.Print(err)
 	}
 
-	var widget *giu.ImageWidget
-	if viewerState.textures == nil || len(viewerState.textures) <= int(frameIdx) || viewerState.textures[frameIdx] == nil {
-		widget = giu.Image(nil).Size(imageW, imageH)
-	} else {
-		bw := p.dcc.Directions[dirIdx].Box.Width
-		bh := p.dcc.Directions[dirIdx].Box.Height
-		w := float32(uint32(bw) * imageScale)
-		h := float32(uint32(bh) * imageScale)
-		widget = giu.Image(viewerState.textures[textureIdx]).Size(w, h)
-	}
-
 	giu.Layout{
 		giu.Row(
 			giu.Label(fmt.Sprintf("Signature: %v", p.dcc.Signature)),
@@ -102,55 +73,41 @@ func (p *widget) Build() {
 			giu.Label(fmt.Sprintf("Frames per Direction: %v", p.dcc.FramesPerDirection)),
 		),
 		giu.Custom(func() {
-			imgui.BeginGroup()
 			if p.dcc.NumberOfDirections > 1 {
 				imgui.SliderInt("Direction", &viewerState.Controls.Direction, 0, int32(p.dcc.NumberOfDirections-1))
 			}
-
-			if p.dcc.FramesPerDirection > 1 {
-				imgui.SliderInt("Frames", &viewerState.Controls.Frame, 0, int32(p.dcc.FramesPerDirection-1))
-			}
-
-			const minScale, maxScale = 1, 8
-
-			imgui.SliderInt("Scale", &viewerState.Controls.Scale, minScale, maxScale)
-
-			imgui.EndGroup()
 		}),
 		giu.Separator(),
-		p.makePlayerLayout(viewerState),
-		giu.Separator(),
-		widget,
+		animationplayer.Create(p.textureLoader, p.id+"player", &viewerState.Player, p.playerFrames(viewerState)),
+		giu.Button("Export GIF##" + p.id + "exportGif").OnClick(func() {
+			err := p.exportGif(viewerState)
+			if err != nil {
+				dialog.Message(err.Error()).Error()
+			}
+		}),
 	}.Build()
 }
 
-func (p *widget) makePlayerLayout(state *widgetState) giu.Layout {
-	playModeList := make([]string, 0)
-	for i := playModeForward; i <= playModePingPong; i++ {
-		playModeList = append(playModeList, i.String())
+// playerFrames returns frames of the current direction; frames are drawn in direction's box
+func (p *widget) playerFrames(state *widgetState) []animationplayer.Frame {
+	dirIdx := int(state.Controls.Direction)
+	direction := p.dcc.Directions[dirIdx]
+	box := direction.Box
+	rect := image.Rect(box.Left, box.Top, box.Left+box.Width, box.Top+box.Height)
+	result := make([]animationplayer.Frame, len(direction.Frames))
+
+	for idx, frame := range direction.Frames {
+		fb := frame.Box
+		result[idx].Rect = rect
+		result[idx].Bounds = image.Rect(fb.Left, fb.Top, fb.Left+fb.Width, fb.Top+fb.Height)
+
+		textureIdx := dirIdx*p.dcc.FramesPerDirection + idx
+		if textureIdx < len(state.textures) {
+			result[idx].Texture = state.textures[textureIdx]
+		}
 	}
 
-	pm := int32(state.PlayMode)
-
-	return giu.Layout{
-		giu.Row(
-			giu.Checkbox("Loop##"+p.id+"PlayRepeat", &state.Repeat),
-			giu.Combo("##"+p.id+"PlayModeList", playModeList[state.PlayMode], playModeList, &pm).OnChange(func() {
-				state.PlayMode = animationPlayMode(pm)
-			}).Size(comboW),
-			giu.InputInt("Tick time##"+p.id+"PlayTickTime", &state.TickTime).Size(inputIntW).OnChange(func() {
-				state.ticker.Reset(time.Second * time.Duration(state.TickTime) / miliseconds)
-			}),
-			hswidget.PlayPauseButton("##"+p.id+"PlayPauseAnimation", &state.IsPlaying, p.textureLoader).
-				Size(playPauseButtonSize, playPauseButtonSize),
-			giu.Button("Export GIF##"+p.id+"exportGif").OnClick(func() {
-				err := p.exportGif(state)
-				if err != nil {
-					dialog.Message(err.Error()).Error()
-				}
-			}),
-		),
-	}
+	return result
 }
 
 func (p *widget) exportGif(state *widgetState) error {
@@ -158,7 +115,7 @@ func (p *widget) exportGif(state *widgetState) error {
 	firstFrame := state.Controls.Direction * fpd
 	images := state.images[firstFrame : firstFrame+fpd]
 
-	err := hsutil.ExportToGif(images, state.TickTime)
+	err := hsutil.ExportToGif(images, state.Player.TickTime())
 	if err != nil {
 		return fmt.Errorf("error creating gif file: %w", err)
 	}
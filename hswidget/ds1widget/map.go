@@ -0,0 +1,352 @@
+package ds1widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+
+	"github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2path"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
+)
+
+const (
+	// map preview is scaled to fit maxMapSize pixels, but its tiles are kept between
+	// minMapTileSize and maxMapTileSize pixels
+	maxMapSize           = 480
+	minMapTileSize       = 5
+	maxMapTileSize       = 25
+	markerRadius         = 3
+	selectedMarkerRadius = 5
+	markerHoverDistance  = 6
+	waypointRadius       = 4
+	maxAlpha             = 0xff
+	// npc actions starts from 1
+	defaultPathAction = 1
+)
+
+func floorColor() color.RGBA {
+	return color.RGBA{R: 0x48, G: 0x48, B: 0x48, A: maxAlpha}
+}
+
+func wallColor() color.RGBA {
+	return color.RGBA{R: 0x9c, G: 0x7c, B: 0x50, A: maxAlpha}
+}
+
+func mapBorderColor() color.RGBA {
+	return color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: maxAlpha}
+}
+
+func monsterMarkerColor() color.RGBA {
+	return color.RGBA{R: 0xe0, G: 0x30, B: 0x30, A: maxAlpha}
+}
+
+func objectMarkerColor() color.RGBA {
+	return color.RGBA{R: 0x30, G: 0x90, B: 0xff, A: maxAlpha}
+}
+
+func unknownMarkerColor() color.RGBA {
+	return color.RGBA{R: 0xff, G: 0xe0, B: 0x30, A: maxAlpha}
+}
+
+func selectedMarkerColor() color.RGBA {
+	return color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: maxAlpha}
+}
+
+func pathColor() color.RGBA {
+	return color.RGBA{R: 0x40, G: 0xff, B: 0x40, A: maxAlpha}
+}
+
+func pathStartColor() color.RGBA {
+	return color.RGBA{R: 0x40, G: 0xff, B: 0x40, A: maxAlpha / 2}
+}
+
+func markerColor(obj *d2ds1.Object) color.RGBA {
+	switch d2enum.ObjectType(obj.Type) {
+	case d2enum.ObjectTypeCharacter:
+		return monsterMarkerColor()
+	case d2enum.ObjectTypeItem:
+		return objectMarkerColor()
+	}
+
+	return unknownMarkerColor()
+}
+
+// mapTileSize returns size of map preview's tile in pixels
+func (p *widget) mapTileSize() int {
+	largest := p.ds1.Width()
+	if h := p.ds1.Height(); h > largest {
+		largest = h
+	}
+
+	if largest == 0 {
+		return maxMapTileSize
+	}
+
+	size := maxMapSize / largest
+
+	switch {
+	case size < minMapTileSize:
+		return minMapTileSize
+	case size > maxMapTileSize:
+		return maxMapTileSize
+	}
+
+	return size
+}
+
+// isTileUsed returns true if any layer of group given has a tile on x, y
+func (p *widget) isTileUsed(t d2ds1.LayerGroupType, x, y int) bool {
+	for _, layer := range *p.ds1.GetLayersGroup(t) {
+		if layer == nil || x >= layer.Width() || y >= layer.Height() {
+			continue
+		}
+
+		if layer.Tile(x, y).Prop1 != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subtileToScreen returns screen position of subtile's center on the map preview
+func subtileToScreen(origin image.Point, tileSize, x, y int) image.Point {
+	return origin.Add(image.Pt(
+		(x*tileSize+tileSize/2)/hsds1.SubtilesPerTile,
+		(y*tileSize+tileSize/2)/hsds1.SubtilesPerTile,
+	))
+}
+
+// screenToSubtile returns subtile of the map preview under screen position given
+func (p *widget) screenToSubtile(origin image.Point, tileSize int, pos image.Point) (x, y int) {
+	delta := pos.Sub(origin)
+	x, y = delta.X*hsds1.SubtilesPerTile/tileSize, delta.Y*hsds1.SubtilesPerTile/tileSize
+
+	clamp := func(v, max int) int {
+		switch {
+		case v < 0:
+			return 0
+		case v >= max:
+			return max - 1
+		}
+
+		return v
+	}
+
+	return clamp(x, p.ds1.Width()*hsds1.SubtilesPerTile), clamp(y, p.ds1.Height()*hsds1.SubtilesPerTile)
+}
+
+// makeMapLayout creates a top-down map preview, which shows used floor and wall tiles
+// and markers of objects; clicking a marker selects the object
+// used in p.makeViewerLayout (in Map tab)
+func (p *widget) makeMapLayout(state *widgetState) giu.Layout {
+	selected := "none"
+	if idx := int(state.Object); idx >= 0 && idx < len(p.ds1.Objects) {
+		selected = fmt.Sprintf("#%d %s", idx, p.objectDescription(&p.ds1.Objects[idx]))
+	}
+
+	editPath := giu.Widget(giu.Checkbox("Edit path of selected object##"+p.id+"editPath", &state.EditPath).OnChange(func() {
+		state.SelectRegion = false
+	}))
+
+	// paths of older versions would be dropped on save
+	if !hsds1.StoresPaths(p.ds1) {
+		state.EditPath = false
+		editPath = giu.Label(fmt.Sprintf("DS1 version %d doesn't store paths", p.ds1.Version()))
+	}
+
+	l := giu.Layout{
+		giu.Label("Red: monsters, blue: objects, yellow: unknown presets"),
+		giu.Row(
+			editPath,
+			giu.Checkbox("Select region##"+p.id+"selectRegion", &state.SelectRegion).OnChange(func() {
+				state.EditPath = false
+			}),
+		),
+		giu.Custom(func() {
+			if state.EditPath {
+				giu.Label("Click to add a waypoint, drag a waypoint to move it, right-click a waypoint to delete it").Build()
+			}
+		}),
+		giu.Custom(func() {
+			p.buildMap(state)
+		}),
+		giu.Label("Selected object: " + selected),
+	}
+
+	if state.SelectRegion {
+		l = append(l, giu.Separator(), p.makeRegionLayout(state))
+	}
+
+	if idx := int(state.Object); idx >= 0 && idx < len(p.ds1.Objects) && len(p.ds1.Objects[idx].Paths) > 0 {
+		l = append(l, p.makePathLayout(state, &p.ds1.Objects[idx]))
+	}
+
+	return l
+}
+
+func (p *widget) buildMap(state *widgetState) {
+	tileSize := p.mapTileSize()
+	width, height := p.ds1.Width()*tileSize, p.ds1.Height()*tileSize
+	origin := giu.GetCursorScreenPos()
+	canvas := giu.GetCanvas()
+
+	for y := 0; y < p.ds1.Height(); y++ {
+		for x := 0; x < p.ds1.Width(); x++ {
+			tileMin := origin.Add(image.Pt(x*tileSize, y*tileSize))
+			tileMax := tileMin.Add(image.Pt(tileSize, tileSize))
+
+			if p.isTileUsed(d2ds1.FloorLayerGroup, x, y) {
+				canvas.AddRectFilled(tileMin, tileMax, floorColor(), 0, 0)
+			}
+
+			if p.isTileUsed(d2ds1.WallLayerGroup, x, y) {
+				canvas.AddRect(tileMin, tileMax, wallColor(), 0, 0, 1)
+			}
+		}
+	}
+
+	canvas.AddRect(origin, origin.Add(image.Pt(width, height)), mapBorderColor(), 0, 0, 1)
+
+	// the button keeps clicks on the map from moving editor's window
+	imgui.InvisibleButton("##"+p.id+"map", imgui.Vec2{X: float32(width), Y: float32(height)})
+	hovered := imgui.IsItemHovered()
+	mousePos := giu.GetMousePos()
+	hoveredObject := -1
+
+	for idx := range p.ds1.Objects {
+		obj := &p.ds1.Objects[idx]
+		center := subtileToScreen(origin, tileSize, obj.X, obj.Y)
+
+		if idx == int(state.Object) {
+			canvas.AddCircleFilled(center, selectedMarkerRadius, selectedMarkerColor())
+		}
+
+		canvas.AddCircleFilled(center, markerRadius, markerColor(obj))
+
+		delta := mousePos.Sub(center)
+		if hovered && delta.X*delta.X+delta.Y*delta.Y <= markerHoverDistance*markerHoverDistance {
+			hoveredObject = idx
+		}
+	}
+
+	p.drawUnresolvedTiles(state, canvas, origin, tileSize)
+	p.drawSelection(state, canvas, origin, tileSize)
+
+	if state.SelectRegion {
+		p.selectRegion(state, origin, tileSize, hovered)
+
+		return
+	}
+
+	if idx := int(state.Object); idx >= 0 && idx < len(p.ds1.Objects) {
+		obj := &p.ds1.Objects[idx]
+		p.drawPath(canvas, origin, tileSize, obj)
+
+		if state.EditPath {
+			p.editPath(state, obj, origin, tileSize, hovered)
+
+			return
+		}
+	}
+
+	if hoveredObject < 0 {
+		return
+	}
+
+	obj := &p.ds1.Objects[hoveredObject]
+	imgui.SetTooltip(fmt.Sprintf("#%d %s\nPosition: %d, %d", hoveredObject, p.objectDescription(obj), obj.X, obj.Y))
+
+	if giu.IsMouseClicked(giu.MouseButtonLeft) {
+		state.Object = int32(hoveredObject)
+	}
+}
+
+// waypointToScreen returns screen position of path's waypoint on the map preview
+func waypointToScreen(origin image.Point, tileSize int, path *d2path.Path) image.Point {
+	return subtileToScreen(origin, tileSize, int(path.Position.X()), int(path.Position.Y()))
+}
+
+// drawPath draws object's path as a polyline, which starts on the object
+func (p *widget) drawPath(canvas *giu.Canvas, origin image.Point, tileSize int, obj *d2ds1.Object) {
+	previous := subtileToScreen(origin, tileSize, obj.X, obj.Y)
+
+	for idx := range obj.Paths {
+		current := waypointToScreen(origin, tileSize, &obj.Paths[idx])
+
+		lineColor := pathColor()
+		if idx == 0 {
+			lineColor = pathStartColor()
+		}
+
+		canvas.AddLine(previous, current, lineColor, 1)
+		canvas.AddCircleFilled(current, waypointRadius, pathColor())
+		canvas.AddText(current.Add(image.Pt(waypointRadius, waypointRadius)), pathColor(), strconv.Itoa(idx))
+
+		previous = current
+	}
+}
+
+// editPath handles mouse on the map preview: click adds a waypoint, dragging moves a waypoint
+// and right-click deletes one
+func (p *widget) editPath(state *widgetState, obj *d2ds1.Object, origin image.Point, tileSize int, hovered bool) {
+	mousePos := giu.GetMousePos()
+
+	if state.isDraggingWaypoint {
+		if !giu.IsMouseDown(giu.MouseButtonLeft) || state.draggedWaypoint >= len(obj.Paths) {
+			state.isDraggingWaypoint = false
+
+			return
+		}
+
+		x, y := p.screenToSubtile(origin, tileSize, mousePos)
+		obj.Paths[state.draggedWaypoint].Position = d2vector.NewPosition(float64(x), float64(y))
+
+		return
+	}
+
+	if !hovered {
+		return
+	}
+
+	hoveredWaypoint := -1
+
+	for idx := range obj.Paths {
+		delta := mousePos.Sub(waypointToScreen(origin, tileSize, &obj.Paths[idx]))
+		if delta.X*delta.X+delta.Y*delta.Y <= markerHoverDistance*markerHoverDistance {
+			hoveredWaypoint = idx
+		}
+	}
+
+	if hoveredWaypoint < 0 {
+		if giu.IsMouseClicked(giu.MouseButtonLeft) {
+			x, y := p.screenToSubtile(origin, tileSize, mousePos)
+			obj.Paths = append(obj.Paths, d2path.Path{
+				Action:   defaultPathAction,
+				Position: d2vector.NewPosition(float64(x), float64(y)),
+			})
+		}
+
+		return
+	}
+
+	path := &obj.Paths[hoveredWaypoint]
+	imgui.SetTooltip(fmt.Sprintf("Waypoint %d\nPosition: %d, %d\nAction: %d",
+		hoveredWaypoint, int(path.Position.X()), int(path.Position.Y()), path.Action))
+
+	switch {
+	case giu.IsMouseClicked(giu.MouseButtonLeft):
+		state.isDraggingWaypoint = true
+		state.draggedWaypoint = hoveredWaypoint
+	case giu.IsMouseClicked(giu.MouseButtonRight):
+		obj.Paths = append(obj.Paths[:hoveredWaypoint], obj.Paths[hoveredWaypoint+1:]...)
+	}
+}
@@ -0,0 +1,152 @@
+package ds1widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
+)
+
+const selectionThickness = 2
+
+func selectionColor() color.RGBA {
+	return color.RGBA{R: 0xff, G: 0xff, B: 0x00, A: maxAlpha}
+}
+
+// ds1CopyLayers are parts of the map, which are copied with a region
+type ds1CopyLayers struct {
+	Floors, Walls, Shadows, Substitutions, Objects bool
+}
+
+// groups returns layer groups chosen to be copied
+func (c *ds1CopyLayers) groups() []d2ds1.LayerGroupType {
+	result := make([]d2ds1.LayerGroupType, 0)
+
+	for t, chosen := range map[d2ds1.LayerGroupType]bool{
+		d2ds1.FloorLayerGroup:        c.Floors,
+		d2ds1.WallLayerGroup:         c.Walls,
+		d2ds1.ShadowLayerGroup:       c.Shadows,
+		d2ds1.SubstitutionLayerGroup: c.Substitutions,
+	} {
+		if chosen {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// selectRegion handles mouse on the map preview: dragging selects a rectangle of tiles
+func (p *widget) selectRegion(state *widgetState, origin image.Point, tileSize int, hovered bool) {
+	x, y := p.screenToSubtile(origin, tileSize, giu.GetMousePos())
+	tile := image.Pt(x/hsds1.SubtilesPerTile, y/hsds1.SubtilesPerTile)
+
+	if state.isSelecting {
+		if !giu.IsMouseDown(giu.MouseButtonLeft) {
+			state.isSelecting = false
+
+			return
+		}
+
+		rect := image.Rectangle{Min: state.selectionStart, Max: tile}.Canon()
+		rect.Max = rect.Max.Add(image.Pt(1, 1))
+		state.selection = rect
+
+		return
+	}
+
+	if hovered && giu.IsMouseClicked(giu.MouseButtonLeft) {
+		state.isSelecting = true
+		state.selectionStart = tile
+		state.selection = image.Rectangle{Min: tile, Max: tile.Add(image.Pt(1, 1))}
+	}
+}
+
+// drawSelection draws selected region on the map preview
+func (p *widget) drawSelection(state *widgetState, canvas *giu.Canvas, origin image.Point, tileSize int) {
+	if state.selection.Empty() {
+		return
+	}
+
+	canvas.AddRect(
+		origin.Add(state.selection.Min.Mul(tileSize)),
+		origin.Add(state.selection.Max.Mul(tileSize)),
+		selectionColor(), 0, 0, selectionThickness,
+	)
+}
+
+// makeRegionLayout creates region selection, copy and paste controls
+// used in p.makeMapLayout
+func (p *widget) makeRegionLayout(state *widgetState) giu.Layout {
+	layers := &state.CopyLayers
+
+	selection := "Drag on the map to select a region"
+	if !state.selection.Empty() {
+		selection = fmt.Sprintf("Selected region: %d, %d (%d x %d tiles)",
+			state.selection.Min.X, state.selection.Min.Y, state.selection.Dx(), state.selection.Dy())
+	}
+
+	clipboard := "Clipboard is empty"
+	if region := p.clipboard.Region(); region != nil {
+		clipboard = fmt.Sprintf("Clipboard: %d x %d tiles, %d object(s)", region.Width, region.Height, len(region.Objects))
+	}
+
+	return giu.Layout{
+		giu.Label(selection),
+		giu.Row(
+			giu.Label("Copy:"),
+			giu.Checkbox("Floors##"+p.id+"copyFloors", &layers.Floors),
+			giu.Checkbox("Walls##"+p.id+"copyWalls", &layers.Walls),
+			giu.Checkbox("Shadows##"+p.id+"copyShadows", &layers.Shadows),
+			giu.Checkbox("Substitutions##"+p.id+"copySubstitutions", &layers.Substitutions),
+			giu.Checkbox("Objects##"+p.id+"copyObjects", &layers.Objects),
+		),
+		giu.Row(
+			giu.Button("Copy region##"+p.id+"copyRegion").Size(actionButtonW, actionButtonH).OnClick(func() {
+				if state.selection.Empty() || p.clipboard == nil {
+					return
+				}
+
+				p.clipboard.Set(hsds1.Copy(p.readFile, p.ds1, state.selection, layers.groups(), layers.Objects))
+			}),
+			giu.Button("Paste at selection##"+p.id+"pasteRegion").Size(actionButtonW, actionButtonH).OnClick(func() {
+				p.pasteRegion(state)
+			}),
+		),
+		giu.Label(clipboard),
+	}
+}
+
+// pasteRegion pastes clipboard's region with its top left corner on selection's one
+func (p *widget) pasteRegion(state *widgetState) {
+	region := p.clipboard.Region()
+	if region == nil || state.selection.Empty() {
+		return
+	}
+
+	result := hsds1.Paste(p.ds1, region, state.selection.Min.X, state.selection.Min.Y)
+	state.selection.Max = state.selection.Min.Add(image.Pt(region.Width, region.Height))
+
+	messages := make([]string, 0)
+
+	if len(result.AddedFiles) > 0 {
+		messages = append(messages, "Added files: "+strings.Join(result.AddedFiles, ", "))
+	}
+
+	if result.SkippedLayers > 0 {
+		messages = append(messages, fmt.Sprintf("%d layer(s) were skipped, because DS1 version %d can't store them",
+			result.SkippedLayers, p.ds1.Version()))
+	}
+
+	if result.SkippedObjects > 0 {
+		messages = append(messages, fmt.Sprintf("%d object(s) outside of the map were skipped", result.SkippedObjects))
+	}
+
+	state.message = strings.Join(messages, "\n")
+}
@@ -0,0 +1,79 @@
+package ds1widget
+
+import (
+	"fmt"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
+)
+
+const anchorButtonSize = 24
+
+// makeAnchorLayout creates a 3x3 grid of buttons, which select resize anchor
+func (p *widget) makeAnchorLayout(state *widgetState) giu.Layout {
+	const anchorsPerRow = 3
+
+	l := giu.Layout{}
+	row := make([]giu.Widget, 0, anchorsPerRow)
+
+	for idx := 0; idx < hsds1.NumAnchors; idx++ {
+		anchor := hsds1.Anchor(idx)
+
+		label := " "
+		if int32(idx) == state.resizeState.Anchor {
+			label = "X"
+		}
+
+		row = append(row, giu.Button(fmt.Sprintf("%s##%sanchor%d", label, p.id, idx)).
+			Size(anchorButtonSize, anchorButtonSize).
+			OnClick(func() {
+				state.resizeState.Anchor = int32(anchor)
+			}))
+
+		if len(row) == anchorsPerRow {
+			l = append(l, giu.Row(row...))
+			row = make([]giu.Widget, 0, anchorsPerRow)
+		}
+	}
+
+	return l
+}
+
+// makeResizeLayout creates map resizing layout
+func (p *widget) makeResizeLayout(state *widgetState) giu.Layout {
+	rs := &state.resizeState
+
+	return giu.Layout{
+		giu.Label(fmt.Sprintf("Current size: %d x %d tiles", p.ds1.Width(), p.ds1.Height())),
+		giu.Row(
+			giu.Label("Width: "),
+			giu.InputInt("##"+p.id+"resizeWidth", &rs.Width).Size(inputIntW),
+			giu.Label("Height: "),
+			giu.InputInt("##"+p.id+"resizeHeight", &rs.Height).Size(inputIntW),
+		),
+		giu.Label("Anchor (this part of the map stays in place): " + hsds1.Anchor(rs.Anchor).String()),
+		p.makeAnchorLayout(state),
+		giu.Label("Tiles, objects and paths are shifted with the map; objects outside of it are removed."),
+		giu.Separator(),
+		giu.Row(
+			giu.Button("Resize##"+p.id+"resizeApply").Size(saveCancelButtonW, saveCancelButtonH).OnClick(func() {
+				removed, err := hsds1.Resize(p.ds1, int(rs.Width), int(rs.Height), hsds1.Anchor(rs.Anchor))
+
+				switch {
+				case err != nil:
+					state.message = fmt.Sprintf("Error resizing the map: %v", err)
+				case removed > 0:
+					state.message = fmt.Sprintf("%d object(s) outside of the resized map were removed", removed)
+				default:
+					state.message = ""
+				}
+
+				state.Mode = widgetModeViewer
+			}),
+			giu.Button("Cancel##"+p.id+"resizeCancel").Size(saveCancelButtonW, saveCancelButtonH).OnClick(func() {
+				state.Mode = widgetModeViewer
+			}),
+		),
+	}
+}
@@ -2,10 +2,12 @@ package ds1widget
 
 import (
 	"fmt"
+	"image"
 
 	"github.com/ianling/giu"
 
 	"github.com/OpenDiablo2/HellSpawner/hsassets"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
 	"github.com/OpenDiablo2/HellSpawner/hswidget"
 )
 
@@ -17,13 +19,20 @@ const (
 	widgetModeAddObject
 	widgetModeAddPath
 	widgetModeConfirm
+	widgetModeResize
+	widgetModeConvertVersion
 )
 
 type ds1Controls struct {
 	TileX, TileY int32
 	Object       int32
 	Subgroup     int32
-	Tile         struct {
+	EditPath     bool
+	SelectRegion bool
+	CopyLayers   ds1CopyLayers
+	// UnresolvedOnly hides resolved tiles in tile references report
+	UnresolvedOnly bool
+	Tile           struct {
 		Floor, Wall, Shadow, Sub int32
 	}
 	noObjectsImageTexture *giu.Texture
@@ -36,6 +45,7 @@ type ds1AddObjectState struct {
 	ObjX     int32
 	ObjY     int32
 	ObjFlags int32
+	Search   string
 }
 
 // Dispose clears state
@@ -55,6 +65,12 @@ func (t *ds1AddPathState) Dispose() {
 	// noop
 }
 
+// ds1ResizeState contains new size of the map
+type ds1ResizeState struct {
+	Width, Height int32
+	Anchor        int32
+}
+
 // widgetState represents ds1 viewers state
 type widgetState struct {
 	*ds1Controls
@@ -63,6 +79,19 @@ type widgetState struct {
 	NewFilePath    string
 	addObjectState ds1AddObjectState
 	addPathState   ds1AddPathState
+	resizeState    ds1ResizeState
+	convertVersion int32
+	// result of the last operation, e.g. number of objects removed by resizing
+	message string
+	// waypoint of selected object's path, which is dragged on the map
+	isDraggingWaypoint bool
+	draggedWaypoint    int
+	// region of the map (in tiles) selected on the map
+	isSelecting    bool
+	selectionStart image.Point
+	selection      image.Rectangle
+	// report of the last check of tile references
+	tileReport *hsds1.ResolutionReport
 }
 
 // Dispose clears viewers state
@@ -96,7 +125,9 @@ func (p *widget) setState(s giu.Disposable) {
 
 func (p *widget) initState() {
 	state := &widgetState{
-		ds1Controls: &ds1Controls{},
+		ds1Controls: &ds1Controls{
+			CopyLayers: ds1CopyLayers{Floors: true, Walls: true, Shadows: true, Substitutions: true, Objects: true},
+		},
 	}
 
 	p.textureLoader.CreateTextureFromFile(hsassets.ImageShrug, func(t *giu.Texture) {
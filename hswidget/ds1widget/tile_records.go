@@ -1,21 +1,30 @@
 package ds1widget
 
 import (
+	"fmt"
+
+	"github.com/ianling/giu"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
 )
 
-func (p *widget) addFloor(idx int32) {
-	p.ds1.InsertFloor(int(idx), &d2ds1.Layer{})
+func (p *widget) addLayer(t d2ds1.LayerGroupType, idx int32) {
+	if err := hsds1.AddLayer(p.ds1, t, int(idx)); err != nil {
+		p.getState().message = err.Error()
+	}
 }
 
-func (p *widget) deleteFloor(idx int32) {
-	p.ds1.DeleteFloor(int(idx))
+func (p *widget) deleteLayer(t d2ds1.LayerGroupType, idx int32) {
+	if err := hsds1.DeleteLayer(p.ds1, t, int(idx)); err != nil {
+		p.getState().message = err.Error()
+	}
 }
 
-func (p *widget) addWall(idx int32) {
-	p.ds1.InsertWall(int(idx), &d2ds1.Layer{})
-}
+// makeLayerCountLabel creates a label with number of layers and how many of them DS1's version can store
+func (p *widget) makeLayerCountLabel(name string, t d2ds1.LayerGroupType) giu.Widget {
+	limits := hsds1.Limits(p.ds1, t)
 
-func (p *widget) deleteWall(idx int32) {
-	p.ds1.DeleteWall(int(idx))
+	return giu.Label(fmt.Sprintf("\t%s Layers: %d (%d - %d)", name, len(*p.ds1.GetLayersGroup(t)), limits.Min, limits.Max))
 }
@@ -0,0 +1,153 @@
+package ds1widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
+)
+
+const reportFileMode = 0o644
+
+func unresolvedTileColor() color.RGBA {
+	return color.RGBA{R: 255, G: 0, B: 255, A: 255}
+}
+
+// makeTileReportLayout creates a report of DT1 tiles, which map's tile records resolve to;
+// clicking position of an unresolved record selects the tile in Tiles tab
+// used in p.makeViewerLayout (in Tile references tab)
+func (p *widget) makeTileReportLayout(state *widgetState) giu.Layout {
+	l := giu.Layout{
+		giu.Row(
+			giu.Button("Check tile references##"+p.id+"checkTiles").OnClick(func() {
+				state.tileReport = hsds1.ResolveTiles(p.readFile, p.ds1)
+			}),
+			giu.Custom(func() {
+				if state.tileReport == nil {
+					return
+				}
+
+				giu.Button("Export report...##" + p.id + "exportTileReport").OnClick(func() {
+					if err := exportTileReport(state.tileReport); err != nil {
+						dialog.Message("error exporting tile report: %v", err).Error()
+					}
+				}).Build()
+			}),
+			giu.Checkbox("Unresolved only##"+p.id+"unresolvedOnly", &state.UnresolvedOnly),
+		),
+	}
+
+	report := state.tileReport
+	if report == nil {
+		return append(l, giu.Label("Tile records are resolved using DT1 files listed in Files tab."))
+	}
+
+	records, unresolved := report.Counts()
+	l = append(l, giu.Label(fmt.Sprintf("%d tile record(s), %d unresolved (check again after changing the map)",
+		records, unresolved)))
+
+	for _, fileError := range report.FileErrors {
+		l = append(l, giu.Style().SetColor(imgui.StyleColorText, unresolvedTileColor()).To(giu.Label(fileError)))
+	}
+
+	for _, layer := range report.Layers {
+		l = append(l, p.makeLayerReportLayout(state, layer))
+	}
+
+	return l
+}
+
+func (p *widget) makeLayerReportLayout(state *widgetState, layer hsds1.LayerResolution) giu.Widget {
+	l := giu.Layout{}
+	unresolved := 0
+
+	for tileIdx, tile := range layer.Tiles {
+		if !tile.Resolved {
+			unresolved++
+		} else if state.UnresolvedOnly {
+			continue
+		}
+
+		id := fmt.Sprintf("%s%s%d_%d", p.id, layer.Group, layer.Index, tileIdx)
+		l = append(l, giu.TreeNode(fmt.Sprintf("%s: %d record(s)##%s", tile.Key, len(tile.Positions), id)).
+			Layout(p.makeTileResolutionLayout(state, tile, id)))
+	}
+
+	return giu.TreeNode(fmt.Sprintf("%s layer %d (%d tile(s), %d unresolved)##%s%s%dreport",
+		layer.Group, layer.Index+1, len(layer.Tiles), unresolved, p.id, layer.Group, layer.Index)).Layout(l)
+}
+
+func (p *widget) makeTileResolutionLayout(state *widgetState, tile hsds1.TileResolution, id string) giu.Layout {
+	l := giu.Layout{}
+
+	if tile.Resolved {
+		l = append(l, giu.Label(fmt.Sprintf("%d variant(s):", len(tile.Variants))))
+	} else {
+		l = append(l, giu.Style().SetColor(imgui.StyleColorText, unresolvedTileColor()).To(
+			giu.Label("Unresolved, DT1 files contain no (or not all) tiles of this key"),
+		))
+	}
+
+	for _, variant := range tile.Variants {
+		l = append(l, giu.Label(variant.String()))
+	}
+
+	if tile.Resolved {
+		return l
+	}
+
+	l = append(l, giu.Label("Positions (click to select the tile):"))
+
+	for idx, position := range tile.Positions {
+		position := position
+
+		l = append(l, giu.Selectable(fmt.Sprintf("%d, %d##%spos%d", position.X, position.Y, id, idx)).OnClick(func() {
+			state.TileX, state.TileY = int32(position.X), int32(position.Y)
+		}))
+	}
+
+	return l
+}
+
+// drawUnresolvedTiles marks tiles with unresolved records on the map preview
+func (p *widget) drawUnresolvedTiles(state *widgetState, canvas *giu.Canvas, origin image.Point, tileSize int) {
+	if state.tileReport == nil {
+		return
+	}
+
+	for _, layer := range state.tileReport.Layers {
+		for _, tile := range layer.Tiles {
+			if tile.Resolved {
+				continue
+			}
+
+			for _, position := range tile.Positions {
+				tileMin := origin.Add(position.Mul(tileSize))
+				canvas.AddRect(tileMin, tileMin.Add(image.Pt(tileSize, tileSize)), unresolvedTileColor(), 0, 0, 2)
+			}
+		}
+	}
+}
+
+// exportTileReport saves report into a text file chosen by user; nothing is saved if the file dialog is cancelled
+func exportTileReport(report *hsds1.ResolutionReport) error {
+	filePath, err := dialog.File().Title("Export tile report").Filter("Text file", "txt").Save()
+	if err != nil || filePath == "" {
+		return nil
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(filePath), []byte(report.String()), os.FileMode(reportFileMode)); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
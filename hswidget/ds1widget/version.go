@@ -0,0 +1,59 @@
+package ds1widget
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
+)
+
+// makeConvertVersionLayout creates layout, which converts DS1 to another version;
+// data, which the target version can't store, is listed before converting
+func (p *widget) makeConvertVersionLayout(state *widgetState) giu.Layout {
+	version := &state.convertVersion
+
+	l := giu.Layout{
+		giu.Label(fmt.Sprintf("Current version: %d", p.ds1.Version())),
+		giu.Row(
+			giu.Label("Convert to version: "),
+			giu.InputInt("##"+p.id+"convertVersion", version).Size(inputIntW),
+		),
+	}
+
+	if *version < hsds1.MinVersion || *version > hsds1.MaxVersion {
+		l = append(l, giu.Label(fmt.Sprintf("Version should be between %d and %d", hsds1.MinVersion, hsds1.MaxVersion)))
+	} else if warnings := hsds1.ConversionWarnings(p.ds1, int(*version)); len(warnings) > 0 {
+		l = append(l, giu.Label("The following data will be lost:"))
+
+		for _, warning := range warnings {
+			l = append(l, giu.Label("- "+warning))
+		}
+	} else {
+		l = append(l, giu.Label("No data will be lost."))
+	}
+
+	return append(l,
+		giu.Separator(),
+		giu.Row(
+			giu.Button("Convert##"+p.id+"convertVersionApply").Size(saveCancelButtonW, saveCancelButtonH).OnClick(func() {
+				warnings, err := hsds1.ConvertVersion(p.ds1, int(*version))
+
+				switch {
+				case err != nil:
+					state.message = fmt.Sprintf("Error converting DS1: %v", err)
+				case len(warnings) > 0:
+					state.message = fmt.Sprintf("Converted to version %d, dropped:\n%s", *version, strings.Join(warnings, "\n"))
+				default:
+					state.message = fmt.Sprintf("Converted to version %d", *version)
+				}
+
+				state.Mode = widgetModeViewer
+			}),
+			giu.Button("Cancel##"+p.id+"convertVersionCancel").Size(saveCancelButtonW, saveCancelButtonH).OnClick(func() {
+				state.Mode = widgetModeViewer
+			}),
+		),
+	)
+}
@@ -8,12 +8,14 @@ import (
 
 	"github.com/ianling/giu"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2path"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
 	"github.com/OpenDiablo2/HellSpawner/hswidget"
 )
 
@@ -26,6 +28,8 @@ const (
 	saveCancelButtonW, saveCancelButtonH = 80, 30
 	bigListW                             = 200
 	imageW, imageH                       = 32, 32
+	presetSearchW                        = 200
+	presetListW, presetListH             = 300, 200
 )
 
 type widget struct {
@@ -33,15 +37,24 @@ type widget struct {
 	ds1                 *d2ds1.DS1
 	deleteButtonTexture *giu.Texture
 	textureLoader       hscommon.TextureLoader
+	presets             *hsds1.Presets
+	clipboard           *hsds1.Clipboard
+	readFile            hsds1.FileReader
 }
 
-// Create creates a new ds1 viewer
-func Create(textureLoader hscommon.TextureLoader, id string, ds1 *d2ds1.DS1, dbt *giu.Texture, state []byte) giu.Widget {
+// Create creates a new ds1 viewer; presets (may be nil) are used to resolve names of objects,
+// regions of the map are copied to (and pasted from) the clipboard shared by DS1 editors
+// and readFile reads DT1 files, which map's tiles are resolved to
+func Create(textureLoader hscommon.TextureLoader, id string, ds1 *d2ds1.DS1, dbt *giu.Texture,
+	presets *hsds1.Presets, clipboard *hsds1.Clipboard, readFile hsds1.FileReader, state []byte) giu.Widget {
 	result := &widget{
 		id:                  id,
 		ds1:                 ds1,
 		deleteButtonTexture: dbt,
 		textureLoader:       textureLoader,
+		presets:             presets,
+		clipboard:           clipboard,
+		readFile:            readFile,
 	}
 
 	if giu.Context.GetState(result.getStateID()) == nil && state != nil {
@@ -69,6 +82,10 @@ func (p *widget) Build() {
 		p.makeAddObjectLayout().Build()
 	case widgetModeAddPath:
 		p.makeAddPathLayout().Build()
+	case widgetModeResize:
+		p.makeResizeLayout(state).Build()
+	case widgetModeConvertVersion:
+		p.makeConvertVersionLayout(state).Build()
 	case widgetModeConfirm:
 		giu.Layout{
 			giu.Label("Please confirm your decision"),
@@ -84,7 +101,9 @@ func (p *widget) makeViewerLayout() giu.Layout {
 	tabs := giu.Layout{
 		giu.TabItem("Files").Layout(p.makeFilesLayout()),
 		giu.TabItem("Objects").Layout(p.makeObjectsLayout(state)),
+		giu.TabItem("Map").Layout(p.makeMapLayout(state)),
 		giu.TabItem("Tiles").Layout(p.makeTilesTabLayout(state)),
+		giu.TabItem("Tile references").Layout(p.makeTileReportLayout(state)),
 	}
 
 	if len(p.ds1.SubstitutionGroups) > 0 {
@@ -101,80 +120,36 @@ func (p *widget) makeViewerLayout() giu.Layout {
 // makeDataLayout creates basic data layout
 // used in p.makeViewerLayout
 func (p *widget) makeDataLayout() giu.Layout {
-	version := int32(p.ds1.Version())
-
 	state := p.getState()
 
 	w, h := int32(p.ds1.Width()), int32(p.ds1.Height())
 	l := giu.Layout{
 		giu.Row(
-			giu.Label("Version: "),
-			giu.InputInt("##"+p.id+"version", &version).Size(inputIntW).OnChange(func() {
-				state.confirmDialog = hswidget.NewPopUpConfirmDialog(
-					"##"+p.id+"confirmVersionChange",
-					"Are you sure, you want to change DS1 Version?",
-					"This value is used while decoding and encoding ds1 file\n"+
-						"Please check github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1/ds1_version.go\n"+
-						"to get more informations what does version determinates.\n\n"+
-						"Continue?",
-					func() {
-						p.ds1.SetVersion(int(version))
-						state.Mode = widgetModeViewer
-					},
-					func() {
-						state.Mode = widgetModeViewer
-					},
-				)
-				state.Mode = widgetModeConfirm
+			giu.Label(fmt.Sprintf("Version: %d", p.ds1.Version())),
+			giu.Button("Convert to version...##"+p.id+"convertVersionButton").OnClick(func() {
+				state.convertVersion = int32(p.ds1.Version())
+				state.Mode = widgetModeConvertVersion
 			}),
 		),
-		// giu.Label(fmt.Sprintf("Size: %d x %d tiles", p.ds1.Width, p.ds1.Height)),
-		giu.Label("Size:"),
 		giu.Row(
-			giu.Label("\tWidth: "),
-			giu.InputInt("##"+p.id+"width", &w).Size(inputIntW).OnChange(func() {
-				state.confirmDialog = hswidget.NewPopUpConfirmDialog(
-					"##"+p.id+"confirmWidthChange",
-					"Are you really sure, you want to change size of DS1 tiles?",
-					"This will affect all your tiles in Tile tab.\n"+
-						"Continue?",
-					func() {
-						p.ds1.SetWidth(int(w))
-						state.Mode = widgetModeViewer
-					},
-					func() {
-						state.Mode = widgetModeViewer
-					},
-				)
-				state.Mode = widgetModeConfirm
-			}),
-		),
-		giu.Row(
-			giu.Label("\tHeight: "),
-			giu.InputInt("##"+p.id+"height", &h).Size(inputIntW).OnChange(func() {
-				state.confirmDialog = hswidget.NewPopUpConfirmDialog(
-					"##"+p.id+"confirmWidthChange",
-					"Are you really sure, you want to change size of DS1 tiles?",
-					"This will affect all your tiles in Tile tab.\n"+
-						"Continue?",
-					func() {
-						p.ds1.SetHeight(int(h))
-						state.Mode = widgetModeViewer
-					},
-					func() {
-						state.Mode = widgetModeViewer
-					},
-				)
-				state.Mode = widgetModeConfirm
+			giu.Label(fmt.Sprintf("Size: %d x %d tiles", w, h)),
+			giu.Button("Resize...##"+p.id+"resize").OnClick(func() {
+				state.resizeState = ds1ResizeState{Width: w, Height: h, Anchor: int32(hsds1.AnchorTopLeft)}
+				state.Mode = widgetModeResize
 			}),
 		),
+		giu.Custom(func() {
+			if state.message != "" {
+				giu.Label(state.message).Build()
+			}
+		}),
 		giu.Label(fmt.Sprintf("Substitution Type: %d", p.ds1.SubstitutionType)),
 		giu.Separator(),
 		giu.Label("Number of"),
-		giu.Label(fmt.Sprintf("\tWall Layers: %d", len(p.ds1.Walls))),
-		giu.Label(fmt.Sprintf("\tFloor Layers: %d", len(p.ds1.Floors))),
-		giu.Label(fmt.Sprintf("\tShadow Layers: %d", len(p.ds1.Shadows))),
-		giu.Label(fmt.Sprintf("\tSubstitution Layers: %d", len(p.ds1.Substitutions))),
+		p.makeLayerCountLabel("Wall", d2ds1.WallLayerGroup),
+		p.makeLayerCountLabel("Floor", d2ds1.FloorLayerGroup),
+		p.makeLayerCountLabel("Shadow", d2ds1.ShadowLayerGroup),
+		p.makeLayerCountLabel("Substitution", d2ds1.SubstitutionLayerGroup),
 	}
 
 	return l
@@ -303,6 +278,7 @@ func (p *widget) makeObjectLayout(state *widgetState) giu.Layout {
 				&obj.Type,
 				nil,
 			),
+			giu.Label(hsds1.TypeName(d2enum.ObjectType(obj.Type))),
 		),
 		giu.Row(
 			giu.Label("ID: "),
@@ -313,7 +289,8 @@ func (p *widget) makeObjectLayout(state *widgetState) giu.Layout {
 				nil,
 			),
 		),
-		giu.Label("Position (tiles): "),
+		giu.Label("Name: " + p.presetName(obj.Type, obj.ID)),
+		giu.Label("Position (subtiles): "),
 		giu.Row(
 			giu.Label("\tX: "),
 			hswidget.MakeInputInt(
@@ -353,18 +330,74 @@ func (p *widget) makeObjectLayout(state *widgetState) giu.Layout {
 	return l
 }
 
+// presetName returns name of object's preset in map's act
+func (p *widget) presetName(objType, id int) string {
+	if p.presets == nil {
+		return "(presets not loaded)"
+	}
+
+	name, found := p.presets.Name(objType, id)
+	if !found {
+		return fmt.Sprintf("(unknown in act %d)", p.presets.Act)
+	}
+
+	return name
+}
+
+// objectDescription returns object's type and preset name, e.g. "Monster 2: akara"
+func (p *widget) objectDescription(obj *d2ds1.Object) string {
+	return fmt.Sprintf("%s %d: %s", hsds1.TypeName(d2enum.ObjectType(obj.Type)), obj.ID, p.presetName(obj.Type, obj.ID))
+}
+
+// makePresetPickerLayout creates a searchable list of act's presets; selecting a preset
+// sets type and ID of the new object
+// used in p.makeAddObjectLayout
+func (p *widget) makePresetPickerLayout(state *widgetState) giu.Layout {
+	if p.presets == nil {
+		return giu.Layout{
+			giu.Label("Object presets couldn't be loaded (obj.txt, objects.txt and monpreset.txt"),
+			giu.Label("were found neither in the project nor in auxiliary MPQs)"),
+		}
+	}
+
+	items := giu.Layout{}
+
+	for _, preset := range p.presets.Search(state.addObjectState.Search) {
+		preset := preset
+		selected := int(state.addObjectState.ObjType) == int(preset.Type) && int(state.addObjectState.ObjID) == preset.ID
+		label := fmt.Sprintf("%s %d: %s##%s%d_%d", preset.TypeName(), preset.ID, preset.Name, p.id, preset.Type, preset.ID)
+
+		items = append(items, giu.Selectable(label).Selected(selected).OnClick(func() {
+			state.addObjectState.ObjType = int32(preset.Type)
+			state.addObjectState.ObjID = int32(preset.ID)
+		}))
+	}
+
+	return giu.Layout{
+		giu.Row(
+			giu.Label(fmt.Sprintf("Search presets of act %d: ", p.presets.Act)),
+			giu.InputText("##"+p.id+"AddObjectSearch", &state.addObjectState.Search).Size(presetSearchW),
+		),
+		giu.Child("##"+p.id+"AddObjectPresets").Border(true).Size(presetListW, presetListH).Layout(items),
+	}
+}
+
 func (p *widget) makeAddObjectLayout() giu.Layout {
 	state := p.getState()
 
 	return giu.Layout{
+		p.makePresetPickerLayout(state),
+		giu.Separator(),
 		giu.Row(
 			giu.Label("Type: "),
 			giu.InputInt("##"+p.id+"AddObjectType", &state.addObjectState.ObjType).Size(inputIntW),
+			giu.Label(hsds1.TypeName(d2enum.ObjectType(state.addObjectState.ObjType))),
 		),
 		giu.Row(
 			giu.Label("ID: "),
 			giu.InputInt("##"+p.id+"AddObjectID", &state.addObjectState.ObjID).Size(inputIntW),
 		),
+		giu.Label("Name: " + p.presetName(int(state.addObjectState.ObjType), int(state.addObjectState.ObjID))),
 		giu.Row(
 			giu.Label("X: "),
 			giu.InputInt("##"+p.id+"AddObjectX", &state.addObjectState.ObjX).Size(inputIntW),
@@ -413,11 +446,19 @@ func (p *widget) makePathLayout(state *widgetState, obj *d2ds1.Object) giu.Layou
 
 	for idx := range obj.Paths {
 		currentIdx := idx
-		x, y := obj.Paths[idx].Position.X(), obj.Paths[idx].Position.Y()
+		path := &obj.Paths[idx]
+		x, y := int32(path.Position.X()), int32(path.Position.Y())
+		setPosition := func() {
+			path.Position = d2vector.NewPosition(float64(x), float64(y))
+		}
+
 		rowWidgets = append(rowWidgets, giu.TableRow(
 			giu.Label(fmt.Sprintf("%d", idx)),
-			giu.Label(fmt.Sprintf("(%d, %d)", int(x), int(y))),
-			giu.Label(fmt.Sprintf("%d", obj.Paths[idx].Action)),
+			giu.Row(
+				giu.InputInt("##"+p.id+"pathX"+strconv.Itoa(currentIdx), &x).Size(inputIntW).OnChange(setPosition),
+				giu.InputInt("##"+p.id+"pathY"+strconv.Itoa(currentIdx), &y).Size(inputIntW).OnChange(setPosition),
+			),
+			hswidget.MakeInputInt("##"+p.id+"pathAction"+strconv.Itoa(currentIdx), inputIntW, &path.Action, nil),
 			hswidget.MakeImageButton(
 				"##"+p.id+"deletePath"+strconv.Itoa(currentIdx),
 				deleteButtonSize, deleteButtonSize,
@@ -493,42 +534,38 @@ func (p *widget) makeTilesGroupLayout(state *widgetState, x, y int, t d2ds1.Laye
 
 	// this is a pointer to appropriate record index
 	var recordIdx *int32
-	// addCb is a callback for layer-add button
-	var addCb func(int32)
-	// delCb is a callback for layer-delete button
-	var deleteCb func(int32)
 
-	// sets "everything" ;-)
 	switch t {
 	case d2ds1.FloorLayerGroup:
 		recordIdx = &state.Tile.Floor
-		addCb = p.addFloor
-		deleteCb = p.deleteFloor
 	case d2ds1.WallLayerGroup:
 		recordIdx = &state.Tile.Wall
-		addCb = p.addWall
-		deleteCb = p.deleteWall
 	case d2ds1.ShadowLayerGroup:
 		recordIdx = &state.Tile.Shadow
 	case d2ds1.SubstitutionLayerGroup:
 		recordIdx = &state.Tile.Sub
 	}
 
+	// buttons are shown only if DS1's version can store more/less layers
 	var addBtn *giu.ButtonWidget
-	if addCb != nil {
+
+	addErr := hsds1.CanAddLayer(p.ds1, t)
+	if addErr == nil {
 		addBtn = giu.Button("Add "+t.String()+" ##"+p.id+"addButton").
 			Size(actionButtonW, actionButtonH).
-			OnClick(func() { addCb(*recordIdx) })
+			OnClick(func() { p.addLayer(t, *recordIdx) })
 	}
 
 	var deleteBtn giu.Widget
-	if deleteCb != nil {
+
+	deleteErr := hsds1.CanDeleteLayer(p.ds1, t)
+	if deleteErr == nil {
 		deleteBtn = hswidget.MakeImageButton(
 			"##"+p.id+"delete"+t.String(),
 			layerDeleteButtonSize, layerDeleteButtonSize,
 			p.deleteButtonTexture,
 			func() {
-				deleteCb(*recordIdx)
+				p.deleteLayer(t, *recordIdx)
 			},
 		)
 	}
@@ -564,6 +601,12 @@ func (p *widget) makeTilesGroupLayout(state *widgetState, x, y int, t d2ds1.Laye
 				l = append(l, btn)
 			}
 			giu.Row(l...).Build()
+
+			for _, err := range []error{addErr, deleteErr} {
+				if err != nil {
+					giu.Label(err.Error()).Build()
+				}
+			}
 		}),
 	})}
 }
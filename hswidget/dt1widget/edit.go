@@ -0,0 +1,233 @@
+package dt1widget
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdc6"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdt1"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+)
+
+const editButtonW = 110
+
+var errNoPalette = errors.New("select a palette first (DT1 Editor > Change Palette)")
+
+// edit applies an edit to the DT1 and reloads its tiles
+func (p *widget) edit(state *widgetState, fn func() error) {
+	if err := fn(); err != nil {
+		state.editMessage = err.Error()
+
+		return
+	}
+
+	state.editMessage = ""
+
+	p.reloadTiles(state)
+}
+
+// reloadTiles groups tiles again (tiles may have moved in memory) and recreates their textures
+func (p *widget) reloadTiles(state *widgetState) {
+	state.tileGroups = p.groupTilesByIdentity()
+
+	if g := int32(len(state.tileGroups)) - 1; state.TileGroup > g {
+		state.TileGroup = g
+	}
+
+	if state.TileGroup < 0 {
+		state.TileGroup = 0
+	}
+
+	if len(state.tileGroups) > 0 {
+		if v := int32(len(state.tileGroups[state.TileGroup])) - 1; state.TileVariant > v {
+			state.TileVariant = v
+		}
+	}
+
+	state.LastTileGroup = state.TileGroup
+	// tiles' indices may have changed
+	state.selectedTiles = nil
+	state.duplicates = nil
+
+	p.makeTileTextures()
+}
+
+// selectTile selects group and variant of tile of index given
+func (p *widget) selectTile(state *widgetState, index int) {
+	tile := &p.dt1.Tiles[index]
+
+	for groupIdx, group := range state.tileGroups {
+		for variantIdx, variant := range group {
+			if variant == tile {
+				state.TileGroup, state.TileVariant = int32(groupIdx), int32(variantIdx)
+				state.LastTileGroup = state.TileGroup
+
+				return
+			}
+		}
+	}
+}
+
+// tileIndex returns index of tile in DT1's tiles list
+func (p *widget) tileIndex(tile *d2dt1.Tile) int {
+	for idx := range p.dt1.Tiles {
+		if &p.dt1.Tiles[idx] == tile {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// tileKind returns kind of tile's graphics: floors (and shadows) have positive height, walls negative
+func tileKind(tile *d2dt1.Tile) hsdt1.TileKind {
+	if tile.Height > 0 {
+		return hsdt1.TileKindFloor
+	}
+
+	return hsdt1.TileKindWall
+}
+
+// makeEditLayout creates buttons, which add, replace, export and delete tiles; tile may be nil if DT1 is empty
+func (p *widget) makeEditLayout(state *widgetState, tile *d2dt1.Tile) giu.Layout {
+	button := func(label, id string, fn func() error) giu.Widget {
+		return giu.Button(label+"##"+p.id+id).Size(editButtonW, 0).OnClick(func() {
+			p.edit(state, fn)
+		})
+	}
+
+	// imported tile is selected after tiles are reloaded
+	importButton := func(label, id string, kind hsdt1.TileKind) giu.Widget {
+		return giu.Button(label+"##"+p.id+id).Size(editButtonW, 0).OnClick(func() {
+			numTiles := len(p.dt1.Tiles)
+
+			p.edit(state, func() error {
+				indices, w, h, err := p.loadImage()
+				if err != nil || indices == nil {
+					return err
+				}
+
+				newTile, err := hsdt1.NewTile(kind, indices, w, h)
+				if err != nil {
+					return fmt.Errorf("error creating tile: %w", err)
+				}
+
+				hsdt1.AddTile(p.dt1, newTile)
+
+				return nil
+			})
+
+			if len(p.dt1.Tiles) > numTiles {
+				p.selectTile(state, len(p.dt1.Tiles)-1)
+			}
+		})
+	}
+
+	l := giu.Layout{
+		giu.Row(
+			importButton("Import floor...", "importFloor", hsdt1.TileKindFloor),
+			importButton("Import wall...", "importWall", hsdt1.TileKindWall),
+		),
+	}
+
+	if tile != nil {
+		l = append(l, giu.Row(
+			button("Replace image...", "replaceImage", func() error {
+				indices, w, h, err := p.loadImage()
+				if err != nil || indices == nil {
+					return err
+				}
+
+				if err := hsdt1.ReplaceGraphics(tile, tileKind(tile), indices, w, h); err != nil {
+					return fmt.Errorf("error replacing tile's image: %w", err)
+				}
+
+				return nil
+			}),
+			giu.Button("Export PNG...##"+p.id+"exportPNG").Size(editButtonW, 0).OnClick(func() {
+				state.editMessage = ""
+
+				if err := p.exportTile(tile); err != nil {
+					state.editMessage = err.Error()
+				}
+			}),
+			button("Delete tile", "deleteTile", func() error {
+				return hsdt1.DeleteTile(p.dt1, p.tileIndex(tile))
+			}),
+		))
+	}
+
+	return append(l,
+		giu.Label(fmt.Sprintf("Floors are %dx%d pixels, walls are %d pixels wide and stand on the top corner of the floor.",
+			hsdt1.TileWidth, hsdt1.FloorHeight, hsdt1.TileWidth)),
+		giu.Label(state.editMessage),
+	)
+}
+
+// loadImage loads a png image selected by user and maps its colors to the nearest colors of palette;
+// indices are nil if user cancelled the selection
+func (p *widget) loadImage() (indices []byte, width, height int, err error) {
+	if p.palette == nil {
+		return nil, 0, 0, errNoPalette
+	}
+
+	filePath, err := dialog.File().Title("Select a tile image").Filter("PNG image", "png").Load()
+	if err != nil || filePath == "" {
+		return nil, 0, 0, nil
+	}
+
+	f, err := os.Open(filepath.Clean(filePath))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("error opening image: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	frame := hsdc6.FrameFromImage(img, hsdc6.NewQuantizer(hspalette.Colors(p.palette)))
+
+	return frame.Indices, frame.Width, frame.Height, nil
+}
+
+// exportTile saves tile's image (floor and wall blocks together) to a png file
+func (p *widget) exportTile(tile *d2dt1.Tile) error {
+	if p.palette == nil {
+		return errNoPalette
+	}
+
+	filePath, err := dialog.File().Title("Export tile").Filter("PNG image", "png").Save()
+	if err != nil || filePath == "" {
+		return nil
+	}
+
+	f, err := os.Create(filepath.Clean(filePath))
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if err := png.Encode(f, hsdt1.Image(tile, hspalette.Colors(p.palette))); err != nil {
+		return fmt.Errorf("error encoding image: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package dt1widget
+
+import (
+	"fmt"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdt1"
+)
+
+// makeFlagPatternLayout creates buttons, which fill the current flag on the whole tile and copy
+// all subtile flags of the tile to other tiles
+// used in p.makeSubtileFlags
+func (p *widget) makeFlagPatternLayout(state *widgetState, tile *d2dt1.Tile) giu.Layout {
+	bit := uint(state.controls.SubtileFlag)
+
+	button := func(label, id string, fn func() string) *giu.ButtonWidget {
+		return giu.Button(label+"##"+p.id+id).Size(editButtonW, 0).OnClick(func() {
+			state.editMessage = fn()
+		})
+	}
+
+	// pattern buttons need a copied pattern
+	patternButton := func(label, id string, fn func(pattern hsdt1.FlagPattern) string) *giu.ButtonWidget {
+		return button(label, id, func() string {
+			if state.flagPattern == nil {
+				return "copy flags of a tile first"
+			}
+
+			return fn(*state.flagPattern)
+		})
+	}
+
+	return giu.Layout{
+		giu.Row(
+			button("Fill flag", "fillFlag", func() string {
+				hsdt1.FillSubtileFlag(tile, bit, true)
+
+				return ""
+			}),
+			button("Clear flag", "clearFlag", func() string {
+				hsdt1.FillSubtileFlag(tile, bit, false)
+
+				return ""
+			}),
+			button("Copy flags", "copyFlags", func() string {
+				pattern := hsdt1.CopyFlags(tile)
+				state.flagPattern = &pattern
+
+				return fmt.Sprintf("flags of tile %d copied", p.tileIndex(tile))
+			}),
+			patternButton("Paste flags", "pasteFlags", func(pattern hsdt1.FlagPattern) string {
+				hsdt1.ApplyFlags(pattern, tile)
+
+				return ""
+			}),
+		),
+		patternButton("Paste flags to all tiles of this style and sequence", "pasteFlagsToStyle",
+			func(pattern hsdt1.FlagPattern) string {
+				n := hsdt1.ApplyFlagsToStyle(p.dt1, pattern, tile.Style, tile.Sequence)
+
+				return fmt.Sprintf("flags pasted to %d tile(s) of style %d, sequence %d", n, tile.Style, tile.Sequence)
+			}).Size(0, 0),
+		giu.Label("Tiles to paste flags to:"),
+		p.makeTileList(state, "flagTileList"),
+		patternButton("Paste to selected", "pasteFlagsToSelected", func(pattern hsdt1.FlagPattern) string {
+			tiles := make([]*d2dt1.Tile, 0)
+
+			for idx, selected := range state.selectedTiles {
+				if selected {
+					tiles = append(tiles, &p.dt1.Tiles[idx])
+				}
+			}
+
+			hsdt1.ApplyFlags(pattern, tiles...)
+
+			return fmt.Sprintf("flags pasted to %d tile(s)", len(tiles))
+		}),
+		giu.Label(state.editMessage),
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/ianling/giu"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdt1"
 )
 
 type controls struct {
@@ -26,6 +28,19 @@ type widgetState struct {
 
 	tileGroups [][]*d2dt1.Tile
 	textures   [][]map[string]*giu.Texture
+	// result of the last edit (e.g. an error importing an image)
+	editMessage string
+	// value of the subtile flag painted while mouse button is held over the grid
+	painting   bool
+	paintValue bool
+	// subtile flags copied from a tile and tiles selected for pasting them
+	flagPattern   *hsdt1.FlagPattern
+	selectedTiles []bool
+	// options of moving tiles between DT1 files and groups of duplicate tiles found
+	updateDS1       bool
+	extractMode     int32
+	removeExtracted bool
+	duplicates      [][]int
 }
 
 // Dispose clears viewers state
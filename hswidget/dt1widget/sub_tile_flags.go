@@ -1,16 +1,5 @@
 package dt1widget
 
-const (
-	subTileFlagBlockWalk = iota
-	subTileFlagBlockLOS
-	subTileFlagBlockJump
-	subTileFlagBlockPlayerWalk
-	subTileFlagUnknown1
-	subTileFlagBlockLight
-	subTileFlagUnknown2
-	subTileFlagUnknown3
-)
-
 func subTileString(subtile int32) string {
 	lookup := map[byte]string{
 		1 << 0: "block walk",
@@ -42,30 +31,3 @@ func getFlagFromPos(x, y int) int {
 
 	return subtileLookup[y][x]
 }
-
-func (p *widget) getSubTileFieldToEdit(idx int) *bool {
-	state := p.getState()
-
-	tileIdx := state.TileGroup
-
-	switch state.SubtileFlag {
-	case subTileFlagBlockWalk:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].BlockWalk
-	case subTileFlagBlockLOS:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].BlockLOS
-	case subTileFlagBlockJump:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].BlockJump
-	case subTileFlagBlockPlayerWalk:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].BlockPlayerWalk
-	case subTileFlagUnknown1:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].Unknown1
-	case subTileFlagBlockLight:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].BlockLight
-	case subTileFlagUnknown2:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].Unknown2
-	case subTileFlagUnknown3:
-		return &p.dt1.Tiles[tileIdx].SubTileFlags[idx].Unknown3
-	}
-
-	return nil
-}
@@ -0,0 +1,255 @@
+package dt1widget
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdt1"
+)
+
+const (
+	tileListH     = 120
+	tilesFileMode = 0o644
+)
+
+// tiles extracted into a new DT1
+const (
+	extractSelected = iota
+	extractStyle
+	extractType
+)
+
+// Project updates project's files, when tiles are moved between DT1 files
+type Project interface {
+	UpdateDS1Files(oldPaths, newPaths []string) (updated []string, err error)
+	InvalidateFileStructure()
+}
+
+func tileLabel(idx int, tile *d2dt1.Tile) string {
+	return fmt.Sprintf("%d: %s, style %d, sequence %d", idx, d2enum.TileType(tile.Type), tile.Style, tile.Sequence)
+}
+
+// makeTileList creates a list of checkboxes selecting tiles of DT1
+func (p *widget) makeTileList(state *widgetState, id string) giu.Widget {
+	if len(state.selectedTiles) != len(p.dt1.Tiles) {
+		state.selectedTiles = make([]bool, len(p.dt1.Tiles))
+	}
+
+	tileList := giu.Layout{}
+
+	for idx := range p.dt1.Tiles {
+		label := fmt.Sprintf("%s##%s%s%d", tileLabel(idx, &p.dt1.Tiles[idx]), p.id, id, idx)
+		tileList = append(tileList, giu.Checkbox(label, &state.selectedTiles[idx]))
+	}
+
+	selectAll := func(value bool) {
+		for idx := range state.selectedTiles {
+			state.selectedTiles[idx] = value
+		}
+	}
+
+	return giu.Layout{
+		giu.Child("##"+p.id+id).Border(true).Size(0, tileListH).Layout(tileList...),
+		giu.Row(
+			giu.Button("Select all##"+p.id+id+"selectAll").Size(editButtonW, 0).OnClick(func() {
+				selectAll(true)
+			}),
+			giu.Button("Select none##"+p.id+id+"selectNone").Size(editButtonW, 0).OnClick(func() {
+				selectAll(false)
+			}),
+		),
+	}
+}
+
+// tilesetEdit is p.edit, which reports its result
+func (p *widget) tilesetEdit(state *widgetState, fn func() (message string, err error)) {
+	var message string
+
+	p.edit(state, func() (err error) {
+		message, err = fn()
+
+		return err
+	})
+
+	if state.editMessage == "" {
+		state.editMessage = message
+	}
+}
+
+// makeTilesetLayout creates buttons, which merge other DT1 files into this one, extract tiles
+// into a new DT1 file and find duplicate tiles
+func (p *widget) makeTilesetLayout(state *widgetState, tile *d2dt1.Tile) giu.Layout {
+	extractModes := []string{"selected tiles", "tiles of this style and sequence", "tiles of this type"}
+
+	l := giu.Layout{
+		giu.Checkbox("Update DT1 files listed in project's DS1 files##"+p.id+"updateDS1", &state.updateDS1),
+		giu.Button("Merge DT1 file...##" + p.id + "mergeDT1").OnClick(func() {
+			p.tilesetEdit(state, p.mergeTileset)
+		}),
+		giu.Row(
+			giu.Label("Extract"),
+			giu.Combo("##"+p.id+"extractMode", extractModes[state.extractMode], extractModes, &state.extractMode),
+			giu.Checkbox("Move (remove from this file)##"+p.id+"removeExtracted", &state.removeExtracted),
+			giu.Button("Extract to new file...##"+p.id+"extractDT1").OnClick(func() {
+				p.tilesetEdit(state, func() (string, error) {
+					return p.extractTiles(state, tile)
+				})
+			}),
+		),
+		giu.Row(
+			giu.Button("Find duplicates##"+p.id+"findDuplicates").OnClick(func() {
+				state.duplicates = hsdt1.Duplicates(p.dt1)
+				state.editMessage = fmt.Sprintf("%d group(s) of pixel-identical tiles found", len(state.duplicates))
+			}),
+			giu.Button("Select duplicates##"+p.id+"selectDuplicates").OnClick(func() {
+				p.selectDuplicates(state)
+			}),
+			giu.Button("Delete selected tiles##"+p.id+"deleteSelected").OnClick(func() {
+				p.tilesetEdit(state, func() (string, error) {
+					n := len(hsdt1.Extract(p.dt1, hsdt1.BySelection(state.selectedTiles), true).Tiles)
+
+					return fmt.Sprintf("%d tile(s) deleted", n), nil
+				})
+			}),
+		),
+	}
+
+	for groupIdx, group := range state.duplicates {
+		labels := make([]string, len(group))
+
+		for idx, tileIdx := range group {
+			labels[idx] = tileLabel(tileIdx, &p.dt1.Tiles[tileIdx])
+		}
+
+		first := group[0]
+
+		l = append(l, giu.Selectable(fmt.Sprintf("%s##%sduplicates%d", strings.Join(labels, " = "), p.id, groupIdx)).
+			OnClick(func() {
+				p.selectTile(state, first)
+			}))
+	}
+
+	return append(l,
+		giu.Label("Tiles:"),
+		p.makeTileList(state, "tilesetTileList"),
+		giu.Label(state.editMessage),
+	)
+}
+
+// selectDuplicates selects all tiles of every group of duplicates but the first one
+func (p *widget) selectDuplicates(state *widgetState) {
+	if len(state.selectedTiles) != len(p.dt1.Tiles) {
+		state.selectedTiles = make([]bool, len(p.dt1.Tiles))
+	}
+
+	for _, group := range state.duplicates {
+		for _, tileIdx := range group[1:] {
+			state.selectedTiles[tileIdx] = true
+		}
+	}
+}
+
+// mergeTileset appends tiles of a DT1 file selected by user
+func (p *widget) mergeTileset() (string, error) {
+	filePath, err := dialog.File().Title("Select a DT1 file to merge").Filter("DT1 tileset", "dt1").Load()
+	if err != nil || filePath == "" {
+		return "", nil
+	}
+
+	if filepath.Clean(filePath) == filepath.Clean(p.path) {
+		return "", errors.New("can't merge the file into itself")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", filePath, err)
+	}
+
+	other, err := d2dt1.LoadDT1(data)
+	if err != nil {
+		return "", fmt.Errorf("error loading %s: %w", filePath, err)
+	}
+
+	hsdt1.Merge(p.dt1, other)
+
+	message := fmt.Sprintf("%d tile(s) merged from %s", len(other.Tiles), filePath)
+
+	return message + p.updateDS1Files([]string{filePath, p.path}, []string{p.path}), nil
+}
+
+// extractTiles saves tiles selected by extract mode into a new DT1 file
+func (p *widget) extractTiles(state *widgetState, tile *d2dt1.Tile) (string, error) {
+	var filter hsdt1.TileFilter
+
+	switch {
+	case state.extractMode == extractSelected:
+		filter = hsdt1.BySelection(state.selectedTiles)
+	case tile == nil:
+		return "", errors.New("there is no tile selected")
+	case state.extractMode == extractStyle:
+		filter = hsdt1.ByStyle(tile.Style, tile.Sequence)
+	case state.extractMode == extractType:
+		filter = hsdt1.ByType(tile.Type)
+	}
+
+	extracted := hsdt1.Extract(p.dt1, filter, false)
+	if len(extracted.Tiles) == 0 {
+		return "", errors.New("there are no tiles to extract")
+	}
+
+	filePath, err := dialog.File().Title("Extract tiles").Filter("DT1 tileset", "dt1").Save()
+	if err != nil || filePath == "" {
+		return "", nil
+	}
+
+	if filepath.Clean(filePath) == filepath.Clean(p.path) {
+		return "", errors.New("can't extract tiles into the file itself")
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(filePath), hsdt1.Marshal(extracted), os.FileMode(tilesFileMode)); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", filePath, err)
+	}
+
+	if p.project != nil {
+		p.project.InvalidateFileStructure()
+	}
+
+	message := fmt.Sprintf("%d tile(s) extracted to %s", len(extracted.Tiles), filePath)
+
+	// copied tiles would become rarity variants of the original ones, so DS1s are updated only when tiles are moved
+	if !state.removeExtracted {
+		return message, nil
+	}
+
+	hsdt1.Extract(p.dt1, filter, true)
+
+	return message + p.updateDS1Files([]string{p.path}, []string{p.path, filePath}), nil
+}
+
+// updateDS1Files updates DT1 files listed in project's DS1 files, if the option is set,
+// and returns description of the result
+func (p *widget) updateDS1Files(oldPaths, newPaths []string) string {
+	state := p.getState()
+
+	if !state.updateDS1 || p.project == nil {
+		return ""
+	}
+
+	updated, err := p.project.UpdateDS1Files(oldPaths, newPaths)
+	if err != nil {
+		return fmt.Sprintf("; %d DS1 file(s) updated, %v", len(updated), err)
+	}
+
+	return fmt.Sprintf("; %d DS1 file(s) updated (save this file, so that they find its tiles)", len(updated))
+}
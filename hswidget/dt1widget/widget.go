@@ -19,6 +19,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdt1"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/dt1widget/tiletypeimage"
 )
 
@@ -49,15 +50,21 @@ type widget struct {
 	dt1           *d2dt1.DT1
 	palette       *[256]d2interface.Color
 	textureLoader hscommon.TextureLoader
+	path          string
+	project       Project
 }
 
-// Create creates a new dt1 viewers widget
-func Create(state []byte, palette *[256]d2interface.Color, textureLoader hscommon.TextureLoader, id string, dt1 *d2dt1.DT1) giu.Widget {
+// Create creates a new dt1 viewers widget; path is DT1's file path
+// and project (may be nil) updates DS1 files, when tiles are moved between DT1 files
+func Create(state []byte, palette *[256]d2interface.Color, textureLoader hscommon.TextureLoader, id string, dt1 *d2dt1.DT1,
+	path string, project Project) giu.Widget {
 	result := &widget{
 		id:            id,
 		dt1:           dt1,
 		textureLoader: textureLoader,
 		palette:       palette,
+		path:          path,
+		project:       project,
 	}
 
 	result.registerKeyboardShortcuts()
@@ -88,6 +95,10 @@ func (p *widget) Build() {
 	if len(state.tileGroups) == 0 {
 		giu.Layout{
 			giu.Label("Nothing to display"),
+			giu.Separator(),
+			p.makeEditLayout(state, nil),
+			giu.Separator(),
+			p.makeTilesetLayout(state, nil),
 		}.Build()
 
 		return
@@ -101,22 +112,30 @@ func (p *widget) Build() {
 		giu.Separator(),
 		p.makeTileDisplay(state, tile),
 		giu.Separator(),
+		p.makeEditLayout(state, tile),
+		giu.Separator(),
 		giu.TabBar("##TabBar_dt1_" + p.id).Layout(giu.Layout{
 			giu.TabItem("Info").Layout(p.makeTileInfoTab(tile)),
 			giu.TabItem("Material").Layout(p.makeMaterialTab(tile)),
 			giu.TabItem("Subtile Flags").Layout(p.makeSubtileFlags(state, tile)),
+			giu.TabItem("Tileset").Layout(p.makeTilesetLayout(state, tile)),
 		}),
 	}.Build()
 }
 
 func (p *widget) groupTilesByIdentity() [][]*d2dt1.Tile {
+	return groupTiles(p.dt1)
+}
+
+// groupTiles groups tiles of the same type, style and sequence (in order of their first appearance)
+func groupTiles(dt1 *d2dt1.DT1) [][]*d2dt1.Tile {
 	result := make([][]*d2dt1.Tile, 0)
 
 	var tileID, groupID tileIdentity
 
 OUTER:
-	for tileIdx := range p.dt1.Tiles {
-		tile := &p.dt1.Tiles[tileIdx]
+	for tileIdx := range dt1.Tiles {
+		tile := &dt1.Tiles[tileIdx]
 		tileID = tileID.fromTile(tile)
 
 		for groupIdx := range result {
@@ -134,6 +153,19 @@ OUTER:
 	return result
 }
 
+// TileGroupOf returns the tile group, which DT1 widget shows tile of index given in
+func TileGroupOf(dt1 *d2dt1.DT1, index int) int32 {
+	for groupIdx, group := range groupTiles(dt1) {
+		for _, tile := range group {
+			if tile == &dt1.Tiles[index] {
+				return int32(groupIdx)
+			}
+		}
+	}
+
+	return 0
+}
+
 func (p *widget) makeTileTextures() {
 	state := p.getState()
 	textureGroups := make([][]map[string]*giu.Texture, len(state.tileGroups))
@@ -562,20 +594,25 @@ func (p *widget) TileGroup() int32 {
 // SetTileGroup sets current tile group
 func (p *widget) SetTileGroup(tileGroup int32) {
 	state := p.getState()
-	if int(tileGroup) > len(state.tileGroups) {
-		tileGroup = int32(len(state.tileGroups))
-	} else if tileGroup < 0 {
+	if int(tileGroup) >= len(state.tileGroups) {
+		tileGroup = int32(len(state.tileGroups)) - 1
+	}
+
+	if tileGroup < 0 {
 		tileGroup = 0
 	}
 
 	state.TileGroup = tileGroup
 }
 
-func (p *widget) makeSubtileFlags(state *widgetState, tile *d2dt1.Tile) giu.Layout {
-	if tile.Height < 0 {
-		tile.Height *= -1
+// SetTileGroup sets current tile group of DT1 widget created by Create (e.g. to show a tile selected outside of the editor)
+func SetTileGroup(w giu.Widget, tileGroup int32) {
+	if p, ok := w.(*widget); ok {
+		p.SetTileGroup(tileGroup)
 	}
+}
 
+func (p *widget) makeSubtileFlags(state *widgetState, tile *d2dt1.Tile) giu.Layout {
 	const (
 		maxSubtileIndex = 7
 		spacerHeight    = 4 // px
@@ -585,8 +622,11 @@ func (p *widget) makeSubtileFlags(state *widgetState, tile *d2dt1.Tile) giu.Layo
 		giu.SliderInt("Subtile Type", &state.controls.SubtileFlag, 0, maxSubtileIndex),
 		giu.Label(subTileString(state.controls.SubtileFlag)),
 		p.makeSubTilePreview(tile, state),
-		giu.Dummy(gridMaxWidth, gridMaxHeight),
-		giu.Label("Click to Add/Remove flags"),
+		// catches clicks, so that dragging over the grid paints flags instead of moving the window
+		giu.InvisibleButton("##"+p.id+"subtileGrid").Size(gridMaxWidth, gridMaxHeight),
+		giu.Label("Click to add/remove the flag, drag to paint it over more subtiles"),
+		giu.Separator(),
+		p.makeFlagPatternLayout(state, tile),
 	}
 }
 
@@ -633,7 +673,7 @@ func (p *widget) makeSubTilePreview(tile *d2dt1.Tile, state *widgetState) giu.La
 
 					hasFlag := (flag & (1 << state.controls.SubtileFlag)) > 0
 
-					p.handleSubtileHoverAndClick(subtileIdx, flagPoint, canvas)
+					p.handleSubtileHoverAndClick(state, tile, subtileIdx, flagPoint, canvas)
 
 					if hasFlag {
 						const circleRadius = 3 // px
@@ -669,7 +709,8 @@ func (p *widget) makeSubTilePreview(tile *d2dt1.Tile, state *widgetState) giu.La
 	}
 }
 
-func (p *widget) handleSubtileHoverAndClick(subtileIdx int, flagPoint image.Point, canvas *giu.Canvas) {
+func (p *widget) handleSubtileHoverAndClick(state *widgetState, tile *d2dt1.Tile, subtileIdx int, flagPoint image.Point,
+	canvas *giu.Canvas) {
 	mousePos := giu.GetMousePos()
 	delta := mousePos.Sub(flagPoint)
 	dx, dy := int(math.Abs(float64(delta.X))), int(math.Abs(float64(delta.Y)))
@@ -686,9 +727,19 @@ func (p *widget) handleSubtileHoverAndClick(subtileIdx int, flagPoint image.Poin
 		canvas.AddLine(p3, p4, highlight, 1)
 	}
 
-	// on mouse release, toggle the flag
-	if closeEnough && giu.IsMouseReleased(giu.MouseButtonLeft) {
-		bit := p.getSubTileFieldToEdit(subtileIdx)
-		*bit = !(*bit)
+	bit := uint(state.controls.SubtileFlag)
+
+	// clicking a subtile toggles the flag, dragging paints the same value over other subtiles
+	if closeEnough && giu.IsMouseClicked(giu.MouseButtonLeft) {
+		state.painting = true
+		state.paintValue = !hsdt1.SubtileFlag(tile, subtileIdx, bit)
+	}
+
+	if !giu.IsMouseDown(giu.MouseButtonLeft) {
+		state.painting = false
+	}
+
+	if closeEnough && state.painting {
+		hsdt1.SetSubtileFlag(tile, subtileIdx, bit, state.paintValue)
 	}
 }
@@ -0,0 +1,177 @@
+package fonttablewidget
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsfont"
+)
+
+const (
+	buildPathW      = 300
+	defaultLastRune = 255
+)
+
+// charSource describes where characters assigned to DC6 frames come from
+type charSource int32
+
+const (
+	charSourceRange charSource = iota
+	charSourceFile
+)
+
+// buildState represents a state of "build from DC6" form
+type buildState struct {
+	SpritePath  string
+	Source      charSource
+	FirstRune   int32
+	LastRune    int32
+	CharsPath   string
+	FirstFrame  int32
+	OnlyMissing bool
+	message     string
+}
+
+// Dispose resets build state
+func (b *buildState) Dispose() {
+	b.message = ""
+}
+
+func (p *widget) makeBuildLayout() giu.Layout {
+	state := p.getState()
+	bs := &state.BuildState
+
+	if bs.FirstRune == 0 && bs.LastRune == 0 {
+		bs.FirstRune, bs.LastRune = ' ', defaultLastRune
+	}
+
+	sourceLayout := giu.Layout{
+		giu.Row(
+			giu.Label("First character (code): "),
+			giu.InputInt("##"+p.id+"buildFirstRune", &bs.FirstRune).Size(inputIntW),
+			giu.Label(fmt.Sprintf("%q", bs.FirstRune)),
+		),
+		giu.Row(
+			giu.Label("Last character (code):  "),
+			giu.InputInt("##"+p.id+"buildLastRune", &bs.LastRune).Size(inputIntW),
+			giu.Label(fmt.Sprintf("%q", bs.LastRune)),
+		),
+	}
+
+	if bs.Source == charSourceFile {
+		sourceLayout = giu.Layout{
+			giu.Row(
+				giu.Label("Characters file: "),
+				giu.InputText("##"+p.id+"buildCharsPath", &bs.CharsPath).Size(buildPathW),
+				giu.Button("...##"+p.id+"buildBrowseChars").OnClick(func() {
+					p.browse(&bs.CharsPath, "Select a text file with characters", "Text file", "txt")
+				}),
+			),
+		}
+	}
+
+	return giu.Layout{
+		giu.Label("Assigns DC6 frames (one frame per glyph) to characters and measures glyph sizes."),
+		giu.Row(
+			giu.Label("DC6 sprite: "),
+			giu.InputText("##"+p.id+"buildSpritePath", &bs.SpritePath).Size(buildPathW),
+			giu.Button("...##"+p.id+"buildBrowseSprite").OnClick(func() {
+				p.browse(&bs.SpritePath, "Select font's DC6 sprite", "DC6 sprite", "dc6")
+			}),
+		),
+		giu.Row(
+			giu.RadioButton("Character range##"+p.id+"buildSourceRange", bs.Source == charSourceRange).OnChange(func() {
+				bs.Source = charSourceRange
+			}),
+			giu.RadioButton("Characters from file##"+p.id+"buildSourceFile", bs.Source == charSourceFile).OnChange(func() {
+				bs.Source = charSourceFile
+			}),
+		),
+		sourceLayout,
+		giu.Row(
+			giu.Label("First frame: "),
+			giu.InputInt("##"+p.id+"buildFirstFrame", &bs.FirstFrame).Size(inputIntW),
+		),
+		giu.Checkbox("Update only missing glyphs##"+p.id+"buildOnlyMissing", &bs.OnlyMissing),
+		giu.Label(bs.message),
+		giu.Separator(),
+		giu.Row(
+			giu.Button("Build##"+p.id+"buildApply").Size(saveCancelW, saveCancelH).OnClick(func() {
+				count, err := p.buildFromDC6()
+				if err != nil {
+					bs.message = err.Error()
+
+					return
+				}
+
+				bs.message = ""
+				state.Mode = modeViewer
+
+				dialog.Message("%d glyphs created or updated", count).Info()
+			}),
+			giu.Button("Cancel##"+p.id+"buildCancel").Size(saveCancelW, saveCancelH).OnClick(func() {
+				state.Mode = modeViewer
+			}),
+		),
+	}
+}
+
+func (p *widget) browse(path *string, title, filterDesc string, ext ...string) {
+	filePath, err := dialog.File().Title(title).Filter(filterDesc, ext...).Load()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	*path = filePath
+}
+
+// buildFromDC6 fills font table with glyphs of DC6 sprite
+func (p *widget) buildFromDC6() (int, error) {
+	bs := &p.getState().BuildState
+
+	if bs.SpritePath == "" {
+		return 0, errors.New("select a DC6 sprite first")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(bs.SpritePath))
+	if err != nil {
+		return 0, fmt.Errorf("error reading DC6 sprite: %w", err)
+	}
+
+	sprite, err := d2dc6.Load(data)
+	if err != nil {
+		return 0, fmt.Errorf("error loading DC6 sprite: %w", err)
+	}
+
+	var chars []rune
+
+	switch bs.Source {
+	case charSourceRange:
+		chars, err = hsfont.CharRange(bs.FirstRune, bs.LastRune)
+		if err != nil {
+			return 0, fmt.Errorf("error building font table: %w", err)
+		}
+	case charSourceFile:
+		text, readErr := ioutil.ReadFile(filepath.Clean(bs.CharsPath))
+		if readErr != nil {
+			return 0, fmt.Errorf("error reading characters file: %w", readErr)
+		}
+
+		chars = hsfont.CharsFromText(string(text))
+	}
+
+	count, err := hsfont.BuildGlyphs(p.fontTable, sprite, chars, int(bs.FirstFrame), bs.OnlyMissing)
+	if err != nil {
+		return 0, fmt.Errorf("error building font table: %w", err)
+	}
+
+	return count, nil
+}
@@ -14,12 +14,14 @@ const (
 	modeViewer widgetMode = iota
 	modeEditRune
 	modeAddItem
+	modeBuildFromDC6
 )
 
 type widgetState struct {
 	Mode                widgetMode
 	EditRuneState       editRuneState
 	AddItemState        addItemState
+	BuildState          buildState
 	deleteButtonTexture *giu.Texture
 }
 
@@ -27,6 +29,7 @@ type widgetState struct {
 func (s *widgetState) Dispose() {
 	s.EditRuneState.Dispose()
 	s.AddItemState.Dispose()
+	s.BuildState.Dispose()
 }
 
 type editRuneState struct {
@@ -19,6 +19,7 @@ const (
 	inputIntW                = 30
 	delSize                  = 20
 	addW, addH               = 400, 30
+	buildW                   = 150
 	editRuneW, editRuneH     = 50, 30
 	saveCancelW, saveCancelH = 80, 30
 )
@@ -62,6 +63,8 @@ func (p *widget) Build() {
 		p.makeEditRuneLayout().Build()
 	case modeAddItem:
 		p.makeAddItemLayout().Build()
+	case modeBuildFromDC6:
+		p.makeBuildLayout().Build()
 	}
 }
 
@@ -100,9 +103,15 @@ func (p *widget) makeTableLayout() giu.Layout {
 	}
 
 	return giu.Layout{
-		giu.Button("Add new glyph...##"+p.id+"addItem").Size(addW, addH).OnClick(func() {
-			state.Mode = modeAddItem
-		}),
+		giu.Row(
+			giu.Button("Add new glyph...##"+p.id+"addItem").Size(addW, addH).OnClick(func() {
+				state.Mode = modeAddItem
+			}),
+			giu.Button("Build from DC6...##"+p.id+"buildFromDC6").Size(buildW, addH).OnClick(func() {
+				state.BuildState.message = ""
+				state.Mode = modeBuildFromDC6
+			}),
+		),
 		giu.Separator(),
 		giu.Child("##" + p.id + "tableArea").Border(false).Layout(giu.Layout{
 			giu.Table("##" + p.id + "table").FastMode(true).Rows(rows...),
@@ -0,0 +1,4 @@
+// Package paletteremapwidget provides a palette remapping panel shared by sprite editors
+// (DC6, DCC): it maps colors of the current palette to the nearest colors of another one
+// (keeping locked indices) and previews a frame before and after remapping.
+package paletteremapwidget
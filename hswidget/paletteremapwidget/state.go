@@ -0,0 +1,43 @@
+package paletteremapwidget
+
+import (
+	"fmt"
+
+	"github.com/ianling/giu"
+)
+
+const defaultLocked = "0"
+
+type widgetState struct {
+	Locked string
+	Frame  int32
+
+	// cache - will not be saved
+	previewKey    string
+	table         *[256]byte
+	before, after *giu.Texture
+	width, height int
+	message       string
+}
+
+// Dispose cleans remap widget's state
+func (s *widgetState) Dispose() {
+	s.previewKey = ""
+	s.table = nil
+	s.before, s.after = nil, nil
+}
+
+func (p *Widget) getStateID() string {
+	return fmt.Sprintf("widget_%s", p.id)
+}
+
+func (p *Widget) getState() *widgetState {
+	if s := giu.Context.GetState(p.getStateID()); s != nil {
+		return s.(*widgetState)
+	}
+
+	state := &widgetState{Locked: defaultLocked}
+	giu.Context.SetState(p.getStateID(), state)
+
+	return state
+}
@@ -0,0 +1,182 @@
+package paletteremapwidget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+)
+
+const (
+	lockedW          = 200
+	sliderW          = 200
+	buttonW, buttonH = 100, 30
+	// previews are scaled up to maxPreviewScale, but not over maxPreviewSize pixels
+	maxPreviewSize  = 192
+	maxPreviewScale = 2
+	maxAlpha        = 0xff
+)
+
+// Image is an indexed image (palette index 0 is transparent)
+type Image struct {
+	Width, Height int
+	Indices       []byte
+}
+
+// Widget is a palette remapping panel
+type Widget struct {
+	id             string
+	textureLoader  hscommon.TextureLoader
+	source, target *[256]d2interface.Color
+	frameCount     int
+	frame          func(idx int) Image
+	onApply        func(table *[256]byte)
+	onCancel       func()
+}
+
+// Create creates a widget, which remaps colors of source palette to target one; frame returns
+// a frame (of frameCount frames) to preview
+func Create(tl hscommon.TextureLoader, id string, source, target *[256]d2interface.Color,
+	frameCount int, frame func(idx int) Image) *Widget {
+	return &Widget{
+		id:            id,
+		textureLoader: tl,
+		source:        source,
+		target:        target,
+		frameCount:    frameCount,
+		frame:         frame,
+	}
+}
+
+// OnApply sets a callback, which remaps sprite's pixels with the table given
+func (p *Widget) OnApply(cb func(table *[256]byte)) *Widget {
+	p.onApply = cb
+
+	return p
+}
+
+// OnCancel sets a callback, which is called when remapping is canceled
+func (p *Widget) OnCancel(cb func()) *Widget {
+	p.onCancel = cb
+
+	return p
+}
+
+// Build builds a widget
+func (p *Widget) Build() {
+	state := p.getState()
+
+	if state.Frame >= int32(p.frameCount) {
+		state.Frame = 0
+	}
+
+	p.updatePreview(state)
+
+	w, h := previewSize(state.width, state.height)
+
+	giu.Layout{
+		giu.Label("Remap colors to the nearest colors of the selected palette"),
+		giu.Row(
+			giu.Label("Locked indices:"),
+			giu.InputText("##"+p.id+"locked", &state.Locked).Size(lockedW),
+			giu.Label("(e.g. 0, 240-255)"),
+		),
+		giu.Custom(func() {
+			if p.frameCount > 1 {
+				giu.SliderInt("Preview frame##"+p.id+"frame", &state.Frame, 0, int32(p.frameCount-1)).Size(sliderW).Build()
+			}
+		}),
+		giu.Row(
+			giu.Column(giu.Label("Before"), giu.Image(state.before).Size(w, h)),
+			giu.Column(giu.Label("After"), giu.Image(state.after).Size(w, h)),
+		),
+		giu.Label(state.message),
+		giu.Row(
+			giu.Button("Apply##"+p.id+"apply").Size(buttonW, buttonH).OnClick(func() {
+				if state.table != nil && p.onApply != nil {
+					p.onApply(state.table)
+				}
+			}),
+			giu.Button("Cancel##"+p.id+"cancel").Size(buttonW, buttonH).OnClick(func() {
+				if p.onCancel != nil {
+					p.onCancel()
+				}
+			}),
+		),
+	}.Build()
+}
+
+func previewSize(width, height int) (w, h float32) {
+	largest := width
+	if height > largest {
+		largest = height
+	}
+
+	scale := float32(maxPreviewScale)
+	if float32(largest)*scale > maxPreviewSize {
+		scale = maxPreviewSize / float32(largest)
+	}
+
+	return float32(width) * scale, float32(height) * scale
+}
+
+// updatePreview recalculates remap table and preview textures when locked indices,
+// preview frame or palettes change
+func (p *Widget) updatePreview(state *widgetState) {
+	key := fmt.Sprintf("%s|%d|%p|%p", state.Locked, state.Frame, p.source, p.target)
+	if key == state.previewKey {
+		return
+	}
+
+	state.previewKey = key
+	state.table = nil
+
+	locked, err := hspalette.ParseIndexRanges(state.Locked)
+	if err != nil {
+		state.message = err.Error()
+
+		return
+	}
+
+	state.message = ""
+	state.table = hspalette.RemapTable(hspalette.Colors(p.source), hspalette.Colors(p.target), locked)
+
+	if p.frameCount == 0 {
+		return
+	}
+
+	frame := p.frame(int(state.Frame))
+	state.width, state.height = frame.Width, frame.Height
+
+	remapped := append([]byte{}, frame.Indices...)
+	hspalette.Remap(remapped, state.table)
+
+	p.textureLoader.CreateTextureFromARGB(toRGBA(frame.Width, frame.Height, frame.Indices, p.source), func(t *giu.Texture) {
+		state.before = t
+	})
+
+	p.textureLoader.CreateTextureFromARGB(toRGBA(frame.Width, frame.Height, remapped, p.target), func(t *giu.Texture) {
+		state.after = t
+	})
+}
+
+func toRGBA(width, height int, indices []byte, palette *[256]d2interface.Color) *image.RGBA {
+	result := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for idx, c := range indices {
+		if c == 0 {
+			continue
+		}
+
+		col := palette[c]
+		result.Set(idx%width, idx/width, color.RGBA{R: col.R(), G: col.G(), B: col.B(), A: maxAlpha})
+	}
+
+	return result
+}
@@ -0,0 +1,186 @@
+package stringtablewidget
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hstbl"
+)
+
+const (
+	newFileMode    = 0o644
+	previewTableH  = 150
+	previewKeyColW = 200
+)
+
+// importState represents a string table, which is going to be merged
+type importState struct {
+	imported      d2tbl.TextDictionary
+	changes       *hstbl.Changes
+	removeMissing bool
+}
+
+func (p *widget) onExportClicked() {
+	filePath, err := dialog.File().Title("Export string table").
+		Filter("CSV", "csv").Filter("JSON", "json").Filter("gettext PO", "po").Save()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	if err := p.exportToFile(filePath); err != nil {
+		dialog.Message("%v", err).Error()
+	}
+}
+
+func (p *widget) exportToFile(filePath string) error {
+	format, err := hstbl.FormatFromExtension(filepath.Ext(filePath))
+	if err != nil {
+		return fmt.Errorf("error exporting string table: %w", err)
+	}
+
+	data, err := hstbl.Export(p.dict, format)
+	if err != nil {
+		return fmt.Errorf("error exporting string table: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(filePath), data, os.FileMode(newFileMode)); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+func (p *widget) onImportClicked() {
+	filePath, err := dialog.File().Title("Import string table").
+		Filter("CSV, JSON, gettext PO", "csv", "json", "po").Load()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	if err := p.importFromFile(filePath); err != nil {
+		dialog.Message("%v", err).Error()
+	}
+}
+
+func (p *widget) importFromFile(filePath string) error {
+	state := p.getState()
+
+	format, err := hstbl.FormatFromExtension(filepath.Ext(filePath))
+	if err != nil {
+		return fmt.Errorf("error importing string table: %w", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	imported, err := hstbl.Import(data, format, hstbl.DetectEncoding(p.dict))
+	if err != nil {
+		return fmt.Errorf("error importing string table: %w", err)
+	}
+
+	state.importState = importState{
+		imported: imported,
+		changes:  hstbl.Compare(p.dict, imported),
+	}
+
+	state.Mode = widgetModeImportPreview
+
+	return nil
+}
+
+func (p *widget) buildImportPreviewLayout() {
+	state := p.getState()
+	changes := state.changes
+
+	if changes == nil {
+		state.Mode = widgetModeViewer
+
+		return
+	}
+
+	if changes.Empty() {
+		giu.Layout{
+			giu.Label("Imported table is the same as the current one."),
+			giu.Button("OK##"+p.id+"importOK").Size(actionButtonW, actionButtonH).OnClick(func() {
+				state.importState = importState{}
+				state.Mode = widgetModeViewer
+			}),
+		}.Build()
+
+		return
+	}
+
+	giu.Layout{
+		giu.Label(fmt.Sprintf("Added (%d):", len(changes.Added))),
+		p.makePreviewTable("added", changes.Added, false),
+		giu.Label(fmt.Sprintf("Changed (%d):", len(changes.Changed))),
+		p.makePreviewTable("changed", changes.Changed, true),
+		giu.Label(fmt.Sprintf("Missing in imported file (%d):", len(changes.Removed))),
+		p.makePreviewTable("removed", changes.Removed, false),
+		giu.Checkbox("Remove keys missing in imported file##"+p.id+"importRemove", &state.removeMissing),
+		giu.Separator(),
+		giu.Row(
+			giu.Button("Apply##"+p.id+"importApply").Size(actionButtonW, actionButtonH).OnClick(func() {
+				changes.Apply(p.dict, state.imported, state.removeMissing)
+				state.importState = importState{}
+				p.reloadMapValues()
+				state.Mode = widgetModeViewer
+			}),
+			giu.Button("Cancel##"+p.id+"importCancel").Size(actionButtonW, actionButtonH).OnClick(func() {
+				state.importState = importState{}
+				state.Mode = widgetModeViewer
+			}),
+		),
+	}.Build()
+}
+
+// makePreviewTable creates a table of keys; if showOld is true, current value is displayed next to the imported one
+func (p *widget) makePreviewTable(id string, keys []string, showOld bool) giu.Widget {
+	state := p.getState()
+
+	if len(keys) == 0 {
+		return giu.Label("(none)")
+	}
+
+	rows := make([]*giu.TableRowWidget, len(keys))
+
+	for idx, key := range keys {
+		value, found := state.imported[key]
+		if !found {
+			value = p.dict[key]
+		}
+
+		cells := []giu.Widget{giu.Label(key)}
+
+		if showOld {
+			cells = append(cells, giu.Style().SetColor(imgui.StyleColorText, missingColor()).To(giu.Label(p.dict[key])))
+		}
+
+		cells = append(cells, giu.Label(value))
+		rows[idx] = giu.TableRow(cells...)
+	}
+
+	columns := []*giu.TableColumnWidget{
+		giu.TableColumn("key").Flags(imgui.TableColumnFlagsWidthFixed).InnerWidthOrWeight(previewKeyColW),
+	}
+	if showOld {
+		columns = append(columns, giu.TableColumn("current value"))
+	}
+
+	columns = append(columns, giu.TableColumn("value"))
+
+	return giu.Child("##"+p.id+"importPreview"+id).Border(true).Size(0, previewTableH).Layout(giu.Layout{
+		giu.Table("##"+p.id+"importPreviewTable"+id).FastMode(true).Freeze(0, 1).Columns(columns...).Rows(rows...),
+	})
+}
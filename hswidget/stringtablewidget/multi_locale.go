@@ -0,0 +1,204 @@
+package stringtablewidget
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsenum"
+)
+
+const (
+	localeValueW       = 250
+	addMissingW        = 40
+	keyColumnW         = 200
+	localeFilterComboW = 200
+)
+
+type localeFilter int32
+
+const (
+	localeFilterAll localeFilter = iota
+	localeFilterMissing
+	localeFilterUntranslated
+)
+
+// LocaleTable represents a string table of a single locale
+type LocaleTable struct {
+	Locale hsenum.Locale
+	Dict   d2tbl.TextDictionary
+	// Changed is set, when table is edited in widget
+	Changed bool
+}
+
+func missingColor() color.RGBA {
+	return color.RGBA{R: 255, G: 80, B: 80, A: 255}
+}
+
+func untranslatedColor() color.RGBA {
+	return color.RGBA{R: 255, G: 200, B: 0, A: 255}
+}
+
+// isMissing returns true, if key isn't present in table of locale given
+func (p *widget) isMissing(locale *LocaleTable, key string) bool {
+	_, found := locale.Dict[key]
+
+	return !found
+}
+
+// isUntranslated returns true, if value of key in locale given is the same as in reference table
+// (the table, which was opened)
+func (p *widget) isUntranslated(locale *LocaleTable, key string) bool {
+	if locale == p.locales[0] {
+		return false
+	}
+
+	value, found := locale.Dict[key]
+	if !found || value == "" {
+		return false
+	}
+
+	return value == p.dict[key]
+}
+
+func (p *widget) generateMultiLocaleKeys() (keys []string) {
+	state := p.getState()
+
+	search := strings.ToLower(state.Search)
+
+	for _, key := range state.allKeys {
+		if search != "" && !p.localesContain(key, search) {
+			continue
+		}
+
+		if state.LocaleFilter == localeFilterAll {
+			keys = append(keys, key)
+
+			continue
+		}
+
+		for _, locale := range p.locales {
+			if (state.LocaleFilter == localeFilterMissing && p.isMissing(locale, key)) ||
+				(state.LocaleFilter == localeFilterUntranslated && p.isUntranslated(locale, key)) {
+				keys = append(keys, key)
+
+				break
+			}
+		}
+	}
+
+	return keys
+}
+
+// localesContain returns true if key or any of locale values contains (lowercase) string s
+func (p *widget) localesContain(key, s string) bool {
+	if strings.Contains(strings.ToLower(key), s) {
+		return true
+	}
+
+	for _, locale := range p.locales {
+		if strings.Contains(strings.ToLower(locale.Dict[key]), s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *widget) buildMultiLocaleLayout() {
+	state := p.getState()
+
+	if len(state.allKeys) < len(state.keys) {
+		p.reloadMapValues()
+	}
+
+	keys := p.generateMultiLocaleKeys()
+
+	rows := make([]*giu.TableRowWidget, len(keys)+1)
+
+	columnWidgets := []giu.Widget{giu.Label("key")}
+	for _, locale := range p.locales {
+		columnWidgets = append(columnWidgets, giu.Label(locale.Locale.String()))
+	}
+
+	rows[0] = giu.TableRow(columnWidgets...)
+
+	for keyIdx, key := range keys {
+		rows[keyIdx+1] = p.makeMultiLocaleTableRow(key)
+	}
+
+	filter := int32(state.LocaleFilter)
+	filters := []string{"all keys", "missing keys", "untranslated keys"}
+
+	giu.Layout{
+		giu.Row(
+			giu.Label("Search:"),
+			giu.InputText("##"+p.id+"localesSearch", &state.Search),
+			giu.Label("Show:"),
+			giu.Combo("##"+p.id+"localeFilter", filters[filter], filters, &filter).
+				Size(localeFilterComboW).OnChange(func() {
+				state.LocaleFilter = localeFilter(filter)
+			}),
+		),
+		giu.Row(
+			giu.Style().SetColor(imgui.StyleColorText, missingColor()).To(giu.Label("missing")),
+			giu.Style().SetColor(imgui.StyleColorText, untranslatedColor()).To(giu.Label("untranslated")),
+		),
+		giu.Separator(),
+		giu.Custom(func() {
+			if len(keys) == 0 {
+				giu.Label("Nothing to display.").Build()
+
+				return
+			}
+
+			giu.Child("##"+p.id+"localesTableArea").Border(false).
+				Size(float32(keyColumnW+len(p.locales)*localeValueW), 0).Layout(giu.Layout{
+				giu.Table("##"+p.id+"localesTable").FastMode(true).Freeze(1, 1).Rows(rows...),
+			}).Build()
+		}),
+	}.Build()
+}
+
+func (p *widget) makeMultiLocaleTableRow(key string) *giu.TableRowWidget {
+	cells := []giu.Widget{giu.Label(key)}
+
+	for _, locale := range p.locales {
+		cells = append(cells, p.makeLocaleCell(locale, key))
+	}
+
+	return giu.TableRow(cells...)
+}
+
+func (p *widget) makeLocaleCell(locale *LocaleTable, key string) giu.Widget {
+	id := "##" + p.id + "locale" + locale.Locale.Code() + key
+
+	if p.isMissing(locale, key) {
+		return giu.Row(
+			giu.Style().SetColor(imgui.StyleColorText, missingColor()).To(giu.Label("(missing)")),
+			giu.Button("add"+id+"add").Size(addMissingW, 0).OnClick(func() {
+				// new entries start with reference value, so they're marked as untranslated
+				locale.Dict[key] = p.dict[key]
+				locale.Changed = true
+				p.reloadMapValues()
+			}),
+		)
+	}
+
+	value := locale.Dict[key]
+
+	input := giu.InputText(id, &value).Size(localeValueW).OnChange(func() {
+		locale.Dict[key] = value
+		locale.Changed = true
+	})
+
+	if p.isUntranslated(locale, key) {
+		return giu.Style().SetColor(imgui.StyleColorText, untranslatedColor()).To(input)
+	}
+
+	return input
+}
@@ -0,0 +1,124 @@
+package stringtablewidget
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hstbl"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
+)
+
+const (
+	previewH         = 150
+	previewWrapW     = 80
+	previewMaxWrap   = 200
+	previewBgOpacity = 255
+)
+
+// previewState represents the state of colored text preview
+type previewState struct {
+	PreviewKey string
+	// PreviewTopDown is true, when lines should be printed in order they're stored
+	// (by default D2 prints multi-line item texts bottom-up)
+	PreviewTopDown bool
+	// PreviewWrap is a maximal number of characters in line (0 means no wrapping)
+	PreviewWrap int32
+}
+
+func (ps *previewState) Dispose() {
+	ps.PreviewKey = ""
+}
+
+// previewLines returns lines of value in order they'd be printed
+func (p *widget) previewLines(value string) (lines []hstbl.TextLine, warnings []string) {
+	state := p.getState()
+
+	lines, warnings = hstbl.ParseColorCodes(value)
+
+	if state.PreviewWrap > 0 {
+		wrapped := make([]string, 0)
+
+		for _, line := range strings.Split(value, "\n") {
+			if line == "" {
+				wrapped = append(wrapped, line)
+
+				continue
+			}
+
+			wrapped = append(wrapped, hsutil.SplitIntoLinesWithMaxWidth(line, int(state.PreviewWrap))...)
+		}
+
+		// color is kept across wrapped lines the same way as across stored ones
+		lines, _ = hstbl.ParseColorCodes(strings.Join(wrapped, "\n"))
+	}
+
+	if !state.PreviewTopDown {
+		for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+			lines[i], lines[j] = lines[j], lines[i]
+		}
+	}
+
+	return lines, warnings
+}
+
+// makePreview creates a pane, which renders value the way the game would print it
+func (p *widget) makePreview(value string) giu.Layout {
+	state := p.getState()
+
+	lines, warnings := p.previewLines(value)
+
+	return giu.Layout{
+		giu.Row(
+			giu.Checkbox("top-down##"+p.id+"previewTopDown", &state.PreviewTopDown),
+			giu.Label("wrap after (chars, 0 = don't wrap):"),
+			giu.SliderInt("##"+p.id+"previewWrap", &state.PreviewWrap, 0, previewMaxWrap).Size(previewWrapW),
+		),
+		giu.Style().SetColor(imgui.StyleColorChildBg, color.RGBA{A: previewBgOpacity}).To(
+			giu.Child("##"+p.id+"preview").Border(true).Size(0, previewH).Layout(giu.Layout{
+				giu.Custom(func() {
+					for _, line := range lines {
+						buildCenteredLine(line)
+					}
+				}),
+			}),
+		),
+		giu.Custom(func() {
+			for _, warning := range warnings {
+				giu.Style().SetColor(imgui.StyleColorText, missingColor()).To(
+					giu.Label("Warning: " + warning),
+				).Build()
+			}
+		}),
+	}
+}
+
+// buildCenteredLine prints colored line centered, like D2 does it in item descriptions
+func buildCenteredLine(line hstbl.TextLine) {
+	const half = 2
+
+	width := imgui.CalcTextSize(line.String(), false, 0).X
+	if offset := (imgui.ContentRegionAvail().X - width) / half; offset > 0 {
+		imgui.SetCursorPos(imgui.Vec2{X: imgui.CursorPosX() + offset, Y: imgui.CursorPosY()})
+	}
+
+	if len(line) == 0 {
+		imgui.Text("")
+
+		return
+	}
+
+	for idx, span := range line {
+		if idx > 0 {
+			imgui.SameLineV(0, 0)
+		}
+
+		rgba, _ := span.Color.RGBA()
+
+		imgui.PushStyleColor(imgui.StyleColorText, giu.ToVec4Color(rgba))
+		imgui.Text(span.Text)
+		imgui.PopStyleColor()
+	}
+}
@@ -12,6 +12,7 @@ type widgetMode int32
 const (
 	widgetModeViewer widgetMode = iota
 	widgetModeAddEdit
+	widgetModeImportPreview
 )
 
 type widgetState struct {
@@ -20,12 +21,22 @@ type widgetState struct {
 	NumOnly bool
 	addEditState
 	Search string
+
+	importState
+	previewState
+
+	// allKeys are keys of all locales displayed
+	allKeys      []string
+	LocaleFilter localeFilter
 }
 
 func (ws *widgetState) Dispose() {
 	ws.Mode = widgetModeViewer
 	ws.keys = make([]string, 0)
+	ws.allKeys = make([]string, 0)
 	ws.addEditState.Dispose()
+	ws.importState = importState{}
+	ws.previewState.Dispose()
 	ws.Search = ""
 }
 
@@ -89,6 +100,30 @@ func (p *widget) reloadMapValues() {
 	sort.Strings(keys)
 
 	state.keys = keys
+
+	if len(p.locales) == 0 {
+		return
+	}
+
+	allKeys := make(map[string]bool)
+
+	for _, key := range keys {
+		allKeys[key] = true
+	}
+
+	for _, locale := range p.locales {
+		for key := range locale.Dict {
+			allKeys[key] = true
+		}
+	}
+
+	state.allKeys = make([]string, 0, len(allKeys))
+
+	for key := range allKeys {
+		state.allKeys = append(state.allKeys, key)
+	}
+
+	sort.Strings(state.allKeys)
 }
 
 func (p *widget) setState(s giu.Disposable) {
@@ -14,18 +14,23 @@ const (
 	deleteW, deleteH             = 50, 25
 	addEditW, addEditH           = 200, 30
 	actionButtonW, actionButtonH = 100, 30
+	// previewAreaH is a height of preview pane including its options and warnings
+	previewAreaH = 250
 )
 
 type widget struct {
-	id   string
-	dict d2tbl.TextDictionary
+	id      string
+	dict    d2tbl.TextDictionary
+	locales []*LocaleTable
 }
 
-// Create creates a new string table editor widget
-func Create(state []byte, id string, dict d2tbl.TextDictionary) giu.Widget {
+// Create creates a new string table editor widget.
+// If locales are given, table is displayed side-by-side with the same tables of other locales
+func Create(state []byte, id string, dict d2tbl.TextDictionary, locales ...*LocaleTable) giu.Widget {
 	result := &widget{
-		id:   id,
-		dict: dict,
+		id:      id,
+		dict:    dict,
+		locales: locales,
 	}
 
 	if giu.Context.GetState(result.getStateID()) == nil && state != nil {
@@ -45,9 +50,17 @@ func (p *widget) Build() {
 
 	switch state.Mode {
 	case widgetModeViewer:
+		if len(p.locales) > 0 {
+			p.buildMultiLocaleLayout()
+
+			return
+		}
+
 		p.buildTableLayout()
 	case widgetModeAddEdit:
 		p.buildAddEditLayout()
+	case widgetModeImportPreview:
+		p.buildImportPreviewLayout()
 	}
 }
 
@@ -73,11 +86,14 @@ func (p *widget) buildTableLayout() {
 	}
 
 	giu.Layout{
-		giu.Button("Add/Edit record##"+p.id+"addEditRecord").
-			Size(addEditW, addEditH).OnClick(func() {
-			state.Editable = true
-			state.Mode = widgetModeAddEdit
-		}),
+		giu.Row(
+			giu.Button("Add/Edit record##"+p.id+"addEditRecord").
+				Size(addEditW, addEditH).OnClick(func() {
+				state.Editable = true
+				state.Mode = widgetModeAddEdit
+			}),
+			p.makeImportExportButtons(),
+		),
 		giu.Separator(),
 		p.makeSearchSection(),
 		giu.Separator(),
@@ -87,20 +103,45 @@ func (p *widget) buildTableLayout() {
 
 				return
 			}
+
+			value, showPreview := p.dict[state.PreviewKey]
+
+			tableH := float32(0)
+			if showPreview {
+				tableH = -previewAreaH
+			}
+
 			giu.Layout{
-				giu.Child("##" + p.id + "tableArea").Border(false).Layout(giu.Layout{
+				giu.Child("##"+p.id+"tableArea").Border(false).Size(0, tableH).Layout(giu.Layout{
 					giu.Table("##" + p.id + "table").FastMode(true).Rows(rows...),
 				}),
+				giu.Custom(func() {
+					if showPreview {
+						giu.Layout{
+							giu.Label("Preview of " + state.PreviewKey + ":"),
+							p.makePreview(value),
+						}.Build()
+					}
+				}),
 			}.Build()
 		}),
 	}.Build()
 }
 
+func (p *widget) makeImportExportButtons() giu.Widget {
+	return giu.Row(
+		giu.Button("Import...##"+p.id+"import").Size(actionButtonW, addEditH).OnClick(p.onImportClicked),
+		giu.Button("Export...##"+p.id+"export").Size(actionButtonW, addEditH).OnClick(p.onExportClicked),
+	)
+}
+
 func (p *widget) makeTableRow(key string) *giu.TableRowWidget {
 	state := p.getState()
 
 	return giu.TableRow(
-		giu.Label(key),
+		giu.Selectable(key+"##"+p.id+"selectKey"+key).Selected(state.PreviewKey == key).OnClick(func() {
+			state.PreviewKey = key
+		}),
 		giu.Label(p.dict[key]),
 		giu.Row(
 			giu.Button("delete##"+p.id+"deleteString"+key).Size(deleteW, deleteH).OnClick(func() {
@@ -158,6 +199,8 @@ func (p *widget) buildAddEditLayout() {
 		}),
 		giu.Label("Value:"),
 		giu.InputTextMultiline("##"+p.id+"addEditValue", &state.Value),
+		giu.Label("Preview:"),
+		p.makePreview(state.Value),
 		giu.Separator(),
 		giu.Row(
 			giu.Custom(func() {
@@ -0,0 +1,283 @@
+// Package hsfontimportdialog contains font import dialog's data
+package hsfontimportdialog
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png" // register png decoder, BMFont pages are png images
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	g "github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsfont"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hsdialog"
+)
+
+const (
+	mainWindowW, mainWindowH = 420, 330
+	textboxSize              = 300
+	inputIntW                = 60
+	btnW, btnH               = 30, 0
+	charsH                   = 60
+	defaultFontSize          = 16
+	defaultFirstChar         = ' '
+	defaultLastChar          = 0xff
+	maxFontSize              = 200
+)
+
+// FontImportDialog represents a dialog, which converts TrueType/OpenType or BMFont fonts into D2 fonts
+type FontImportDialog struct {
+	*hsdialog.Dialog
+
+	onImported func(font *hsfont.Font)
+
+	sourcePath  string
+	fontSize    int32
+	useChars    bool
+	firstChar   int32
+	lastChar    int32
+	chars       string
+	palettePath string
+	outputDir   string
+	name        string
+	message     string
+}
+
+// Create creates a new font import dialog; onImported is called when font files are written
+func Create(onImported func(font *hsfont.Font)) *FontImportDialog {
+	result := &FontImportDialog{
+		Dialog:     hsdialog.New("Import Font"),
+		onImported: onImported,
+		fontSize:   defaultFontSize,
+		firstChar:  defaultFirstChar,
+		lastChar:   defaultLastChar,
+	}
+
+	return result
+}
+
+// Show shows font import dialog; imported files are written to outputDir by default
+func (p *FontImportDialog) Show(outputDir string) {
+	p.outputDir = outputDir
+	p.message = ""
+
+	p.Dialog.Show()
+}
+
+func isTrueType(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	return ext == ".ttf" || ext == ".otf"
+}
+
+// Build builds a font import dialog
+func (p *FontImportDialog) Build() {
+	p.IsOpen(&p.Visible).Layout(
+		g.Child("FontImportLayout").Size(mainWindowW, mainWindowH).Layout(
+			g.Label("Source font (TTF, OTF or BMFont .fnt)"),
+			g.Row(
+				g.InputText("##FontImportSource", &p.sourcePath).Size(textboxSize),
+				g.Button("...##FontImportSourceBrowse").Size(btnW, btnH).OnClick(p.onBrowseSourceClicked),
+			),
+			g.Custom(func() {
+				if !isTrueType(p.sourcePath) {
+					return
+				}
+
+				g.Layout{
+					g.Row(
+						g.Label("Size (px):"),
+						g.InputInt("##FontImportSize", &p.fontSize).Size(inputIntW),
+					),
+					g.Checkbox("Use characters listed below instead of a range##FontImportUseChars", &p.useChars),
+					g.Custom(func() {
+						if p.useChars {
+							g.InputTextMultiline("##FontImportChars", &p.chars).Size(-1, charsH).Build()
+
+							return
+						}
+
+						g.Row(
+							g.Label("Characters from"),
+							g.InputInt("##FontImportFirstChar", &p.firstChar).Size(inputIntW),
+							g.Label("to"),
+							g.InputInt("##FontImportLastChar", &p.lastChar).Size(inputIntW),
+						).Build()
+					}),
+				}.Build()
+			}),
+			g.Separator(),
+			g.Label("Palette (PL2)"),
+			g.Row(
+				g.InputText("##FontImportPalette", &p.palettePath).Size(textboxSize),
+				g.Button("...##FontImportPaletteBrowse").Size(btnW, btnH).OnClick(p.onBrowsePaletteClicked),
+			),
+			g.Label("Output directory"),
+			g.Row(
+				g.InputText("##FontImportOutputDir", &p.outputDir).Size(textboxSize),
+				g.Button("...##FontImportOutputDirBrowse").Size(btnW, btnH).OnClick(p.onBrowseOutputDirClicked),
+			),
+			g.Label("Font name (creates <name>.dc6, <name>.tbl and <name>.hsf)"),
+			g.InputText("##FontImportName", &p.name).Size(textboxSize),
+			g.Custom(func() {
+				if p.message == "" {
+					return
+				}
+
+				g.Style().SetColor(imgui.StyleColorText, color.RGBA{R: 0xff, G: 0x50, B: 0x50, A: 0xff}).To(
+					g.Label(p.message).Wrapped(true),
+				).Build()
+			}),
+		),
+		g.Row(
+			g.Button("Import##FontImportImport").OnClick(p.onImportClicked),
+			g.Button("Cancel##FontImportCancel").OnClick(p.onCancelClicked),
+		),
+	).Build()
+}
+
+func (p *FontImportDialog) onBrowseSourceClicked() {
+	filePath, err := dialog.File().Title("Select a font").
+		Filter("TrueType/OpenType or BMFont", "ttf", "otf", "fnt").Load()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	p.sourcePath = filePath
+
+	if p.name == "" {
+		p.name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+}
+
+func (p *FontImportDialog) onBrowsePaletteClicked() {
+	filePath, err := dialog.File().Title("Select a palette").Filter("PL2 palette", "pl2").Load()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	p.palettePath = filePath
+}
+
+func (p *FontImportDialog) onBrowseOutputDirClicked() {
+	path, err := dialog.Directory().Browse()
+	if err != nil || path == "" {
+		return
+	}
+
+	p.outputDir = path
+}
+
+func (p *FontImportDialog) onCancelClicked() {
+	p.Visible = false
+}
+
+func (p *FontImportDialog) onImportClicked() {
+	font, err := p.importFont()
+	if err != nil {
+		p.message = err.Error()
+
+		return
+	}
+
+	p.message = ""
+	p.Visible = false
+
+	p.onImported(font)
+}
+
+// loadGlyphs rasterizes (or reads) glyphs of the source font
+func (p *FontImportDialog) loadGlyphs() ([]hsfont.SourceGlyph, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(p.sourcePath))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read font: %w", err)
+	}
+
+	if !isTrueType(p.sourcePath) {
+		dir := filepath.Dir(p.sourcePath)
+
+		glyphs, err := hsfont.LoadBMFont(data, func(fileName string) (image.Image, error) {
+			f, err := os.Open(filepath.Join(dir, filepath.Clean(fileName)))
+			if err != nil {
+				return nil, fmt.Errorf("cannot open page: %w", err)
+			}
+
+			defer func() {
+				_ = f.Close()
+			}()
+
+			img, _, err := image.Decode(f)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode page: %w", err)
+			}
+
+			return img, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot load BMFont: %w", err)
+		}
+
+		return glyphs, nil
+	}
+
+	if p.fontSize <= 0 || p.fontSize > maxFontSize {
+		return nil, fmt.Errorf("font size should be between 1 and %d", maxFontSize)
+	}
+
+	chars := hsfont.CharsFromText(p.chars)
+
+	if !p.useChars {
+		if chars, err = hsfont.CharRange(p.firstChar, p.lastChar); err != nil {
+			return nil, fmt.Errorf("cannot import font: %w", err)
+		}
+	}
+
+	glyphs, err := hsfont.RasterizeTrueType(data, float64(p.fontSize), chars)
+	if err != nil {
+		return nil, fmt.Errorf("cannot rasterize font: %w", err)
+	}
+
+	return glyphs, nil
+}
+
+func (p *FontImportDialog) importFont() (*hsfont.Font, error) {
+	switch {
+	case p.sourcePath == "":
+		return nil, errors.New("select a font to import")
+	case p.palettePath == "":
+		return nil, errors.New("select a palette")
+	case strings.TrimSpace(p.name) == "" || p.outputDir == "":
+		return nil, errors.New("set output directory and font name")
+	}
+
+	palette, _, err := hspalette.LoadFile(p.palettePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot import font: %w", err)
+	}
+
+	glyphs, err := p.loadGlyphs()
+	if err != nil {
+		return nil, err
+	}
+
+	sprite, table, err := hsfont.Convert(glyphs, &palette)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert font: %w", err)
+	}
+
+	font, err := hsfont.SaveConverted(filepath.Join(p.outputDir, strings.TrimSpace(p.name)), p.palettePath, sprite, table)
+	if err != nil {
+		return nil, fmt.Errorf("cannot save font: %w", err)
+	}
+
+	return font, nil
+}
@@ -3,12 +3,14 @@ package hsanimdataeditor
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/OpenDiablo2/dialog"
 	g "github.com/ianling/giu"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2animdata"
 
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsanimdata"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
@@ -26,10 +28,21 @@ type AnimationDataEditor struct {
 	d2            *d2animdata.AnimationData
 	state         []byte
 	textureLoader hscommon.TextureLoader
+	config        *hsconfig.Config
+	// merge is a pending import
+	merge     *hsanimdata.Merge
+	overwrite bool
+	// cofReport is a pending result of validation against COFs
+	cofReport      *hsanimdata.COFReport
+	removeOrphaned bool
+	// original is a loaded file and originalRecords are its records; if records aren't changed,
+	// original is saved as it is, because encoding could reorder records
+	original        []byte
+	originalRecords []hsanimdata.Record
 }
 
 // Create creates a new cof editor
-func Create(_ *hsconfig.Config,
+func Create(config *hsconfig.Config,
 	tl hscommon.TextureLoader,
 	pathEntry *hscommon.PathEntry,
 	state []byte,
@@ -44,18 +57,35 @@ func Create(_ *hsconfig.Config,
 		d2:            d2,
 		state:         state,
 		textureLoader: tl,
+		config:        config,
+		original:      *data,
 	}
 
+	result.originalRecords = hsanimdata.Records(d2)
+
 	return result, nil
 }
 
 // Build builds a D2 editor
 func (e *AnimationDataEditor) Build() {
 	uid := e.Path.GetUniqueID()
-	animDataWidget := animdatawidget.Create(e.textureLoader, e.state, uid, e.d2)
+	animDataWidget := animdatawidget.Create(e.textureLoader, e.state, uid, e.d2, e.loadComposite)
 
 	e.IsOpen(&e.Visible)
 	e.Flags(g.WindowFlagsAlwaysAutoResize)
+
+	if e.merge != nil {
+		e.Layout(e.makeMergeLayout())
+
+		return
+	}
+
+	if e.cofReport != nil {
+		e.Layout(e.makeCOFReportLayout())
+
+		return
+	}
+
 	e.Layout(g.Layout{animDataWidget})
 }
 
@@ -67,8 +97,9 @@ func (e *AnimationDataEditor) UpdateMainMenuLayout(l *g.Layout) {
 		g.MenuItem("Add to project").OnClick(func() {}),
 		g.MenuItem("Remove from project").OnClick(func() {}),
 		g.Separator(),
-		g.MenuItem("Import from file...").OnClick(func() {}),
-		g.MenuItem("Export to file...").OnClick(func() {}),
+		g.MenuItem("Import from file...").OnClick(e.onImportClicked),
+		g.MenuItem("Export to file...").OnClick(e.onExportClicked),
+		g.MenuItem("Validate against COFs...").OnClick(e.onValidateCOFsClicked),
 		g.Separator(),
 		g.MenuItem("Close").OnClick(func() {
 			e.Cleanup()
@@ -78,15 +109,56 @@ func (e *AnimationDataEditor) UpdateMainMenuLayout(l *g.Layout) {
 	*l = append(*l, m)
 }
 
+// encode returns data to be saved
+func (e *AnimationDataEditor) encode() ([]byte, error) {
+	records := hsanimdata.Records(e.d2)
+	if recordsEqual(records, e.originalRecords) {
+		return e.original, nil
+	}
+
+	// d2animdata's Marshal doesn't put records into hash blocks, so the game wouldn't find them
+	data, err := hsanimdata.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding animation data: %w", err)
+	}
+
+	return data, nil
+}
+
+func recordsEqual(a, b []hsanimdata.Record) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if !a[idx].Equal(&b[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GenerateSaveData generates data to be saved
 func (e *AnimationDataEditor) GenerateSaveData() []byte {
-	data := e.d2.Marshal()
+	data, err := e.encode()
+	if err != nil {
+		log.Print(err)
+
+		return nil
+	}
 
 	return data
 }
 
 // Save saves an editor
 func (e *AnimationDataEditor) Save() {
+	if _, err := e.encode(); err != nil {
+		dialog.Message("%v, changes aren't saved", err).Error()
+
+		return
+	}
+
 	e.Editor.Save(e)
 }
 
@@ -94,7 +166,9 @@ func (e *AnimationDataEditor) Save() {
 func (e *AnimationDataEditor) Cleanup() {
 	const strPrompt = "There are unsaved changes to %s, save before closing this editor?"
 
-	if e.HasChanges(e) {
+	if _, err := e.encode(); err != nil {
+		dialog.Message("Changes to %s can't be saved and will be lost: %v", e.Path.FullPath, err).Error()
+	} else if e.HasChanges(e) {
 		if shouldSave := dialog.Message(strPrompt, e.Path.FullPath).YesNo(); shouldSave {
 			e.Save()
 		}
@@ -0,0 +1,130 @@
+package hsanimdataeditor
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/dialog"
+	g "github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsanimdata"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hscof"
+)
+
+// unitsPalettePath is a palette used to preview animations
+const unitsPalettePath = `data\global\palette\act1\pal.dat`
+
+func (e *AnimationDataEditor) onValidateCOFsClicked() {
+	cofs, err := e.Project.ScanCOFs(e.config)
+	if err != nil {
+		dialog.Message("%v", err).Error()
+
+		return
+	}
+
+	if len(cofs) == 0 {
+		dialog.Message("No COF files found in project and auxiliary MPQs").Info()
+
+		return
+	}
+
+	report := hsanimdata.ValidateCOFs(hsanimdata.Records(e.d2), cofs)
+	if report.Empty() {
+		dialog.Message("Animation data matches all %d COF files", len(cofs)).Info()
+
+		return
+	}
+
+	e.cofReport = report
+	e.removeOrphaned = false
+}
+
+// applyCOFFixes adds missing records and corrects frame counts reported by validation
+func (e *AnimationDataEditor) applyCOFFixes() {
+	records, err := e.cofReport.Fix(hsanimdata.Records(e.d2), e.removeOrphaned)
+	if err != nil {
+		dialog.Message("error fixing animation data: %v", err).Error()
+
+		return
+	}
+
+	fixed, err := hsanimdata.Build(records)
+	if err != nil {
+		dialog.Message("error fixing animation data: %v", err).Error()
+
+		return
+	}
+
+	*e.d2 = *fixed
+	e.cofReport = nil
+}
+
+func (e *AnimationDataEditor) makeCOFReportLayout() g.Layout {
+	r := e.cofReport
+
+	rows := make([]*g.TableRowWidget, 0, len(r.Missing)+len(r.Mismatched)+len(r.Orphaned)+len(r.Unreadable)+1)
+	rows = append(rows, g.TableRow(g.Label("Entry"), g.Label("Problem"), g.Label("COF")))
+
+	for _, cof := range r.Missing {
+		rows = append(rows, g.TableRow(
+			g.Label(cof.Name),
+			g.Label(fmt.Sprintf("missing (fpd %d, speed %d)", cof.FramesPerDirection, cof.Speed)),
+			g.Label(cof.Source),
+		))
+	}
+
+	for _, m := range r.Mismatched {
+		rows = append(rows, g.TableRow(
+			g.Label(m.Name),
+			g.Label(fmt.Sprintf("fpd %d, COF has %d", m.Record, m.COF.FramesPerDirection)),
+			g.Label(m.COF.Source),
+		))
+	}
+
+	for _, name := range r.Orphaned {
+		rows = append(rows, g.TableRow(g.Label(name), g.Label("no matching COF"), g.Label("")))
+	}
+
+	for _, cof := range r.Unreadable {
+		rows = append(rows, g.TableRow(g.Label(cof.Name), g.Label("COF "+cof.Error), g.Label(cof.Source)))
+	}
+
+	return g.Layout{
+		g.Label(fmt.Sprintf("Missing entries: %d", len(r.Missing))),
+		g.Label(fmt.Sprintf("Entries with wrong frame count: %d", len(r.Mismatched))),
+		g.Label(fmt.Sprintf("Entries without COF: %d", len(r.Orphaned))),
+		g.Label(fmt.Sprintf("Unreadable COF files (their entries aren't checked): %d", len(r.Unreadable))),
+		g.Child("##AnimationDataEditorCOFReport").Border(true).Size(conflictsW, conflictsH).Layout(g.Layout{
+			g.Table("##AnimationDataEditorCOFReportTable").FastMode(true).Rows(rows...),
+		}),
+		g.Checkbox("Remove entries without COF##AnimationDataEditorRemoveOrphaned", &e.removeOrphaned),
+		g.Separator(),
+		g.Row(
+			g.Button("Fix all##AnimationDataEditorCOFFix").Size(mergeButtonW, mergeButtonH).OnClick(e.applyCOFFixes),
+			g.Button("Cancel##AnimationDataEditorCOFCancel").Size(mergeButtonW, mergeButtonH).OnClick(func() {
+				e.cofReport = nil
+			}),
+		),
+	}
+}
+
+// loadComposite composes animation of record using COFs and graphics of project and auxiliary MPQs
+func (e *AnimationDataEditor) loadComposite(name string, direction int) (*hscof.Composite, error) {
+	var palette *[256]d2interface.Color
+
+	if data, err := e.Project.ReadFile(unitsPalettePath); err == nil {
+		if pal, err := d2dat.Load(data); err == nil {
+			colors := pal.GetColors()
+			palette = &colors
+		}
+	}
+
+	composite, err := hscof.Load(name, e.Project.ReadFile, palette, direction)
+	if err != nil {
+		return nil, fmt.Errorf("error loading animation: %w", err)
+	}
+
+	return composite, nil
+}
@@ -0,0 +1,149 @@
+package hsanimdataeditor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/dialog"
+	g "github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsanimdata"
+)
+
+const (
+	newFileMode  = 0o644
+	conflictsW   = 400
+	conflictsH   = 200
+	mergeButtonW = 120
+	mergeButtonH = 30
+)
+
+func (e *AnimationDataEditor) onExportClicked() {
+	filePath, err := dialog.File().Title("Export animation data").
+		Filter("CSV", "csv").Filter("JSON", "json").Save()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	if err := e.exportToFile(filePath); err != nil {
+		dialog.Message("%v", err).Error()
+	}
+}
+
+func (e *AnimationDataEditor) exportToFile(filePath string) error {
+	format, err := hsanimdata.FormatFromExtension(filepath.Ext(filePath))
+	if err != nil {
+		return fmt.Errorf("error exporting animation data: %w", err)
+	}
+
+	data, err := hsanimdata.Export(hsanimdata.Records(e.d2), format)
+	if err != nil {
+		return fmt.Errorf("error exporting animation data: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(filePath), data, os.FileMode(newFileMode)); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+func (e *AnimationDataEditor) onImportClicked() {
+	filePath, err := dialog.File().Title("Import animation data").Filter("CSV, JSON", "csv", "json").Load()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	if err := e.importFromFile(filePath); err != nil {
+		dialog.Message("%v", err).Error()
+	}
+}
+
+func (e *AnimationDataEditor) importFromFile(filePath string) error {
+	format, err := hsanimdata.FormatFromExtension(filepath.Ext(filePath))
+	if err != nil {
+		return fmt.Errorf("error importing animation data: %w", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	imported, err := hsanimdata.Import(data, format)
+	if err != nil {
+		return fmt.Errorf("error importing animation data: %w", err)
+	}
+
+	e.merge = hsanimdata.NewMerge(hsanimdata.Records(e.d2), imported)
+	e.overwrite = false
+
+	return nil
+}
+
+// applyMerge replaces animation data with merged records
+func (e *AnimationDataEditor) applyMerge() {
+	merged, err := hsanimdata.Build(e.merge.Records(e.overwrite))
+	if err != nil {
+		dialog.Message("error merging animation data: %v", err).Error()
+
+		return
+	}
+
+	*e.d2 = *merged
+	e.merge = nil
+}
+
+func formatRecords(records []hsanimdata.Record) string {
+	parts := make([]string, len(records))
+
+	for idx := range records {
+		r := &records[idx]
+		parts[idx] = fmt.Sprintf("fpd %d, speed %d, %d event(s)", r.FramesPerDirection, r.Speed, len(r.Events))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func (e *AnimationDataEditor) makeMergeLayout() g.Layout {
+	m := e.merge
+
+	rows := make([]*g.TableRowWidget, 0, len(m.Conflicts)+1)
+	rows = append(rows, g.TableRow(g.Label("Entry"), g.Label("Current"), g.Label("Imported")))
+
+	for _, name := range m.Conflicts {
+		rows = append(rows, g.TableRow(
+			g.Label(name),
+			g.Label(formatRecords(m.Current(name))),
+			g.Label(formatRecords(m.Imported(name))),
+		))
+	}
+
+	return g.Layout{
+		g.Label(fmt.Sprintf("Entries to add: %d", len(m.Added))),
+		g.Label(fmt.Sprintf("Unchanged entries: %d", m.Unchanged)),
+		g.Label(fmt.Sprintf("Conflicting entries: %d", len(m.Conflicts))),
+		g.Custom(func() {
+			if len(m.Conflicts) == 0 {
+				return
+			}
+
+			g.Layout{
+				g.Child("##AnimationDataEditorConflicts").Border(true).Size(conflictsW, conflictsH).Layout(g.Layout{
+					g.Table("##AnimationDataEditorConflictsTable").FastMode(true).Rows(rows...),
+				}),
+				g.Checkbox("Overwrite conflicting entries with imported ones##AnimationDataEditorOverwrite", &e.overwrite),
+			}.Build()
+		}),
+		g.Separator(),
+		g.Row(
+			g.Button("Merge##AnimationDataEditorMerge").Size(mergeButtonW, mergeButtonH).OnClick(e.applyMerge),
+			g.Button("Cancel##AnimationDataEditorMergeCancel").Size(mergeButtonW, mergeButtonH).OnClick(func() {
+				e.merge = nil
+			}),
+		),
+	}
+}
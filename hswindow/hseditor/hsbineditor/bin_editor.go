@@ -0,0 +1,198 @@
+// Package hsbineditor contains compiled excel table editor's data
+package hsbineditor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	g "github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsbin"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
+	"github.com/OpenDiablo2/HellSpawner/hsconfig"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor"
+)
+
+const (
+	mainWindowW, mainWindowH = 400, 300
+	tableViewModW            = 80
+	newFileMode              = 0o644
+)
+
+// static check, to ensure, if bin editor implemented editoWindow
+var _ hscommon.EditorWindow = &BinEditor{}
+
+// BinEditor represents a compiled excel table editor
+type BinEditor struct {
+	*hseditor.Editor
+
+	layout    *hsbin.Layout
+	data      []byte
+	table     *hsbin.Table
+	tableRows []*g.TableRowWidget
+}
+
+// Create creates a new bin editor
+func Create(_ *hsconfig.Config,
+	_ hscommon.TextureLoader,
+	pathEntry *hscommon.PathEntry,
+	_ []byte,
+	data *[]byte, x, y float32, project *hsproject.Project) (hscommon.EditorWindow, error) {
+	layout, found := hsbin.LayoutFor(pathEntry.Name)
+	if !found {
+		return nil, fmt.Errorf("record layout of compiled table %s is unknown", pathEntry.Name)
+	}
+
+	table, err := layout.Decode(*data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding compiled table: %w", err)
+	}
+
+	result := &BinEditor{
+		Editor: hseditor.New(pathEntry, x, y, project),
+		layout: layout,
+		data:   *data,
+	}
+
+	if w, h := result.CurrentSize(); w == 0 || h == 0 {
+		result.Size(mainWindowW, mainWindowH)
+	}
+
+	result.setTable(table)
+
+	return result, nil
+}
+
+func (e *BinEditor) setTable(table *hsbin.Table) {
+	e.table = table
+	e.tableRows = make([]*g.TableRowWidget, len(table.Rows)+1)
+
+	columnWidgets := make([]g.Widget, len(table.Columns))
+	for idx := range table.Columns {
+		columnWidgets[idx] = g.Label(table.Columns[idx])
+	}
+
+	e.tableRows[0] = g.TableRow(columnWidgets...)
+
+	for rowIdx, row := range table.Rows {
+		columnWidgets := make([]g.Widget, len(row))
+
+		for idx := range row {
+			columnWidgets[idx] = g.Label(row[idx])
+		}
+
+		e.tableRows[rowIdx+1] = g.TableRow(columnWidgets...)
+	}
+}
+
+// Build builds an editor
+func (e *BinEditor) Build() {
+	e.IsOpen(&e.Visible).
+		Flags(g.WindowFlagsHorizontalScrollbar).
+		Layout(
+			g.Child("").Border(false).Size(float32(len(e.table.Columns)*tableViewModW), 0).Layout(
+				g.Table("").FastMode(true).Freeze(0, 1).Rows(e.tableRows...),
+			),
+		)
+}
+
+// UpdateMainMenuLayout updates mainMenu layout to it contains editor's options
+func (e *BinEditor) UpdateMainMenuLayout(l *g.Layout) {
+	m := g.Menu("Compiled Table Editor").Layout(g.Layout{
+		g.MenuItem("Save\t\t\t\tCtrl+Shift+S").OnClick(e.Save),
+		g.Separator(),
+		g.MenuItem("Add to project").OnClick(func() {}),
+		g.MenuItem("Remove from project").OnClick(func() {}),
+		g.Separator(),
+		g.MenuItem("Import from txt...").OnClick(e.importFromTXT),
+		g.MenuItem("Export to txt...").OnClick(e.exportToTXT),
+		g.Separator(),
+		g.MenuItem("Close").OnClick(func() {
+			e.Cleanup()
+		}),
+	})
+
+	*l = append(*l, m)
+}
+
+// importFromTXT compiles a txt table selected by user and replaces editor's data with it
+func (e *BinEditor) importFromTXT() {
+	filePath, err := dialog.File().Title("Import").Filter("excel tables", "txt").Load()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	txt, err := ioutil.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	table, err := hsbin.LoadTXT(txt)
+	if err != nil {
+		dialog.Message("Unable to import %s: %v", filePath, err).Error()
+		return
+	}
+
+	data, err := e.layout.Encode(table)
+	if err != nil {
+		dialog.Message("Unable to compile %s: %v", filePath, err).Error()
+		return
+	}
+
+	// decoding back makes the view show exactly the data, which will be saved
+	table, err = e.layout.Decode(data)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	e.data = data
+	e.setTable(table)
+}
+
+// exportToTXT saves decoded table under the path selected by user
+func (e *BinEditor) exportToTXT() {
+	filePath, err := dialog.File().Title("Export").Filter("excel tables", "txt").Save()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	if filepath.Ext(filePath) == "" {
+		filePath += ".txt"
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(filePath), e.table.MarshalTXT(), os.FileMode(newFileMode)); err != nil {
+		log.Print(err)
+	}
+}
+
+// GenerateSaveData generates data to be saved
+func (e *BinEditor) GenerateSaveData() []byte {
+	return e.data
+}
+
+// Save saves an editor
+func (e *BinEditor) Save() {
+	e.Editor.Save(e)
+}
+
+// Cleanup hides an editor
+func (e *BinEditor) Cleanup() {
+	if e.HasChanges(e) {
+		if shouldSave := dialog.Message("There are unsaved changes to %s, save before closing this editor?",
+			e.Path.FullPath).YesNo(); shouldSave {
+			e.Save()
+		}
+	}
+
+	e.Editor.Cleanup()
+}
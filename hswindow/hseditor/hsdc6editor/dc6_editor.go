@@ -3,6 +3,10 @@ package hsdc6editor
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/OpenDiablo2/dialog"
 	g "github.com/ianling/giu"
@@ -11,13 +15,19 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdc6"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdcc"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
 	"github.com/OpenDiablo2/HellSpawner/hsconfig"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/dc6widget"
+	"github.com/OpenDiablo2/HellSpawner/hswidget/paletteremapwidget"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/selectpalettewidget"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor"
 )
 
+const newFileMode = 0o644
+
 // static check, to ensure, if dc6 editor implemented editoWindow
 var _ hscommon.EditorWindow = &DC6Editor{}
 
@@ -31,6 +41,10 @@ type DC6Editor struct {
 	palette             *[256]d2interface.Color
 	selectPaletteWidget g.Widget
 	state               []byte
+	// remapTarget is a palette, which sprite is being remapped to
+	remapTarget             *[256]d2interface.Color
+	selectRemapTarget       bool
+	selectRemapTargetWidget g.Widget
 }
 
 // Create creates a new dc6 editor
@@ -61,9 +75,20 @@ func (e *DC6Editor) Build() {
 	e.IsOpen(&e.Visible)
 	e.Flags(g.WindowFlagsAlwaysAutoResize)
 
+	if e.selectRemapTarget {
+		e.buildSelectRemapTarget()
+
+		return
+	}
+
 	if !e.selectPalette {
 		e.Layout(g.Layout{
 			dc6widget.Create(e.state, e.palette, e.textureLoader, e.Path.GetUniqueID(), e.dc6),
+			g.Custom(func() {
+				if e.remapTarget != nil {
+					g.Layout{g.Separator(), e.makeRemapWidget()}.Build()
+				}
+			}),
 		})
 
 		return
@@ -100,6 +125,8 @@ func (e *DC6Editor) UpdateMainMenuLayout(l *g.Layout) {
 		g.Separator(),
 		g.MenuItem("Import from file...").OnClick(func() {}),
 		g.MenuItem("Export to file...").OnClick(func() {}),
+		g.MenuItem("Convert to DCC...").OnClick(e.onConvertToDCCClicked),
+		g.MenuItem("Remap to palette...").OnClick(e.onRemapClicked),
 		g.Separator(),
 		g.MenuItem("Close").OnClick(func() {
 			e.Cleanup()
@@ -109,6 +136,80 @@ func (e *DC6Editor) UpdateMainMenuLayout(l *g.Layout) {
 	*l = append(*l, m)
 }
 
+func (e *DC6Editor) onConvertToDCCClicked() {
+	filePath, err := dialog.File().Title("Convert to DCC").Filter("DCC animation", "dcc").Save()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	// DCC cells have 4 colors at most, the palette is used to pick the nearest ones
+	data, warnings, err := hsdcc.FromDC6(e.dc6, hspalette.Colors(e.palette))
+	if err != nil {
+		dialog.Message("error converting DC6 to DCC: %v", err).Error()
+
+		return
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(filePath), data, os.FileMode(newFileMode)); err != nil {
+		dialog.Message("error writing file: %v", err).Error()
+
+		return
+	}
+
+	if len(warnings) > 0 {
+		dialog.Message("DC6 was converted with warnings:\n%s", strings.Join(warnings, "\n")).Info()
+	}
+}
+
+func (e *DC6Editor) onRemapClicked() {
+	if e.palette == nil {
+		dialog.Message("select sprite's current palette first (DC6 Editor > Change Palette)").Info()
+
+		return
+	}
+
+	e.selectRemapTarget = true
+}
+
+func (e *DC6Editor) buildSelectRemapTarget() {
+	if e.selectRemapTargetWidget == nil {
+		e.selectRemapTargetWidget = selectpalettewidget.NewSelectPaletteWidget(
+			e.Path.GetUniqueID()+"selectRemapTarget",
+			e.Project,
+			e.config,
+			func(palette *[256]d2interface.Color) {
+				e.remapTarget = palette
+			},
+			func() {
+				e.selectRemapTarget = false
+			},
+		)
+	}
+
+	e.Layout(e.selectRemapTargetWidget)
+}
+
+func (e *DC6Editor) makeRemapWidget() g.Widget {
+	frame := func(idx int) paletteremapwidget.Image {
+		f := e.dc6.Frames[idx]
+		if f.Width == 0 || f.Height == 0 {
+			return paletteremapwidget.Image{Width: 1, Height: 1, Indices: []byte{0}}
+		}
+
+		return paletteremapwidget.Image{Width: int(f.Width), Height: int(f.Height), Indices: e.dc6.DecodeFrame(idx)}
+	}
+
+	return paletteremapwidget.Create(e.textureLoader, e.Path.GetUniqueID()+"remap", e.palette, e.remapTarget,
+		len(e.dc6.Frames), frame).
+		OnApply(func(table *[256]byte) {
+			hsdc6.Remap(e.dc6, table)
+			e.palette, e.remapTarget = e.remapTarget, nil
+		}).
+		OnCancel(func() {
+			e.remapTarget = nil
+		})
+}
+
 // GenerateSaveData generates save data
 func (e *DC6Editor) GenerateSaveData() []byte {
 	data := e.dc6.Marshal()
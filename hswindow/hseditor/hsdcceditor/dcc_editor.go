@@ -3,6 +3,9 @@ package hsdcceditor
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	g "github.com/ianling/giu"
 
@@ -12,13 +15,17 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdcc"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
 	"github.com/OpenDiablo2/HellSpawner/hsconfig"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/dccwidget"
+	"github.com/OpenDiablo2/HellSpawner/hswidget/paletteremapwidget"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/selectpalettewidget"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor"
 )
 
+const newFileMode = 0o644
+
 // static check, to ensure, if dc6 editor implemented editoWindow
 var _ hscommon.EditorWindow = &DCCEditor{}
 
@@ -32,6 +39,12 @@ type DCCEditor struct {
 	selectPaletteWidget g.Widget
 	state               []byte
 	textureLoader       hscommon.TextureLoader
+	// data is encoded animation after it was changed (e.g. remapped); nil if it wasn't changed
+	data []byte
+	// remapTarget is a palette, which animation is being remapped to
+	remapTarget             *[256]d2interface.Color
+	selectRemapTarget       bool
+	selectRemapTargetWidget g.Widget
 }
 
 // Create creates a new dcc editor
@@ -62,9 +75,20 @@ func (e *DCCEditor) Build() {
 	e.IsOpen(&e.Visible)
 	e.Flags(g.WindowFlagsAlwaysAutoResize)
 
+	if e.selectRemapTarget {
+		e.buildSelectRemapTarget()
+
+		return
+	}
+
 	if !e.selectPalette {
 		e.Layout(g.Layout{
 			dccwidget.Create(e.textureLoader, e.state, e.palette, e.Path.GetUniqueID(), e.dcc),
+			g.Custom(func() {
+				if e.remapTarget != nil {
+					g.Layout{g.Separator(), e.makeRemapWidget()}.Build()
+				}
+			}),
 		})
 
 		return
@@ -99,6 +123,8 @@ func (e *DCCEditor) UpdateMainMenuLayout(l *g.Layout) {
 		g.Separator(),
 		g.MenuItem("Import from file...").OnClick(func() {}),
 		g.MenuItem("Export to file...").OnClick(func() {}),
+		g.MenuItem("Convert to DC6...").OnClick(e.onConvertToDC6Clicked),
+		g.MenuItem("Remap to palette...").OnClick(e.onRemapClicked),
 		g.Separator(),
 		g.MenuItem("Close").OnClick(func() {
 			e.Cleanup()
@@ -108,8 +134,95 @@ func (e *DCCEditor) UpdateMainMenuLayout(l *g.Layout) {
 	*l = append(*l, m)
 }
 
+func (e *DCCEditor) onConvertToDC6Clicked() {
+	filePath, err := dialog.File().Title("Convert to DC6").Filter("DC6 sprite", "dc6").Save()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	sprite, err := hsdcc.ToDC6(e.dcc)
+	if err != nil {
+		dialog.Message("error converting DCC to DC6: %v", err).Error()
+
+		return
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(filePath), sprite.Marshal(), os.FileMode(newFileMode)); err != nil {
+		dialog.Message("error writing file: %v", err).Error()
+	}
+}
+
+func (e *DCCEditor) onRemapClicked() {
+	if e.palette == nil {
+		dialog.Message("select animation's current palette first (DCC Editor > Change Palette)").Info()
+
+		return
+	}
+
+	e.selectRemapTarget = true
+}
+
+func (e *DCCEditor) buildSelectRemapTarget() {
+	if e.selectRemapTargetWidget == nil {
+		e.selectRemapTargetWidget = selectpalettewidget.NewSelectPaletteWidget(
+			"##"+e.Path.GetUniqueID()+"SelectRemapTargetWidget",
+			e.Project,
+			e.config,
+			func(colors *[256]d2interface.Color) {
+				e.remapTarget = colors
+			},
+			func() {
+				e.selectRemapTarget = false
+			},
+		)
+	}
+
+	e.Layout(g.Layout{e.selectRemapTargetWidget})
+}
+
+// remapPreview returns a frame (cropped to its box) to preview remapping
+func (e *DCCEditor) remapPreview(idx int) paletteremapwidget.Image {
+	fpd := e.dcc.FramesPerDirection
+	frame := hsdcc.DirectionFrames(e.dcc.Directions[idx/fpd])[idx%fpd]
+
+	return paletteremapwidget.Image{Width: frame.Width, Height: frame.Height, Indices: frame.Indices}
+}
+
+func (e *DCCEditor) makeRemapWidget() g.Widget {
+	return paletteremapwidget.Create(e.textureLoader, e.Path.GetUniqueID()+"remap", e.palette, e.remapTarget,
+		e.dcc.NumberOfDirections*e.dcc.FramesPerDirection, e.remapPreview).
+		OnApply(func(table *[256]byte) {
+			// a copy is remapped, so that the animation is kept as it is, if it can't be encoded
+			dcc, err := d2dcc.Load(e.GenerateSaveData())
+			if err != nil {
+				dialog.Message("error loading animation: %v", err).Error()
+
+				return
+			}
+
+			hsdcc.Remap(dcc, table)
+
+			data, err := hsdcc.Marshal(dcc)
+			if err != nil {
+				dialog.Message("error encoding remapped animation: %v", err).Error()
+
+				return
+			}
+
+			e.dcc, e.data = dcc, data
+			e.palette, e.remapTarget = e.remapTarget, nil
+		}).
+		OnCancel(func() {
+			e.remapTarget = nil
+		})
+}
+
 // GenerateSaveData generates data to save
 func (e *DCCEditor) GenerateSaveData() []byte {
+	if e.data != nil {
+		return e.data
+	}
+
 	// https://github.com/OpenDiablo2/HellSpawner/issues/181
 	data, _ := e.Path.GetFileBytes()
 
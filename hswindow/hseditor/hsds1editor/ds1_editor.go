@@ -3,6 +3,7 @@ package hsds1editor
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/OpenDiablo2/dialog"
 	g "github.com/ianling/giu"
@@ -13,11 +14,15 @@ import (
 
 	"github.com/OpenDiablo2/HellSpawner/hsassets"
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsds1"
 	"github.com/OpenDiablo2/HellSpawner/hsconfig"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/ds1widget"
 	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor"
 )
 
+// old DS1 versions don't specify map's act
+const defaultAct = 1
+
 // static check if DS1Editor implemented hscommon.EditorWindow
 var _ hscommon.EditorWindow = &DS1Editor{}
 
@@ -27,15 +32,25 @@ type DS1Editor struct {
 	ds1                 *d2ds1.DS1
 	deleteButtonTexture *g.Texture
 	textureLoader       hscommon.TextureLoader
+	presets             *hsds1.Presets
+	clipboard           *hsds1.Clipboard
 	state               []byte
 }
 
-// Create creates a new ds1 editor
-func Create(_ *hsconfig.Config,
-	tl hscommon.TextureLoader,
+// Creator returns a function, which creates DS1 editors sharing the clipboard given,
+// so that map regions can be copied between them
+func Creator(clipboard *hsds1.Clipboard) func(*hsconfig.Config, hscommon.TextureLoader, *hscommon.PathEntry,
+	[]byte, *[]byte, float32, float32, *hsproject.Project) (hscommon.EditorWindow, error) {
+	return func(_ *hsconfig.Config, tl hscommon.TextureLoader, pathEntry *hscommon.PathEntry,
+		state []byte, data *[]byte, x, y float32, project *hsproject.Project) (hscommon.EditorWindow, error) {
+		return create(tl, pathEntry, state, data, x, y, project, clipboard)
+	}
+}
+
+func create(tl hscommon.TextureLoader,
 	pathEntry *hscommon.PathEntry,
 	state []byte,
-	data *[]byte, x, y float32, project *hsproject.Project) (hscommon.EditorWindow, error) {
+	data *[]byte, x, y float32, project *hsproject.Project, clipboard *hsds1.Clipboard) (hscommon.EditorWindow, error) {
 	ds1, err := d2ds1.Unmarshal(*data)
 	if err != nil {
 		return nil, fmt.Errorf("error loading DS1 file: %w", err)
@@ -45,11 +60,14 @@ func Create(_ *hsconfig.Config,
 		Editor:        hseditor.New(pathEntry, x, y, project),
 		ds1:           ds1,
 		textureLoader: tl,
+		clipboard:     clipboard,
 		state:         state,
 	}
 
 	result.Path = pathEntry
 
+	result.loadPresets()
+
 	tl.CreateTextureFromFile(hsassets.DeleteIcon, func(texture *g.Texture) {
 		result.deleteButtonTexture = texture
 	})
@@ -62,7 +80,8 @@ func (e *DS1Editor) Build() {
 	e.IsOpen(&e.Visible).
 		Flags(g.WindowFlagsAlwaysAutoResize).
 		Layout(g.Layout{
-			ds1widget.Create(e.textureLoader, e.Path.GetUniqueID(), e.ds1, e.deleteButtonTexture, e.state),
+			ds1widget.Create(e.textureLoader, e.Path.GetUniqueID(), e.ds1, e.deleteButtonTexture,
+				e.presets, e.clipboard, e.Project.ReadFile, e.state),
 		})
 }
 
@@ -74,6 +93,8 @@ func (e *DS1Editor) UpdateMainMenuLayout(l *g.Layout) {
 		g.MenuItem("Add to project").OnClick(func() {}),
 		g.MenuItem("Remove from project").OnClick(func() {}),
 		g.Separator(),
+		g.MenuItem("Reload object presets").OnClick(e.loadPresets),
+		g.Separator(),
 		g.MenuItem("Import from file...").OnClick(func() {}),
 		g.MenuItem("Export to file...").OnClick(func() {}),
 		g.Separator(),
@@ -85,9 +106,27 @@ func (e *DS1Editor) UpdateMainMenuLayout(l *g.Layout) {
 	*l = append(*l, m)
 }
 
+// loadPresets loads presets of map's act from project and auxiliary MPQs,
+// so that the widget can show names of objects
+func (e *DS1Editor) loadPresets() {
+	act := int(e.ds1.Act)
+	if act == 0 {
+		act = defaultAct
+	}
+
+	presets, err := hsds1.LoadPresets(e.Project.ReadFile, act)
+	if err != nil {
+		log.Printf("error loading object presets: %v", err)
+
+		return
+	}
+
+	e.presets = presets
+}
+
 // GenerateSaveData generates data to be saved
 func (e *DS1Editor) GenerateSaveData() []byte {
-	data := e.ds1.Marshal()
+	data := hsds1.Marshal(e.ds1)
 
 	return data
 }
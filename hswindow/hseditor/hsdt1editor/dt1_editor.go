@@ -12,6 +12,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdt1"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
 	"github.com/OpenDiablo2/HellSpawner/hsconfig"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/dt1widget"
@@ -32,6 +33,8 @@ type DT1Editor struct {
 	palette             *[256]d2interface.Color
 	selectPaletteWidget g.Widget
 	state               []byte
+	// tile group to show, set from outside of the editor (-1 if none)
+	tileGroup int32
 }
 
 // Create creates new dt1 editor
@@ -52,6 +55,7 @@ func Create(config *hsconfig.Config,
 		selectPalette: false,
 		textureLoader: textureLoader,
 		state:         state,
+		tileGroup:     -1,
 	}
 
 	return result, nil
@@ -63,7 +67,14 @@ func (e *DT1Editor) Build() {
 	e.Flags(g.WindowFlagsAlwaysAutoResize)
 
 	if !e.selectPalette {
-		dt1Viewer := dt1widget.Create(e.state, e.palette, e.textureLoader, e.Path.GetUniqueID(), e.dt1)
+		dt1Viewer := dt1widget.Create(e.state, e.palette, e.textureLoader, e.Path.GetUniqueID(), e.dt1,
+			e.Path.FullPath, e.Project)
+
+		if e.tileGroup >= 0 {
+			dt1widget.SetTileGroup(dt1Viewer, e.tileGroup)
+			e.tileGroup = -1
+		}
+
 		e.Layout(g.Layout{
 			dt1Viewer,
 		})
@@ -89,6 +100,11 @@ func (e *DT1Editor) Build() {
 	e.Layout(g.Layout{e.selectPaletteWidget})
 }
 
+// SetTileGroup makes the editor show tile group given
+func (e *DT1Editor) SetTileGroup(tileGroup int32) {
+	e.tileGroup = tileGroup
+}
+
 // UpdateMainMenuLayout updates main menu layout to it contains editors options
 func (e *DT1Editor) UpdateMainMenuLayout(l *g.Layout) {
 	m := g.Menu("DT1 Editor").Layout(g.Layout{
@@ -140,7 +156,7 @@ func (e *DT1Editor) KeyboardShortcuts() []g.WindowShortcut {
 
 // GenerateSaveData generates data to be saved
 func (e *DT1Editor) GenerateSaveData() []byte {
-	data := e.dt1.Marshal()
+	data := hsdt1.Marshal(e.dt1)
 
 	return data
 }
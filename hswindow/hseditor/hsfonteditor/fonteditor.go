@@ -3,6 +3,8 @@ package hsfonteditor
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/OpenDiablo2/dialog"
 	g "github.com/ianling/giu"
@@ -16,7 +18,7 @@ import (
 )
 
 const (
-	mainWindowW, mainWindowH = 400, 300
+	mainWindowW, mainWindowH = 500, 600
 	pathSize                 = 245
 	browseW, browseH         = 30, 0
 )
@@ -28,11 +30,13 @@ var _ hscommon.EditorWindow = &FontEditor{}
 type FontEditor struct {
 	*hseditor.Editor
 	*hsfont.Font
+	textureLoader hscommon.TextureLoader
+	preview       preview
 }
 
 // Create creates a new font editor
 func Create(_ *hsconfig.Config,
-	_ hscommon.TextureLoader,
+	tl hscommon.TextureLoader,
 	pathEntry *hscommon.PathEntry,
 	_ []byte,
 	data *[]byte, x, y float32, project *hsproject.Project) (hscommon.EditorWindow, error) {
@@ -42,14 +46,17 @@ func Create(_ *hsconfig.Config,
 	}
 
 	result := &FontEditor{
-		Editor: hseditor.New(pathEntry, x, y, project),
-		Font:   font,
+		Editor:        hseditor.New(pathEntry, x, y, project),
+		Font:          font,
+		textureLoader: tl,
 	}
 
 	if w, h := result.CurrentSize(); w == 0 || h == 0 {
 		result.Size(mainWindowW, mainWindowH)
 	}
 
+	result.reloadPreview()
+
 	return result, nil
 }
 
@@ -74,6 +81,8 @@ func (e *FontEditor) Build() {
 				g.InputText("##FontEditorPL2Path", &e.PaletteFile).Size(pathSize).Flags(g.InputTextFlags_ReadOnly),
 				g.Button("...##FontEditorPL2Browse").Size(browseW, browseH).OnClick(e.onBrowsePL2PathClicked),
 			),
+			g.Separator(),
+			e.makePreviewLayout(),
 		})
 }
 
@@ -88,6 +97,7 @@ func (e *FontEditor) onBrowseDC6PathClicked() {
 	}
 
 	e.SpriteFile = filePath
+	e.reloadPreview()
 }
 
 func (e *FontEditor) onBrowseTBLPathClicked() {
@@ -101,6 +111,7 @@ func (e *FontEditor) onBrowseTBLPathClicked() {
 	}
 
 	e.TableFile = filePath
+	e.reloadPreview()
 }
 
 func (e *FontEditor) onBrowsePL2PathClicked() {
@@ -114,6 +125,28 @@ func (e *FontEditor) onBrowsePL2PathClicked() {
 	}
 
 	e.PaletteFile = filePath
+	e.reloadPreview()
+}
+
+func (e *FontEditor) onExportBMFontClicked() {
+	if e.preview.renderer == nil {
+		dialog.Message("Unable to export font: %v", e.preview.err).Error()
+
+		return
+	}
+
+	filePath, err := dialog.File().Title("Export as BMFont").Filter("BMFont descriptor", "fnt").Save()
+	if err != nil || filePath == "" {
+		return
+	}
+
+	if !strings.EqualFold(filepath.Ext(filePath), ".fnt") {
+		filePath += ".fnt"
+	}
+
+	if err := e.preview.renderer.SaveBMFont(filePath); err != nil {
+		dialog.Message("%v", err).Error()
+	}
 }
 
 // UpdateMainMenuLayout updates main menu layout to it contains editors options
@@ -124,6 +157,7 @@ func (e *FontEditor) UpdateMainMenuLayout(l *g.Layout) {
 		g.Separator(),
 		g.MenuItem("Import from file...").OnClick(func() {}),
 		g.MenuItem("Export to file...").OnClick(func() {}),
+		g.MenuItem("Export as BMFont...").OnClick(e.onExportBMFontClicked),
 		g.Separator(),
 		g.MenuItem("Close").OnClick(func() {
 			e.Cleanup()
@@ -0,0 +1,169 @@
+package hsfonteditor
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	g "github.com/ianling/giu"
+	"github.com/ianling/imgui-go"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsfont"
+)
+
+const (
+	defaultSampleText   = "The quick brown fox jumps over the lazy dog.\n0123456789 !?.,:;'\"()"
+	sampleTextH         = 50
+	zoomW               = 100
+	maxZoom             = 8
+	glyphGridColumns    = 16
+	defaultPreviewScale = 2
+)
+
+// preview represents a live preview of the font
+type preview struct {
+	renderer *hsfont.Renderer
+	err      error
+
+	sampleText    string
+	sampleTexture *g.Texture
+	sampleSize    image.Point
+	missing       []rune
+
+	showGrid     bool
+	gridTexture  *g.Texture
+	gridSize     image.Point
+	gridCellSize image.Point
+	gridRunes    []rune
+
+	zoom int32
+}
+
+// reloadPreview loads files referenced by font again and re-renders preview
+func (e *FontEditor) reloadPreview() {
+	if e.preview.sampleText == "" {
+		e.preview.sampleText = defaultSampleText
+	}
+
+	if e.preview.zoom == 0 {
+		e.preview.zoom = defaultPreviewScale
+	}
+
+	e.preview.renderer, e.preview.err = e.Font.NewRenderer()
+	e.preview.sampleTexture, e.preview.gridTexture = nil, nil
+
+	if e.preview.err != nil {
+		return
+	}
+
+	e.updateSampleText()
+	e.updateGlyphGrid()
+}
+
+func (e *FontEditor) updateSampleText() {
+	if e.preview.renderer == nil {
+		return
+	}
+
+	img := e.preview.renderer.RenderText(e.preview.sampleText)
+	e.preview.sampleSize = img.Bounds().Size()
+	e.preview.missing = e.preview.renderer.MissingGlyphs(e.preview.sampleText)
+
+	e.textureLoader.CreateTextureFromARGB(img, func(t *g.Texture) {
+		e.preview.sampleTexture = t
+	})
+}
+
+func (e *FontEditor) updateGlyphGrid() {
+	if e.preview.renderer == nil {
+		return
+	}
+
+	img, cellW, cellH := e.preview.renderer.RenderGlyphGrid(glyphGridColumns)
+	e.preview.gridSize = img.Bounds().Size()
+	e.preview.gridCellSize = image.Point{X: cellW, Y: cellH}
+	e.preview.gridRunes = e.preview.renderer.Runes()
+
+	e.textureLoader.CreateTextureFromARGB(img, func(t *g.Texture) {
+		e.preview.gridTexture = t
+	})
+}
+
+func (e *FontEditor) makePreviewLayout() g.Layout {
+	if e.preview.err != nil {
+		return g.Layout{
+			g.Label(fmt.Sprintf("Unable to preview font: %v", e.preview.err)),
+			g.Button("Reload##FontEditorReloadPreview").OnClick(e.reloadPreview),
+		}
+	}
+
+	zoom := float32(e.preview.zoom)
+
+	return g.Layout{
+		g.Row(
+			g.Label("Zoom:"),
+			g.SliderInt("##FontEditorPreviewZoom", &e.preview.zoom, 1, maxZoom).Size(zoomW),
+			g.Button("Reload##FontEditorReloadPreview").OnClick(e.reloadPreview),
+		),
+		g.Label("Sample text (supports color codes ÿcN):"),
+		g.InputTextMultiline("##FontEditorSampleText", &e.preview.sampleText).
+			Size(-1, sampleTextH).OnChange(e.updateSampleText),
+		g.Custom(func() {
+			if len(e.preview.missing) == 0 {
+				return
+			}
+
+			chars := make([]string, len(e.preview.missing))
+			for idx, c := range e.preview.missing {
+				chars[idx] = fmt.Sprintf("%q", c)
+			}
+
+			g.Style().SetColor(imgui.StyleColorText, color.RGBA{R: 0xff, G: 0x50, B: 0x50, A: 0xff}).To(
+				g.Label("No glyph for: " + strings.Join(chars, ", ")),
+			).Build()
+		}),
+		g.Custom(func() {
+			if e.preview.sampleTexture == nil {
+				return
+			}
+
+			g.Image(e.preview.sampleTexture).
+				Size(float32(e.preview.sampleSize.X)*zoom, float32(e.preview.sampleSize.Y)*zoom).Build()
+		}),
+		g.Separator(),
+		g.Checkbox("Show all glyphs##FontEditorShowGrid", &e.preview.showGrid),
+		g.Custom(func() {
+			if !e.preview.showGrid || e.preview.gridTexture == nil {
+				return
+			}
+
+			g.Image(e.preview.gridTexture).
+				Size(float32(e.preview.gridSize.X)*zoom, float32(e.preview.gridSize.Y)*zoom).Build()
+
+			e.glyphGridTooltip(zoom)
+		}),
+	}
+}
+
+// glyphGridTooltip shows metrics of glyph hovered in glyph grid
+func (e *FontEditor) glyphGridTooltip(zoom float32) {
+	if !imgui.IsItemHovered() {
+		return
+	}
+
+	pos := imgui.MousePos().Minus(imgui.ItemRectMin())
+	column := int(pos.X / zoom / float32(e.preview.gridCellSize.X))
+	row := int(pos.Y / zoom / float32(e.preview.gridCellSize.Y))
+
+	idx := row*glyphGridColumns + column
+	if column >= glyphGridColumns || idx < 0 || idx >= len(e.preview.gridRunes) {
+		return
+	}
+
+	c := e.preview.gridRunes[idx]
+	glyph := e.preview.renderer.Table.Glyphs[c]
+
+	imgui.SetTooltip(fmt.Sprintf("%q (U+%04X)\nframe: %d\nwidth: %d, height: %d",
+		c, c, glyph.FrameIndex(), glyph.Width(), glyph.Height()))
+}
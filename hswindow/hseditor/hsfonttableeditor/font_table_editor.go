@@ -3,6 +3,7 @@ package hsfonttableeditor
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/OpenDiablo2/dialog"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2font"
 
 	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsfont"
 	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
 	"github.com/OpenDiablo2/HellSpawner/hsconfig"
 	"github.com/OpenDiablo2/HellSpawner/hswidget/fonttablewidget"
@@ -86,13 +88,24 @@ func (e *FontTableEditor) UpdateMainMenuLayout(l *g.Layout) {
 
 // GenerateSaveData generates data to be saved
 func (e *FontTableEditor) GenerateSaveData() []byte {
-	data := e.fontTable.Marshal()
+	data, err := hsfont.MarshalTable(e.fontTable)
+	if err != nil {
+		log.Print(err)
+
+		return nil
+	}
 
 	return data
 }
 
 // Save saves an editor
 func (e *FontTableEditor) Save() {
+	if _, err := hsfont.MarshalTable(e.fontTable); err != nil {
+		dialog.Message("%v", err).Error()
+
+		return
+	}
+
 	e.Editor.Save(e)
 }
 
@@ -0,0 +1,182 @@
+package hsstringtableeditor
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/OpenDiablo2/dialog"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsenum"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsutil"
+	"github.com/OpenDiablo2/HellSpawner/hswidget/stringtablewidget"
+)
+
+// localeTable is a string table of an other locale, displayed next to the edited one
+type localeTable struct {
+	*stringtablewidget.LocaleTable
+	path *hscommon.PathEntry
+}
+
+// splitLocalePath splits path like data\local\lng\eng\string.tbl into
+// prefix (data\local\lng\), locale and suffix (\string.tbl)
+func splitLocalePath(path string) (prefix string, locale hsenum.Locale, suffix string, found bool) {
+	lower := strings.ToLower(path)
+	start := -1
+
+	for l := hsenum.LocaleEnglish; l <= hsenum.LocalePolish; l++ {
+		for _, separator := range []string{`\`, "/"} {
+			if idx := strings.LastIndex(lower, separator+l.Code()+separator); idx > start {
+				start, locale = idx, l
+			}
+		}
+	}
+
+	if start < 0 {
+		return "", 0, "", false
+	}
+
+	// skip separator
+	start++
+
+	return path[:start], locale, path[start+len(locale.Code()):], true
+}
+
+// loadLocales loads the same string table for all other locales, which could be found
+// next to the edited table (in project directory or in the same MPQ)
+func (e *StringTableEditor) loadLocales() {
+	prefix, current, suffix, found := splitLocalePath(e.Path.FullPath)
+	if !found {
+		e.locales = nil
+		e.localesMessage = "Unable to determine locale of " + e.Path.FullPath +
+			" (string table should be placed in data\\local\\lng\\<locale>\\)"
+
+		return
+	}
+
+	e.locales = []*localeTable{
+		{
+			LocaleTable: &stringtablewidget.LocaleTable{Locale: current, Dict: e.dict},
+			path:        e.Path,
+		},
+	}
+
+	notFound := make([]string, 0)
+
+	for l := hsenum.LocaleEnglish; l <= hsenum.LocalePolish; l++ {
+		if l == current {
+			continue
+		}
+
+		path := &hscommon.PathEntry{
+			Name:     e.Path.Name,
+			FullPath: prefix + l.Code() + suffix,
+			Source:   e.Path.Source,
+			MPQFile:  e.Path.MPQFile,
+		}
+
+		data, err := path.GetFileBytes()
+		if err != nil {
+			notFound = append(notFound, l.String())
+
+			continue
+		}
+
+		dict, err := d2tbl.LoadTextDictionary(data)
+		if err != nil {
+			log.Printf("error loading string table %s: %v", path.FullPath, err)
+
+			notFound = append(notFound, l.String())
+
+			continue
+		}
+
+		e.locales = append(e.locales, &localeTable{
+			LocaleTable: &stringtablewidget.LocaleTable{Locale: l, Dict: dict},
+			path:        path,
+		})
+	}
+
+	messages := make([]string, 0)
+
+	if len(notFound) > 0 {
+		messages = append(messages, "Table not found for: "+strings.Join(notFound, ", "))
+	}
+
+	if e.Path.Source != hscommon.PathEntrySourceProject {
+		messages = append(messages, "Tables are read from MPQ; changed tables of other locales are saved into project")
+	}
+
+	e.localesMessage = strings.Join(messages, "\n")
+}
+
+// localeTables returns string tables displayed by multi-locale view
+func (e *StringTableEditor) localeTables() []*stringtablewidget.LocaleTable {
+	result := make([]*stringtablewidget.LocaleTable, len(e.locales))
+
+	for idx := range e.locales {
+		result[idx] = e.locales[idx].LocaleTable
+	}
+
+	return result
+}
+
+// changedLocales returns other locales' tables, which were changed
+// (the edited table is saved by hseditor.Editor)
+func (e *StringTableEditor) changedLocales() []*localeTable {
+	result := make([]*localeTable, 0)
+
+	for _, locale := range e.locales {
+		if locale.path != e.Path && locale.Changed {
+			result = append(result, locale)
+		}
+	}
+
+	return result
+}
+
+// saveLocales saves other locales' tables; tables of MPQs are saved into project,
+// the same way MPQ explorer copies files into project
+func (e *StringTableEditor) saveLocales() {
+	for _, locale := range e.changedLocales() {
+		data := locale.Dict.Marshal()
+
+		if locale.path.Source == hscommon.PathEntrySourceProject {
+			if err := locale.path.WriteFile(data); err != nil {
+				dialog.Message("failed to save string table %s: %v", locale.path.FullPath, err).Error()
+
+				continue
+			}
+
+			locale.Changed = false
+
+			continue
+		}
+
+		pathToFile := e.Project.ProjectPath(locale.path.FullPath)
+
+		if _, err := os.Stat(pathToFile); err == nil {
+			if !dialog.Message("%s already exists in project, overwrite it?", pathToFile).YesNo() {
+				continue
+			}
+		}
+
+		if !hsutil.CreateFileAtPath(pathToFile, data) {
+			dialog.Message("failed to save string table %s into project", locale.path.FullPath).Error()
+
+			continue
+		}
+
+		e.Project.InvalidateFileStructure()
+
+		locale.path = &hscommon.PathEntry{
+			Name:     locale.path.Name,
+			FullPath: pathToFile,
+			Source:   hscommon.PathEntrySourceProject,
+		}
+		locale.Changed = false
+	}
+}
@@ -0,0 +1,38 @@
+package hsstringtableeditor
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2tbl"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsenum"
+	"github.com/OpenDiablo2/HellSpawner/hswidget/stringtablewidget"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hseditor"
+)
+
+func TestChangedLocales(t *testing.T) {
+	path := &hscommon.PathEntry{FullPath: `data\local\lng\eng\string.tbl`}
+	e := &StringTableEditor{Editor: &hseditor.Editor{Path: path}, dict: d2tbl.TextDictionary{"a": "1"}}
+
+	locale := func(l hsenum.Locale) *localeTable {
+		return &localeTable{
+			LocaleTable: &stringtablewidget.LocaleTable{Locale: l, Dict: d2tbl.TextDictionary{"a": "1", "b": "2", "c": "3"}},
+			path:        &hscommon.PathEntry{FullPath: `data\local\lng\` + l.Code() + `\string.tbl`},
+		}
+	}
+
+	unchanged, edited := locale(hsenum.LocaleGerman), locale(hsenum.LocaleFrench)
+	edited.Changed = true
+
+	e.locales = []*localeTable{
+		{LocaleTable: &stringtablewidget.LocaleTable{Locale: hsenum.LocaleEnglish, Dict: e.dict, Changed: true}, path: path},
+		unchanged,
+		edited,
+	}
+
+	changed := e.changedLocales()
+	if len(changed) != 1 || changed[0] != edited {
+		t.Fatalf("expected only edited locale to be changed, got %d locales", len(changed))
+	}
+}
@@ -29,6 +29,11 @@ type StringTableEditor struct {
 	*hseditor.Editor
 	dict  d2tbl.TextDictionary
 	state []byte
+
+	// multiLocale is true, when table is displayed side-by-side with other locales
+	multiLocale    bool
+	locales        []*localeTable
+	localesMessage string
 }
 
 // Create creates a new string table editor
@@ -59,6 +64,21 @@ func Create(_ *hsconfig.Config,
 
 // Build builds an editor
 func (e *StringTableEditor) Build() {
+	if e.multiLocale {
+		e.IsOpen(&e.Visible).
+			Flags(g.WindowFlagsHorizontalScrollbar).
+			Layout(g.Layout{
+				g.Custom(func() {
+					if e.localesMessage != "" {
+						g.Label(e.localesMessage).Build()
+					}
+				}),
+				stringtablewidget.Create(e.state, e.Path.GetUniqueID(), e.dict, e.localeTables()...),
+			})
+
+		return
+	}
+
 	l := stringtablewidget.Create(e.state, e.Path.GetUniqueID(), e.dict)
 
 	e.IsOpen(&e.Visible).
@@ -71,6 +91,8 @@ func (e *StringTableEditor) UpdateMainMenuLayout(l *g.Layout) {
 	m := g.Menu("String Table Editor").Layout(g.Layout{
 		g.MenuItem("Save\t\t\t\tCtrl+Shift+S").OnClick(e.Save),
 		g.Separator(),
+		g.MenuItem("Side-by-side locales view").Selected(e.multiLocale).OnClick(e.toggleMultiLocale),
+		g.Separator(),
 		g.MenuItem("Add to project").OnClick(func() {}),
 		g.MenuItem("Remove from project").OnClick(func() {}),
 		g.Separator(),
@@ -85,6 +107,14 @@ func (e *StringTableEditor) UpdateMainMenuLayout(l *g.Layout) {
 	*l = append(*l, m)
 }
 
+func (e *StringTableEditor) toggleMultiLocale() {
+	e.multiLocale = !e.multiLocale
+
+	if e.multiLocale && e.locales == nil {
+		e.loadLocales()
+	}
+}
+
 // GenerateSaveData generates data to be saved
 func (e *StringTableEditor) GenerateSaveData() []byte {
 	data := e.dict.Marshal()
@@ -95,11 +125,12 @@ func (e *StringTableEditor) GenerateSaveData() []byte {
 // Save saves an editor
 func (e *StringTableEditor) Save() {
 	e.Editor.Save(e)
+	e.saveLocales()
 }
 
 // Cleanup hides an editor
 func (e *StringTableEditor) Cleanup() {
-	if e.HasChanges(e) {
+	if e.HasChanges(e) || len(e.changedLocales()) > 0 {
 		if shouldSave := dialog.Message("There are unsaved changes to %s, save before closing this editor?",
 			e.Path.FullPath).YesNo(); shouldSave {
 			e.Save()
@@ -0,0 +1,436 @@
+// Package hstilesetbrowser contains a tool window, which indexes DT1 files of project and auxiliary MPQs
+// and shows their tiles grouped by type, style and sequence.
+package hstilesetbrowser
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	g "github.com/ianling/giu"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+
+	"github.com/OpenDiablo2/HellSpawner/hscommon"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hsdt1"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsfiletypes/hspalette"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsproject"
+	"github.com/OpenDiablo2/HellSpawner/hscommon/hsstate"
+	"github.com/OpenDiablo2/HellSpawner/hsconfig"
+	"github.com/OpenDiablo2/HellSpawner/hswidget/dt1widget"
+	"github.com/OpenDiablo2/HellSpawner/hswindow/hstoolwindow"
+)
+
+const (
+	mainWindowW, mainWindowH = 400, 500
+	comboW                   = 100
+	inputW                   = 40
+	// thumbnails are shown at half of tile's size
+	thumbnailScale = 2
+	numTileTypes   = int(d2enum.TileLowerWallsEquivalentToSouthCornerwall) + 1
+	palettePath    = `data\global\palette\act%d\pal.dat`
+)
+
+// act folders of data\global\tiles and acts, whose palettes are used to draw them
+type tilesFolder struct {
+	name   string
+	folder string
+	act    int
+}
+
+func tilesFolders() []tilesFolder {
+	return []tilesFolder{
+		{"All acts", "", 1},
+		{"ACT1", "act1", 1},
+		{"ACT2", "act2", 2},
+		{"ACT3", "act3", 3},
+		{"ACT4", "act4", 4},
+		{"ACT5", "act5", 5},
+		{"Expansion", "expansion", 5},
+	}
+}
+
+// TileSelectedCallback opens DT1 file at tile group given
+type TileSelectedCallback func(path *hscommon.PathEntry, tileGroup int32)
+
+type tileKey struct {
+	Type     int32
+	Style    int32
+	Sequence int32
+}
+
+// indexedTile is a tile of an indexed DT1 file
+type indexedTile struct {
+	file  int
+	index int
+}
+
+// tileIndex is a result of indexing DT1 files; it isn't changed after it was built
+type tileIndex struct {
+	files   []hsproject.DT1File
+	dt1s    []*d2dt1.DT1
+	keys    []tileKey
+	tiles   map[tileKey][]indexedTile
+	palette *[256]color.RGBA
+}
+
+func newTileIndex() *tileIndex {
+	return &tileIndex{tiles: make(map[tileKey][]indexedTile)}
+}
+
+// TilesetBrowser represents a tileset browser
+type TilesetBrowser struct {
+	*hstoolwindow.ToolWindow
+	config               *hsconfig.Config
+	project              *hsproject.Project
+	textureLoader        hscommon.TextureLoader
+	tileSelectedCallback TileSelectedCallback
+
+	folder     int32
+	tileType   int32
+	style      string
+	sequence   string
+	fileFilter string
+
+	// files are indexed and textures are created in background, so fields below are guarded by mutex
+	mutex    sync.Mutex
+	index    *tileIndex
+	textures map[indexedTile]*g.Texture
+	indexing bool
+	// generation changes, when the index is cleared, so that results of previous indexing are dropped
+	generation int
+	message    string
+}
+
+// Create creates a new tileset browser
+func Create(textureLoader hscommon.TextureLoader, tileSelectedCallback TileSelectedCallback, config *hsconfig.Config,
+	x, y float32) *TilesetBrowser {
+	result := &TilesetBrowser{
+		ToolWindow:           hstoolwindow.New("Tileset Browser", hsstate.ToolWindowTypeTilesetBrowser, x, y),
+		config:               config,
+		textureLoader:        textureLoader,
+		tileSelectedCallback: tileSelectedCallback,
+	}
+
+	result.Reset()
+
+	if w, h := result.CurrentSize(); w == 0 || h == 0 {
+		result.Size(mainWindowW, mainWindowH)
+	}
+
+	return result
+}
+
+// SetProject sets browser's project
+func (t *TilesetBrowser) SetProject(project *hsproject.Project) {
+	t.project = project
+	t.Reset()
+}
+
+// Reset clears the index (e.g. when auxiliary MPQs are reloaded)
+func (t *TilesetBrowser) Reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.clear()
+	t.indexing = false
+	t.message = "Select an act and press Index to list its tiles"
+}
+
+// clear clears the index; it is called with mutex locked
+func (t *TilesetBrowser) clear() {
+	t.index = newTileIndex()
+	t.textures = make(map[indexedTile]*g.Texture)
+	t.generation++
+}
+
+// Build builds a browser
+func (t *TilesetBrowser) Build() {
+	if t.project == nil {
+		return
+	}
+
+	folders := tilesFolders()
+	folderNames := make([]string, len(folders))
+
+	for idx := range folders {
+		folderNames[idx] = folders[idx].name
+	}
+
+	typeNames := make([]string, numTileTypes+1)
+	typeNames[0] = "Any type"
+
+	for idx := 0; idx < numTileTypes; idx++ {
+		typeNames[idx+1] = fmt.Sprintf("%d: %s", idx, d2enum.TileType(idx))
+	}
+
+	t.mutex.Lock()
+	index, indexing, message := t.index, t.indexing, t.message
+	t.mutex.Unlock()
+
+	indexButton := g.Widget(g.Button("Index##TilesetBrowserIndex").OnClick(t.startIndexing))
+	if indexing {
+		indexButton = g.Label("Indexing...")
+	}
+
+	t.IsOpen(&t.Visible).
+		Layout(g.Layout{
+			g.Row(
+				g.Combo("##TilesetBrowserFolder", folderNames[t.folder], folderNames, &t.folder).Size(comboW),
+				indexButton,
+				g.Label(message),
+			),
+			g.Row(
+				g.Combo("##TilesetBrowserType", typeNames[t.tileType], typeNames, &t.tileType).Size(comboW),
+				g.Label("Style"),
+				g.InputText("##TilesetBrowserStyle", &t.style).Size(inputW),
+				g.Label("Sequence"),
+				g.InputText("##TilesetBrowserSequence", &t.sequence).Size(inputW),
+			),
+			g.InputText("File##TilesetBrowserFile", &t.fileFilter),
+			g.Separator(),
+			g.Child("TilesetBrowserContent").
+				Border(false).
+				Flags(g.WindowFlagsHorizontalScrollbar).
+				Layout(t.makeTileTree(index)...),
+		})
+}
+
+// startIndexing starts loading all DT1 files of selected folder in background
+func (t *TilesetBrowser) startIndexing() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.indexing {
+		return
+	}
+
+	t.clear()
+	t.indexing = true
+	t.message = "Looking for DT1 files..."
+
+	go t.buildIndex(t.project, tilesFolders()[t.folder], t.generation)
+}
+
+// setProgress sets browser's message and, when indexing is done, the index, unless
+// the index was cleared in the meantime
+func (t *TilesetBrowser) setProgress(generation int, message string, index *tileIndex) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if generation != t.generation {
+		return
+	}
+
+	t.message = message
+
+	if index != nil {
+		t.index = index
+		t.indexing = false
+	}
+}
+
+// buildIndex loads all DT1 files of project's folder given; it runs in background
+func (t *TilesetBrowser) buildIndex(project *hsproject.Project, folder tilesFolder, generation int) {
+	index := newTileIndex()
+
+	files, err := project.ScanDT1s(t.config, folder.folder)
+	if err != nil {
+		t.setProgress(generation, err.Error(), index)
+
+		return
+	}
+
+	for fileNumber, file := range files {
+		t.setProgress(generation, fmt.Sprintf("%d/%d: %s", fileNumber+1, len(files), file.Path), nil)
+
+		data, err := project.ReadFile(file.Path)
+		if err != nil {
+			log.Print(err)
+
+			continue
+		}
+
+		dt1, err := d2dt1.LoadDT1(data)
+		if err != nil {
+			log.Printf("error loading %s: %v", file.Path, err)
+
+			continue
+		}
+
+		fileIdx := len(index.files)
+		index.files = append(index.files, file)
+		index.dt1s = append(index.dt1s, dt1)
+
+		for idx := range dt1.Tiles {
+			tile := &dt1.Tiles[idx]
+			key := tileKey{Type: tile.Type, Style: tile.Style, Sequence: tile.Sequence}
+
+			if _, found := index.tiles[key]; !found {
+				index.keys = append(index.keys, key)
+			}
+
+			index.tiles[key] = append(index.tiles[key], indexedTile{file: fileIdx, index: idx})
+		}
+	}
+
+	sort.Slice(index.keys, func(i, j int) bool {
+		a, b := index.keys[i], index.keys[j]
+
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+
+		if a.Style != b.Style {
+			return a.Style < b.Style
+		}
+
+		return a.Sequence < b.Sequence
+	})
+
+	if data, err := project.ReadFile(fmt.Sprintf(palettePath, folder.act)); err == nil {
+		if pal, err := d2dat.Load(data); err == nil {
+			colors := pal.GetColors()
+			index.palette = hspalette.Colors(&colors)
+		}
+	}
+
+	message := fmt.Sprintf("%d file(s), %d tile key(s)", len(index.files), len(index.keys))
+
+	if index.palette == nil {
+		message += fmt.Sprintf(", palette of act %d not found", folder.act)
+	}
+
+	t.setProgress(generation, message, index)
+}
+
+// matchesNumber returns true if filter is empty or it is the number given
+func matchesNumber(filter string, n int32) bool {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return true
+	}
+
+	value, err := strconv.Atoi(filter)
+
+	return err == nil && int32(value) == n
+}
+
+// filteredTiles returns tiles of key matching search fields
+func (t *TilesetBrowser) filteredTiles(index *tileIndex, key tileKey) []indexedTile {
+	if (t.tileType > 0 && key.Type != t.tileType-1) || !matchesNumber(t.style, key.Style) ||
+		!matchesNumber(t.sequence, key.Sequence) {
+		return nil
+	}
+
+	fileFilter := strings.ToLower(strings.TrimSpace(t.fileFilter))
+	if fileFilter == "" {
+		return index.tiles[key]
+	}
+
+	result := make([]indexedTile, 0)
+
+	for _, tile := range index.tiles[key] {
+		if strings.Contains(strings.ToLower(index.files[tile.file].Path), fileFilter) {
+			result = append(result, tile)
+		}
+	}
+
+	return result
+}
+
+// makeTileTree creates tree of types, styles and sequences; tiles are drawn only, when their node is open
+func (t *TilesetBrowser) makeTileTree(index *tileIndex) []g.Widget {
+	result := make([]g.Widget, 0)
+
+	for keyIdx := 0; keyIdx < len(index.keys); {
+		tileType := index.keys[keyIdx].Type
+		nodes := g.Layout{}
+		numTiles := 0
+
+		for ; keyIdx < len(index.keys) && index.keys[keyIdx].Type == tileType; keyIdx++ {
+			key := index.keys[keyIdx]
+
+			tiles := t.filteredTiles(index, key)
+			if len(tiles) == 0 {
+				continue
+			}
+
+			numTiles += len(tiles)
+			label := fmt.Sprintf("style %d, sequence %d: %d tile(s)##TilesetBrowser%d_%d_%d",
+				key.Style, key.Sequence, len(tiles), key.Type, key.Style, key.Sequence)
+			nodes = append(nodes, g.TreeNode(label).Layout(t.makeTilesLayout(index, tiles)))
+		}
+
+		if len(nodes) == 0 {
+			continue
+		}
+
+		label := fmt.Sprintf("%d: %s (%d tile(s))##TilesetBrowserType%d", tileType, d2enum.TileType(tileType), numTiles, tileType)
+		result = append(result, g.TreeNode(label).Layout(nodes))
+	}
+
+	return result
+}
+
+func (t *TilesetBrowser) makeTilesLayout(index *tileIndex, tiles []indexedTile) g.Layout {
+	result := g.Layout{}
+
+	for _, tile := range tiles {
+		result = append(result, t.makeTileWidget(index, tile))
+	}
+
+	return result
+}
+
+// texture returns tile's texture; the texture is requested, if it wasn't requested yet
+func (t *TilesetBrowser) texture(index *tileIndex, tile indexedTile, dt1Tile *d2dt1.Tile) *g.Texture {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// texture loader's callback stores the texture into textures of this index,
+	// even if the index was cleared in the meantime
+	textures := t.textures
+
+	texture, requested := textures[tile]
+	if requested || index.palette == nil || hsdt1.Bounds(dt1Tile).Empty() {
+		return texture
+	}
+
+	textures[tile] = nil
+
+	t.textureLoader.CreateTextureFromARGB(hsdt1.Image(dt1Tile, index.palette), func(tex *g.Texture) {
+		t.mutex.Lock()
+		textures[tile] = tex
+		t.mutex.Unlock()
+	})
+
+	return nil
+}
+
+// makeTileWidget creates tile's thumbnail (texture is created, when the widget is built
+// for the first time) and a label, which opens tile's DT1 file
+func (t *TilesetBrowser) makeTileWidget(index *tileIndex, tile indexedTile) g.Widget {
+	return g.Custom(func() {
+		file := index.files[tile.file]
+		dt1 := index.dt1s[tile.file]
+		dt1Tile := &dt1.Tiles[tile.index]
+		bounds := hsdt1.Bounds(dt1Tile)
+
+		label := fmt.Sprintf("%s, tile %d (rarity %d)##TilesetBrowserTile%d_%d",
+			file.Path, tile.index, dt1Tile.RarityFrameIndex, tile.file, tile.index)
+
+		g.Layout{
+			g.Image(t.texture(index, tile, dt1Tile)).Size(float32(bounds.Dx()/thumbnailScale), float32(bounds.Dy()/thumbnailScale)),
+			g.Selectable(label).OnClick(func() {
+				t.tileSelectedCallback(file.Entry, dt1widget.TileGroupOf(dt1, tile.index))
+			}),
+		}.Build()
+	})
+}